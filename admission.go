@@ -0,0 +1,332 @@
+package gocb
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// AdmissionMode specifies what happens to an operation when the admission control limits for its service have been
+// reached.
+// VOLATILE: This API is subject to change at any time.
+type AdmissionMode uint
+
+const (
+	// AdmissionModeQueue indicates that operations will wait for capacity, for at most the operation timeout.
+	AdmissionModeQueue AdmissionMode = iota
+
+	// AdmissionModeReject indicates that operations will immediately fail with ErrAdmissionRejected.
+	AdmissionModeReject
+)
+
+// ServiceAdmissionConfig specifies the admission control limits for a single service.
+// VOLATILE: This API is subject to change at any time.
+type ServiceAdmissionConfig struct {
+	// MaxInFlight is the maximum number of operations that can be in flight at once, 0 means unlimited.
+	// For streaming services an operation is in flight until the initial response has been received.
+	MaxInFlight uint32
+
+	// RateLimit is the maximum number of operations that can be started per second, 0 means unlimited.
+	RateLimit float64
+
+	// RateBurst is the number of operations that can be started at once before RateLimit applies, defaults to
+	// RateLimit rounded up.
+	RateBurst uint32
+}
+
+// AdmissionConfig specifies options for client side admission control, which limits the number of operations
+// that the SDK will dispatch to each service.
+// VOLATILE: This API is subject to change at any time.
+type AdmissionConfig struct {
+	Mode AdmissionMode
+
+	KV         ServiceAdmissionConfig
+	Query      ServiceAdmissionConfig
+	Search     ServiceAdmissionConfig
+	Analytics  ServiceAdmissionConfig
+	Management ServiceAdmissionConfig
+}
+
+type admissionController struct {
+	kv         *admissionLimiter
+	query      *admissionLimiter
+	search     *admissionLimiter
+	analytics  *admissionLimiter
+	management *admissionLimiter
+}
+
+func newAdmissionController(config AdmissionConfig) *admissionController {
+	return &admissionController{
+		kv:         newAdmissionLimiter(ServiceTypeKeyValue, config.Mode, config.KV),
+		query:      newAdmissionLimiter(ServiceTypeQuery, config.Mode, config.Query),
+		search:     newAdmissionLimiter(ServiceTypeSearch, config.Mode, config.Search),
+		analytics:  newAdmissionLimiter(ServiceTypeAnalytics, config.Mode, config.Analytics),
+		management: newAdmissionLimiter(ServiceTypeManagement, config.Mode, config.Management),
+	}
+}
+
+// forService returns the limiter for a service, or nil if the service is not limited.
+func (ac *admissionController) forService(service ServiceType) *admissionLimiter {
+	if ac == nil {
+		return nil
+	}
+
+	switch service {
+	case ServiceTypeKeyValue:
+		return ac.kv
+	case ServiceTypeQuery:
+		return ac.query
+	case ServiceTypeSearch:
+		return ac.search
+	case ServiceTypeAnalytics:
+		return ac.analytics
+	case ServiceTypeManagement, ServiceTypeEventing:
+		return ac.management
+	}
+
+	return nil
+}
+
+func (ac *admissionController) enabled() bool {
+	return ac != nil && (ac.kv != nil || ac.query != nil || ac.search != nil || ac.analytics != nil ||
+		ac.management != nil)
+}
+
+// admissionLimiter limits the operations for a single service, all methods are safe to call on a nil limiter.
+type admissionLimiter struct {
+	service ServiceType
+	mode    AdmissionMode
+
+	slots chan struct{}
+
+	rate       float64
+	burst      float64
+	lock       sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newAdmissionLimiter(service ServiceType, mode AdmissionMode, config ServiceAdmissionConfig) *admissionLimiter {
+	if config.MaxInFlight == 0 && config.RateLimit <= 0 {
+		return nil
+	}
+
+	limiter := &admissionLimiter{
+		service: service,
+		mode:    mode,
+	}
+
+	if config.MaxInFlight > 0 {
+		limiter.slots = make(chan struct{}, config.MaxInFlight)
+	}
+
+	if config.RateLimit > 0 {
+		burst := float64(config.RateBurst)
+		if burst == 0 {
+			burst = math.Ceil(config.RateLimit)
+		}
+
+		limiter.rate = config.RateLimit
+		limiter.burst = burst
+		limiter.tokens = burst
+		limiter.lastRefill = time.Now()
+	}
+
+	return limiter
+}
+
+// Acquire admits an operation, depending on the mode it will either wait until the deadline for capacity or fail
+// immediately. Release must be called once the operation is no longer in flight if Acquire returns nil.
+// The in flight slot is acquired before the rate limit token so that operations which are not admitted due to the
+// in flight limit do not use up the rate limit, the slot is released again if the rate limit then fails.
+func (al *admissionLimiter) Acquire(ctx context.Context, deadline time.Time) error {
+	if al == nil {
+		return nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := al.acquireSlot(ctx, deadline); err != nil {
+		return err
+	}
+
+	if al.rate > 0 {
+		if err := al.acquireRate(ctx, deadline); err != nil {
+			al.Release()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Release marks an operation admitted by Acquire as no longer being in flight.
+func (al *admissionLimiter) Release() {
+	if al == nil || al.slots == nil {
+		return
+	}
+
+	<-al.slots
+}
+
+func (al *admissionLimiter) acquireSlot(ctx context.Context, deadline time.Time) error {
+	if al.slots == nil {
+		return nil
+	}
+
+	select {
+	case al.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if al.mode == AdmissionModeReject {
+		return al.rejectedError("max_in_flight")
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case al.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return al.timeoutError("max_in_flight")
+	case <-ctx.Done():
+		return al.contextError(ctx)
+	}
+}
+
+func (al *admissionLimiter) acquireRate(ctx context.Context, deadline time.Time) error {
+	now := time.Now()
+
+	al.lock.Lock()
+	elapsed := now.Sub(al.lastRefill)
+	if elapsed > 0 {
+		al.tokens = math.Min(al.burst, al.tokens+elapsed.Seconds()*al.rate)
+		al.lastRefill = now
+	}
+
+	if al.tokens >= 1 {
+		al.tokens--
+		al.lock.Unlock()
+		return nil
+	}
+
+	wait := time.Duration((1 - al.tokens) / al.rate * float64(time.Second))
+	if al.mode == AdmissionModeReject {
+		al.lock.Unlock()
+		return al.rejectedError("rate_limit")
+	}
+	if now.Add(wait).After(deadline) {
+		al.lock.Unlock()
+		return al.timeoutError("rate_limit")
+	}
+
+	// Reserve the token that will be available once we've waited.
+	al.tokens--
+	al.lock.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		al.lock.Lock()
+		al.tokens++
+		al.lock.Unlock()
+		return al.contextError(ctx)
+	}
+}
+
+func (al *admissionLimiter) rejectedError(reason string) error {
+	return makeGenericError(ErrAdmissionRejected, map[string]interface{}{
+		"service": serviceTypeToString(al.service),
+		"reason":  reason,
+	})
+}
+
+func (al *admissionLimiter) timeoutError(reason string) error {
+	return makeGenericError(ErrUnambiguousTimeout, map[string]interface{}{
+		"service":   serviceTypeToString(al.service),
+		"admission": reason,
+	})
+}
+
+func (al *admissionLimiter) contextError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return al.timeoutError("context_deadline")
+	}
+
+	return makeGenericError(ErrRequestCanceled, map[string]interface{}{
+		"service": serviceTypeToString(al.service),
+	})
+}
+
+// bulkAdmission applies key-value admission control to the ops of a bulk operation. Each op must be admitted before
+// it is dispatched, and holds its slot until it signals completion.
+type bulkAdmission struct {
+	limiter  *admissionLimiter
+	ctx      context.Context
+	deadline time.Time
+
+	signal     chan BulkOp
+	dispatched chan BulkOp
+}
+
+func newBulkAdmission(ctx context.Context, limiter *admissionLimiter, deadline time.Time,
+	signal chan BulkOp) *bulkAdmission {
+	ba := &bulkAdmission{
+		limiter:  limiter,
+		ctx:      ctx,
+		deadline: deadline,
+		signal:   signal,
+	}
+	if limiter == nil {
+		return ba
+	}
+
+	// Slots must be released as ops complete rather than as they are collected, otherwise dispatching would
+	// wait on slots which can't be released until dispatching has finished.
+	ba.dispatched = make(chan BulkOp, cap(signal))
+	go func() {
+		for item := range ba.dispatched {
+			limiter.Release()
+			signal <- item
+		}
+	}()
+
+	return ba
+}
+
+// opSignal returns the channel that admitted ops must signal completion on.
+func (ba *bulkAdmission) opSignal() chan BulkOp {
+	if ba.dispatched == nil {
+		return ba.signal
+	}
+
+	return ba.dispatched
+}
+
+// admit admits an op, if the op is not admitted then it is failed and signalled as complete and false is returned.
+func (ba *bulkAdmission) admit(item BulkOp) bool {
+	if err := ba.limiter.Acquire(ba.ctx, ba.deadline); err != nil {
+		item.setErr(err)
+		ba.signal <- item
+		return false
+	}
+
+	return true
+}
+
+// close must be called once all of the ops have completed.
+func (ba *bulkAdmission) close() {
+	if ba.dispatched != nil {
+		close(ba.dispatched)
+	}
+}
@@ -0,0 +1,73 @@
+package gocb
+
+import (
+	"context"
+
+	gocbcore "github.com/couchbase/gocbcore/v10"
+)
+
+type admissionQueryProviderCore struct {
+	provider queryProviderCoreProvider
+	limiter  *admissionLimiter
+}
+
+func (ap *admissionQueryProviderCore) N1QLQuery(ctx context.Context, opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	if err := ap.limiter.Acquire(ctx, opts.Deadline); err != nil {
+		return nil, err
+	}
+	defer ap.limiter.Release()
+
+	return ap.provider.N1QLQuery(ctx, opts)
+}
+
+func (ap *admissionQueryProviderCore) PreparedN1QLQuery(ctx context.Context, opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	if err := ap.limiter.Acquire(ctx, opts.Deadline); err != nil {
+		return nil, err
+	}
+	defer ap.limiter.Release()
+
+	return ap.provider.PreparedN1QLQuery(ctx, opts)
+}
+
+type admissionSearchProviderCore struct {
+	provider searchProviderCoreProvider
+	limiter  *admissionLimiter
+}
+
+func (ap *admissionSearchProviderCore) SearchQuery(ctx context.Context, opts gocbcore.SearchQueryOptions) (searchRowReader, error) {
+	if err := ap.limiter.Acquire(ctx, opts.Deadline); err != nil {
+		return nil, err
+	}
+	defer ap.limiter.Release()
+
+	return ap.provider.SearchQuery(ctx, opts)
+}
+
+type admissionAnalyticsProviderCore struct {
+	provider analyticsProviderCoreProvider
+	limiter  *admissionLimiter
+}
+
+func (ap *admissionAnalyticsProviderCore) AnalyticsQuery(ctx context.Context, opts gocbcore.AnalyticsQueryOptions) (analyticsRowReader, error) {
+	if err := ap.limiter.Acquire(ctx, opts.Deadline); err != nil {
+		return nil, err
+	}
+	defer ap.limiter.Release()
+
+	return ap.provider.AnalyticsQuery(ctx, opts)
+}
+
+type admissionHTTPProviderCore struct {
+	provider  httpProvider
+	admission *admissionController
+}
+
+func (ap *admissionHTTPProviderCore) DoHTTPRequest(ctx context.Context, req *gocbcore.HTTPRequest) (*gocbcore.HTTPResponse, error) {
+	limiter := ap.admission.forService(ServiceType(req.Service))
+	if err := limiter.Acquire(ctx, req.Deadline); err != nil {
+		return nil, err
+	}
+	defer limiter.Release()
+
+	return ap.provider.DoHTTPRequest(ctx, req)
+}
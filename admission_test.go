@@ -0,0 +1,208 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestAdmissionLimiterDisabled() {
+	controller := newAdmissionController(AdmissionConfig{
+		Query: ServiceAdmissionConfig{MaxInFlight: 1},
+	})
+
+	suite.Assert().Nil(controller.forService(ServiceTypeKeyValue))
+	suite.Assert().NotNil(controller.forService(ServiceTypeQuery))
+
+	var limiter *admissionLimiter
+	suite.Require().NoError(limiter.Acquire(context.Background(), time.Now()))
+	limiter.Release()
+}
+
+func (suite *UnitTestSuite) TestAdmissionLimiterRejectsInFlight() {
+	limiter := newAdmissionLimiter(ServiceTypeQuery, AdmissionModeReject, ServiceAdmissionConfig{MaxInFlight: 1})
+
+	deadline := time.Now().Add(time.Second)
+	suite.Require().NoError(limiter.Acquire(context.Background(), deadline))
+
+	err := limiter.Acquire(context.Background(), deadline)
+	suite.Require().ErrorIs(err, ErrAdmissionRejected)
+
+	limiter.Release()
+	suite.Require().NoError(limiter.Acquire(context.Background(), deadline))
+	limiter.Release()
+}
+
+func (suite *UnitTestSuite) TestAdmissionLimiterQueuesInFlight() {
+	limiter := newAdmissionLimiter(ServiceTypeKeyValue, AdmissionModeQueue, ServiceAdmissionConfig{MaxInFlight: 1})
+
+	suite.Require().NoError(limiter.Acquire(context.Background(), time.Now().Add(time.Second)))
+
+	err := limiter.Acquire(context.Background(), time.Now().Add(20*time.Millisecond))
+	suite.Require().ErrorIs(err, ErrUnambiguousTimeout)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		limiter.Release()
+	}()
+
+	suite.Require().NoError(limiter.Acquire(context.Background(), time.Now().Add(time.Second)))
+	limiter.Release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	suite.Require().NoError(limiter.Acquire(ctx, time.Now().Add(time.Second)))
+	err = limiter.Acquire(ctx, time.Now().Add(time.Second))
+	suite.Require().ErrorIs(err, ErrRequestCanceled)
+}
+
+func (suite *UnitTestSuite) TestAdmissionLimiterRateLimit() {
+	limiter := newAdmissionLimiter(ServiceTypeSearch, AdmissionModeReject, ServiceAdmissionConfig{
+		RateLimit: 10,
+		RateBurst: 2,
+	})
+
+	deadline := time.Now().Add(time.Second)
+	suite.Require().NoError(limiter.Acquire(context.Background(), deadline))
+	suite.Require().NoError(limiter.Acquire(context.Background(), deadline))
+	suite.Require().ErrorIs(limiter.Acquire(context.Background(), deadline), ErrAdmissionRejected)
+
+	time.Sleep(110 * time.Millisecond)
+	suite.Require().NoError(limiter.Acquire(context.Background(), deadline))
+
+	queued := newAdmissionLimiter(ServiceTypeSearch, AdmissionModeQueue, ServiceAdmissionConfig{
+		RateLimit: 20,
+		RateBurst: 1,
+	})
+	suite.Require().NoError(queued.Acquire(context.Background(), deadline))
+
+	start := time.Now()
+	suite.Require().NoError(queued.Acquire(context.Background(), time.Now().Add(time.Second)))
+	suite.Assert().GreaterOrEqual(time.Since(start), 40*time.Millisecond)
+
+	err := queued.Acquire(context.Background(), time.Now().Add(time.Millisecond))
+	suite.Require().ErrorIs(err, ErrUnambiguousTimeout)
+}
+
+func (suite *UnitTestSuite) TestAdmissionLimiterRejectedInFlightKeepsRateLimit() {
+	limiter := newAdmissionLimiter(ServiceTypeQuery, AdmissionModeReject, ServiceAdmissionConfig{
+		MaxInFlight: 1,
+		RateLimit:   1,
+		RateBurst:   2,
+	})
+	// Prevent refills interfering with the test.
+	limiter.lastRefill = time.Now().Add(time.Hour)
+
+	deadline := time.Now().Add(time.Second)
+	suite.Require().NoError(limiter.Acquire(context.Background(), deadline))
+
+	// Rejected due to the in flight limit, this must not use up the remaining rate limit token.
+	for i := 0; i < 3; i++ {
+		suite.Require().ErrorIs(limiter.Acquire(context.Background(), deadline), ErrAdmissionRejected)
+	}
+	limiter.Release()
+
+	suite.Require().NoError(limiter.Acquire(context.Background(), deadline))
+	limiter.Release()
+
+	// Rejected due to the rate limit, this must not hold on to the in flight slot.
+	suite.Require().ErrorIs(limiter.Acquire(context.Background(), deadline), ErrAdmissionRejected)
+	suite.Assert().Len(limiter.slots, 0)
+}
+
+func (suite *UnitTestSuite) TestQueryAdmissionRejected() {
+	retErr := errors.New("an error")
+	blockCh := make(chan struct{})
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			<-blockCh
+		}).
+		Return(nil, retErr).
+		Once()
+
+	limiter := newAdmissionLimiter(ServiceTypeQuery, AdmissionModeReject, ServiceAdmissionConfig{MaxInFlight: 1})
+
+	queryProvider := &queryProviderCore{
+		provider: &admissionQueryProviderCore{provider: provider, limiter: limiter},
+	}
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+
+	cluster := suite.newCluster(cli)
+
+	queryProvider.meter = cluster.meter
+	queryProvider.tracer = cluster.tracer
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{
+			Adhoc: true,
+		})
+		errCh <- err
+	}()
+
+	suite.Require().Eventually(func() bool {
+		return len(limiter.slots) == 1
+	}, time.Second, time.Millisecond)
+
+	_, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{
+		Adhoc: true,
+	})
+	suite.Require().ErrorIs(err, ErrAdmissionRejected)
+
+	close(blockCh)
+	suite.Require().Equal(retErr, <-errCh)
+	suite.Assert().Len(limiter.slots, 0)
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestBulkOpsAdmission() {
+	var inFlight, maxInFlight int32
+	agent := new(mockKvProviderCoreProvider)
+	agent.On("Get", mock.AnythingOfType("gocbcore.GetOptions"), mock.AnythingOfType("gocbcore.GetCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.GetCallback)
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+					break
+				}
+			}
+
+			go func() {
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				cb(&gocbcore.GetResult{Value: []byte(`{}`)}, nil)
+			}()
+		}).
+		Return(new(mockPendingOp), nil)
+
+	col := suite.collection("default", "_default", "_default", nil)
+	col.admission = newAdmissionController(AdmissionConfig{
+		KV: ServiceAdmissionConfig{MaxInFlight: 1},
+	})
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{agent: agent}, nil
+	}
+
+	var ops []BulkOp
+	for i := 0; i < 5; i++ {
+		ops = append(ops, &GetOp{ID: "key"})
+	}
+	suite.Require().NoError(col.Do(ops, nil))
+
+	for _, op := range ops {
+		suite.Assert().NoError(op.(*GetOp).Err)
+	}
+	suite.Assert().Equal(int32(1), atomic.LoadInt32(&maxInFlight))
+	agent.AssertNumberOfCalls(suite.T(), "Get", 5)
+}
@@ -0,0 +1,71 @@
+package gocb
+
+// analyticsIndexProviderPs is used when connected with couchbase2. Dataverse, dataset, index and link connection
+// management are performed using analytics queries, which couchbase2 supports, so are delegated to the core provider.
+// The operations which use the analytics management HTTP API have no equivalent so are not supported.
+// The core provider has no management provider so must only be delegated to for operations which use queries.
+type analyticsIndexProviderPs struct {
+	core *analyticsIndexProviderCore
+}
+
+var _ analyticsIndexProvider = &analyticsIndexProviderPs{}
+
+func (am *analyticsIndexProviderPs) CreateDataverse(dataverseName string, opts *CreateAnalyticsDataverseOptions) error {
+	return am.core.CreateDataverse(dataverseName, opts)
+}
+
+func (am *analyticsIndexProviderPs) DropDataverse(dataverseName string, opts *DropAnalyticsDataverseOptions) error {
+	return am.core.DropDataverse(dataverseName, opts)
+}
+
+func (am *analyticsIndexProviderPs) CreateDataset(datasetName, bucketName string, opts *CreateAnalyticsDatasetOptions) error {
+	return am.core.CreateDataset(datasetName, bucketName, opts)
+}
+
+func (am *analyticsIndexProviderPs) DropDataset(datasetName string, opts *DropAnalyticsDatasetOptions) error {
+	return am.core.DropDataset(datasetName, opts)
+}
+
+func (am *analyticsIndexProviderPs) GetAllDatasets(opts *GetAllAnalyticsDatasetsOptions) ([]AnalyticsDataset, error) {
+	return am.core.GetAllDatasets(opts)
+}
+
+func (am *analyticsIndexProviderPs) CreateIndex(datasetName, indexName string, fields map[string]string, opts *CreateAnalyticsIndexOptions) error {
+	return am.core.CreateIndex(datasetName, indexName, fields, opts)
+}
+
+func (am *analyticsIndexProviderPs) DropIndex(datasetName, indexName string, opts *DropAnalyticsIndexOptions) error {
+	return am.core.DropIndex(datasetName, indexName, opts)
+}
+
+func (am *analyticsIndexProviderPs) GetAllIndexes(opts *GetAllAnalyticsIndexesOptions) ([]AnalyticsIndex, error) {
+	return am.core.GetAllIndexes(opts)
+}
+
+func (am *analyticsIndexProviderPs) ConnectLink(opts *ConnectAnalyticsLinkOptions) error {
+	return am.core.ConnectLink(opts)
+}
+
+func (am *analyticsIndexProviderPs) DisconnectLink(opts *DisconnectAnalyticsLinkOptions) error {
+	return am.core.DisconnectLink(opts)
+}
+
+func (am *analyticsIndexProviderPs) GetPendingMutations(*GetPendingMutationsAnalyticsOptions) (map[string]map[string]int, error) {
+	return nil, makePsNotSupportedError("GetPendingMutations")
+}
+
+func (am *analyticsIndexProviderPs) CreateLink(AnalyticsLink, *CreateAnalyticsLinkOptions) error {
+	return makePsNotSupportedError("CreateLink")
+}
+
+func (am *analyticsIndexProviderPs) ReplaceLink(AnalyticsLink, *ReplaceAnalyticsLinkOptions) error {
+	return makePsNotSupportedError("ReplaceLink")
+}
+
+func (am *analyticsIndexProviderPs) DropLink(string, string, *DropAnalyticsLinkOptions) error {
+	return makePsNotSupportedError("DropLink")
+}
+
+func (am *analyticsIndexProviderPs) GetLinks(*GetAnalyticsLinksOptions) ([]AnalyticsLink, error) {
+	return nil, makePsNotSupportedError("GetLinks")
+}
@@ -14,6 +14,9 @@ type Bucket struct {
 	retryStrategyWrapper *coreRetryStrategyWrapper
 	tracer               RequestTracer
 	meter                *meterWrapper
+	admission            *admissionController
+	interceptors         *operationInterceptors
+	events               *eventBus
 
 	useServerDurations bool
 	useMutationTokens  bool
@@ -36,6 +39,10 @@ func newBucket(c *Cluster, bucketName string) *Bucket {
 		tracer: c.tracer,
 		meter:  c.meter,
 
+		admission:    c.admission,
+		interceptors: c.interceptors,
+		events:       c.events,
+
 		useServerDurations: c.useServerDurations,
 		useMutationTokens:  c.useMutationTokens,
 
@@ -203,6 +210,7 @@ func (b *Bucket) WaitUntilReady(timeout time.Duration, opts *WaitUntilReadyOptio
 		opts,
 	)
 	if err != nil {
+		b.events.publishAuthFailure(b.bucketName, err)
 		return err
 	}
 
@@ -60,8 +60,8 @@ func (report *PingResult) MarshalJSON() ([]byte, error) {
 		for _, service := range serviceInfo {
 			jsonReport.Services[serviceStr] = append(jsonReport.Services[serviceStr], jsonEndpointPingReport{
 				ID:        service.ID,
-				Local:     service.Local,
-				Remote:    service.Remote,
+				Local:     redactSystemData(service.Local),
+				Remote:    redactSystemData(service.Remote),
 				State:     pingStateToString(service.State),
 				Error:     service.Error,
 				Namespace: service.Namespace,
@@ -69,7 +69,7 @@ func (suite *IntegrationTestSuite) TestBucketWaitUntilReadyFastFailAuth() {
 	b := c.Bucket(globalConfig.Bucket)
 
 	err = b.WaitUntilReady(globalCluster.waitUntilReadyTimeout(), &WaitUntilReadyOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if !errors.Is(err, ErrAuthenticationFailure) {
 		suite.T().Fatalf("Expected authentication error but was: %v", err)
@@ -94,7 +94,7 @@ func (suite *IntegrationTestSuite) TestBucketWaitUntilReadyFastFailConnStr() {
 	b := c.Bucket(globalConfig.Bucket)
 
 	err = b.WaitUntilReady(globalCluster.waitUntilReadyTimeout(), &WaitUntilReadyOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if !errors.Is(err, ErrTimeout) {
 		suite.T().Fatalf("Expected timeout error but was: %v", err)
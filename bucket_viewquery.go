@@ -121,6 +121,18 @@ func (r *ViewResult) Raw() *ViewResultRaw {
 	return vr
 }
 
+// rows returns a ViewResultRaw sharing the reader of this result. Unlike Raw the result remains valid, so that the
+// meta-data can be accessed once the rows have been read.
+func (r *ViewResult) rows() (*ViewResultRaw, error) {
+	if r.reader == nil {
+		return nil, r.Err()
+	}
+
+	return &ViewResultRaw{
+		reader: r.reader,
+	}, nil
+}
+
 // Next assigns the next result from the results into the value pointer, returning whether the read was successful.
 func (r *ViewResult) Next() bool {
 	if r.reader == nil {
@@ -215,13 +227,16 @@ func (r *ViewResult) MetaData() (*ViewMetaData, error) {
 }
 
 // ViewQuery performs a view query and returns a list of rows or an error.
-func (b *Bucket) ViewQuery(designDoc string, viewName string, opts *ViewOptions) (*ViewResult, error) {
+func (b *Bucket) ViewQuery(designDoc string, viewName string, opts *ViewOptions) (resOut *ViewResult, errOut error) {
 	if opts == nil {
 		opts = &ViewOptions{}
 	}
 
 	start := time.Now()
-	defer b.meter.ValueRecord(meterValueServiceViews, "views", start)
+	defer func() {
+		op := meterOperation{service: meterValueServiceViews, operation: "views", bucket: b.Name()}
+		b.meter.ValueRecordOutcome(op, errOut, start)
+	}()
 
 	designDoc = b.maybePrefixDevDocument(opts.Namespace, designDoc)
 
@@ -240,6 +255,7 @@ func (b *Bucket) ViewQuery(designDoc string, viewName string, opts *ViewOptions)
 	if opts.RetryStrategy != nil {
 		retryWrapper = newCoreRetryStrategyWrapper(opts.RetryStrategy)
 	}
+	retryWrapper = retryWrapper.forService(ServiceTypeViews, b.meter)
 
 	urlValues, err := opts.toURLValues()
 	if err != nil {
@@ -1,6 +1,10 @@
 package gocb
 
-import "time"
+import (
+	"errors"
+	"sync"
+	"time"
+)
 
 // CircuitBreakerCallback is the callback used by the circuit breaker to determine if an error should count toward
 // the circuit breaker failure count.
@@ -16,3 +20,392 @@ type CircuitBreakerConfig struct {
 	CompletionCallback       CircuitBreakerCallback
 	CanaryTimeout            time.Duration
 }
+
+// CircuitBreakerState represents the state of a circuit breaker.
+type CircuitBreakerState uint32
+
+const (
+	// CircuitBreakerStateDisabled indicates that the circuit breaker is disabled.
+	CircuitBreakerStateDisabled CircuitBreakerState = iota
+
+	// CircuitBreakerStateClosed indicates that the circuit breaker is closed and requests are allowed.
+	CircuitBreakerStateClosed
+
+	// CircuitBreakerStateHalfOpen indicates that the circuit breaker is allowing a single canary request through to
+	// determine whether the service has recovered.
+	CircuitBreakerStateHalfOpen
+
+	// CircuitBreakerStateOpen indicates that the circuit breaker is open and requests will fail fast.
+	CircuitBreakerStateOpen
+)
+
+// String returns the string representation of the state.
+func (state CircuitBreakerState) String() string {
+	switch state {
+	case CircuitBreakerStateDisabled:
+		return "disabled"
+	case CircuitBreakerStateClosed:
+		return "closed"
+	case CircuitBreakerStateHalfOpen:
+		return "half_open"
+	case CircuitBreakerStateOpen:
+		return "open"
+	}
+
+	return "unknown"
+}
+
+// CircuitBreakerStateChangeCallback is invoked whenever the circuit breaker for a service changes state.
+type CircuitBreakerStateChangeCallback func(service ServiceType, from, to CircuitBreakerState)
+
+// ServiceCircuitBreakerConfig are the settings for configuring circuit breakers for the HTTP based services.
+// These circuit breakers are disabled unless Enabled is set, each service can then be disabled individually.
+// When a circuit breaker is open requests to its service will fail with ErrCircuitBreakerOpen.
+// The canary for these services is the first request made once the sleep window has elapsed, if it does not
+// complete within the canary timeout then it is considered to have failed.
+// VOLATILE: This API is subject to change at any time.
+type ServiceCircuitBreakerConfig struct {
+	Enabled bool
+
+	Query      CircuitBreakerConfig
+	Search     CircuitBreakerConfig
+	Analytics  CircuitBreakerConfig
+	Management CircuitBreakerConfig
+
+	// StateChangeCallback is invoked whenever the circuit breaker for a service changes state.
+	StateChangeCallback CircuitBreakerStateChangeCallback
+}
+
+// CircuitBreakerMetrics is a snapshot of the state of a circuit breaker for a service.
+// VOLATILE: This API is subject to change at any time.
+type CircuitBreakerMetrics struct {
+	State CircuitBreakerState
+	// Total is the number of requests completed within the current rolling window.
+	Total int64
+	// Failed is the number of requests which failed within the current rolling window.
+	Failed int64
+	// Rejected is the total number of requests which have been rejected by the circuit breaker.
+	Rejected uint64
+}
+
+// CircuitBreakerMetrics returns a snapshot of the state of the circuit breakers for the HTTP based services.
+// VOLATILE: This API is subject to change at any time.
+func (c *Cluster) CircuitBreakerMetrics() map[ServiceType]CircuitBreakerMetrics {
+	return c.circuitBreakers.metrics()
+}
+
+type serviceCircuitBreakers struct {
+	query      *serviceCircuitBreaker
+	search     *serviceCircuitBreaker
+	analytics  *serviceCircuitBreaker
+	management *serviceCircuitBreaker
+}
+
+func newServiceCircuitBreakers(config ServiceCircuitBreakerConfig) *serviceCircuitBreakers {
+	if !config.Enabled {
+		return &serviceCircuitBreakers{}
+	}
+
+	return &serviceCircuitBreakers{
+		query:      newServiceCircuitBreaker(ServiceTypeQuery, config.Query, config.StateChangeCallback),
+		search:     newServiceCircuitBreaker(ServiceTypeSearch, config.Search, config.StateChangeCallback),
+		analytics:  newServiceCircuitBreaker(ServiceTypeAnalytics, config.Analytics, config.StateChangeCallback),
+		management: newServiceCircuitBreaker(ServiceTypeManagement, config.Management, config.StateChangeCallback),
+	}
+}
+
+// forService returns the circuit breaker for a service, or nil if the service has no circuit breaker.
+func (cbs *serviceCircuitBreakers) forService(service ServiceType) *serviceCircuitBreaker {
+	if cbs == nil {
+		return nil
+	}
+
+	switch service {
+	case ServiceTypeQuery:
+		return cbs.query
+	case ServiceTypeSearch:
+		return cbs.search
+	case ServiceTypeAnalytics:
+		return cbs.analytics
+	case ServiceTypeManagement, ServiceTypeEventing:
+		return cbs.management
+	}
+
+	return nil
+}
+
+func (cbs *serviceCircuitBreakers) metrics() map[ServiceType]CircuitBreakerMetrics {
+	metrics := make(map[ServiceType]CircuitBreakerMetrics)
+	for _, service := range []ServiceType{ServiceTypeQuery, ServiceTypeSearch, ServiceTypeAnalytics, ServiceTypeManagement} {
+		breaker := cbs.forService(service)
+		if breaker == nil {
+			metrics[service] = CircuitBreakerMetrics{State: CircuitBreakerStateDisabled}
+			continue
+		}
+
+		metrics[service] = breaker.metrics()
+	}
+
+	return metrics
+}
+
+// serviceCircuitBreaker is a circuit breaker for a single service, all methods are safe to call on a nil breaker.
+type serviceCircuitBreaker struct {
+	service ServiceType
+
+	volumeThreshold          int64
+	errorPercentageThreshold float64
+	sleepWindow              time.Duration
+	rollingWindow            time.Duration
+	canaryTimeout            time.Duration
+	completionCallback       CircuitBreakerCallback
+	stateChangeCallback      CircuitBreakerStateChangeCallback
+	now                      func() time.Time
+
+	lock            sync.Mutex
+	state           CircuitBreakerState
+	windowStart     time.Time
+	total           int64
+	failed          int64
+	openedAt        time.Time
+	canaryStartedAt time.Time
+	rejected        uint64
+}
+
+// circuitBreakerPermit is returned by AllowsRequest for a request which is allowed through, and must be passed back
+// to MarkComplete once the request completes. It records whether the request is the canary.
+type circuitBreakerPermit struct {
+	canary    bool
+	startedAt time.Time
+}
+
+func newServiceCircuitBreaker(service ServiceType, config CircuitBreakerConfig,
+	stateChangeCallback CircuitBreakerStateChangeCallback) *serviceCircuitBreaker {
+	if config.Disabled {
+		return nil
+	}
+
+	if config.VolumeThreshold == 0 {
+		config.VolumeThreshold = 20
+	}
+	if config.ErrorThresholdPercentage == 0 {
+		config.ErrorThresholdPercentage = 50
+	}
+	if config.SleepWindow == 0 {
+		config.SleepWindow = 5 * time.Second
+	}
+	if config.RollingWindow == 0 {
+		config.RollingWindow = 1 * time.Minute
+	}
+	if config.CanaryTimeout == 0 {
+		config.CanaryTimeout = 5 * time.Second
+	}
+	if config.CompletionCallback == nil {
+		config.CompletionCallback = func(err error) bool {
+			return !errors.Is(err, ErrTimeout)
+		}
+	}
+
+	return &serviceCircuitBreaker{
+		service:                  service,
+		volumeThreshold:          config.VolumeThreshold,
+		errorPercentageThreshold: config.ErrorThresholdPercentage,
+		sleepWindow:              config.SleepWindow,
+		rollingWindow:            config.RollingWindow,
+		canaryTimeout:            config.CanaryTimeout,
+		completionCallback:       config.CompletionCallback,
+		stateChangeCallback:      stateChangeCallback,
+		now:                      time.Now,
+		state:                    CircuitBreakerStateClosed,
+		windowStart:              time.Now(),
+	}
+}
+
+// AllowsRequest returns whether a request can be sent to the service. If the sleep window has elapsed since the
+// circuit opened then the request is allowed through as the canary.
+func (cb *serviceCircuitBreaker) AllowsRequest() (circuitBreakerPermit, bool) {
+	if cb == nil {
+		return circuitBreakerPermit{}, true
+	}
+
+	now := cb.now()
+
+	cb.lock.Lock()
+	from := cb.state
+	switch cb.state {
+	case CircuitBreakerStateClosed:
+		cb.lock.Unlock()
+		return circuitBreakerPermit{}, true
+	case CircuitBreakerStateHalfOpen:
+		if now.Sub(cb.canaryStartedAt) <= cb.canaryTimeout {
+			cb.rejected++
+			cb.lock.Unlock()
+			return circuitBreakerPermit{}, false
+		}
+
+		// The canary didn't complete in time so it's deemed to have failed, start a new sleep window.
+		logDebugf("Circuit breaker canary for %s timed out", serviceTypeToString(cb.service))
+		cb.state = CircuitBreakerStateOpen
+		cb.openedAt = now
+		cb.rejected++
+		cb.lock.Unlock()
+		cb.notifyStateChange(from, CircuitBreakerStateOpen)
+		return circuitBreakerPermit{}, false
+	}
+
+	if now.Sub(cb.openedAt) <= cb.sleepWindow {
+		cb.rejected++
+		cb.lock.Unlock()
+		return circuitBreakerPermit{}, false
+	}
+
+	cb.state = CircuitBreakerStateHalfOpen
+	cb.canaryStartedAt = now
+	cb.lock.Unlock()
+	cb.notifyStateChange(from, CircuitBreakerStateHalfOpen)
+	return circuitBreakerPermit{canary: true, startedAt: now}, true
+}
+
+// MarkComplete records the outcome of a request that was allowed by the circuit breaker. Only the outcome of the
+// canary can move the circuit breaker out of the half open state, and then only if it completed within the canary
+// timeout. Requests which were allowed before the circuit opened are not counted once it has opened.
+func (cb *serviceCircuitBreaker) MarkComplete(permit circuitBreakerPermit, err error) {
+	if cb == nil {
+		return
+	}
+
+	success := err == nil || cb.completionCallback(err)
+	if permit.canary {
+		cb.markCanaryComplete(permit, success)
+		return
+	}
+
+	if success {
+		cb.markSuccessful()
+		return
+	}
+
+	cb.markFailure()
+}
+
+func (cb *serviceCircuitBreaker) markCanaryComplete(permit circuitBreakerPermit, success bool) {
+	now := cb.now()
+
+	cb.lock.Lock()
+	if cb.state != CircuitBreakerStateHalfOpen || !cb.canaryStartedAt.Equal(permit.startedAt) {
+		// The canary has already been deemed to have timed out.
+		cb.lock.Unlock()
+		return
+	}
+
+	if success && now.Sub(permit.startedAt) <= cb.canaryTimeout {
+		logDebugf("Moving circuit breaker for %s to closed", serviceTypeToString(cb.service))
+		cb.resetLocked(now)
+		cb.lock.Unlock()
+		cb.notifyStateChange(CircuitBreakerStateHalfOpen, CircuitBreakerStateClosed)
+		return
+	}
+
+	logDebugf("Moving circuit breaker for %s from half open to open", serviceTypeToString(cb.service))
+	cb.state = CircuitBreakerStateOpen
+	cb.openedAt = now
+	cb.lock.Unlock()
+	cb.notifyStateChange(CircuitBreakerStateHalfOpen, CircuitBreakerStateOpen)
+}
+
+func (cb *serviceCircuitBreaker) markSuccessful() {
+	now := cb.now()
+
+	cb.lock.Lock()
+	if cb.state == CircuitBreakerStateClosed {
+		cb.maybeResetRollingWindowLocked(now)
+		cb.total++
+	}
+	cb.lock.Unlock()
+}
+
+func (cb *serviceCircuitBreaker) markFailure() {
+	now := cb.now()
+
+	cb.lock.Lock()
+	if cb.state != CircuitBreakerStateClosed {
+		cb.lock.Unlock()
+		return
+	}
+
+	cb.maybeResetRollingWindowLocked(now)
+	cb.total++
+	cb.failed++
+
+	if cb.total < cb.volumeThreshold {
+		cb.lock.Unlock()
+		return
+	}
+
+	currentPercentage := (float64(cb.failed) / float64(cb.total)) * 100
+	if currentPercentage < cb.errorPercentageThreshold {
+		cb.lock.Unlock()
+		return
+	}
+
+	logDebugf("Moving circuit breaker for %s to open", serviceTypeToString(cb.service))
+	cb.state = CircuitBreakerStateOpen
+	cb.openedAt = now
+	cb.lock.Unlock()
+	cb.notifyStateChange(CircuitBreakerStateClosed, CircuitBreakerStateOpen)
+}
+
+func (cb *serviceCircuitBreaker) State() CircuitBreakerState {
+	if cb == nil {
+		return CircuitBreakerStateDisabled
+	}
+
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	return cb.state
+}
+
+func (cb *serviceCircuitBreaker) metrics() CircuitBreakerMetrics {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	return CircuitBreakerMetrics{
+		State:    cb.state,
+		Total:    cb.total,
+		Failed:   cb.failed,
+		Rejected: cb.rejected,
+	}
+}
+
+func (cb *serviceCircuitBreaker) resetLocked(now time.Time) {
+	cb.state = CircuitBreakerStateClosed
+	cb.total = 0
+	cb.failed = 0
+	cb.openedAt = time.Time{}
+	cb.canaryStartedAt = time.Time{}
+	cb.windowStart = now
+}
+
+func (cb *serviceCircuitBreaker) maybeResetRollingWindowLocked(now time.Time) {
+	if now.Sub(cb.windowStart) <= cb.rollingWindow {
+		return
+	}
+
+	cb.windowStart = now
+	cb.total = 0
+	cb.failed = 0
+}
+
+func (cb *serviceCircuitBreaker) notifyStateChange(from, to CircuitBreakerState) {
+	if cb.stateChangeCallback == nil || from == to {
+		return
+	}
+
+	cb.stateChangeCallback(cb.service, from, to)
+}
+
+func (cb *serviceCircuitBreaker) openError() error {
+	return makeGenericError(ErrCircuitBreakerOpen, map[string]interface{}{
+		"service": serviceTypeToString(cb.service),
+	})
+}
@@ -0,0 +1,83 @@
+package gocb
+
+import (
+	"context"
+
+	gocbcore "github.com/couchbase/gocbcore/v10"
+)
+
+type circuitBreakerQueryProviderCore struct {
+	provider queryProviderCoreProvider
+	breaker  *serviceCircuitBreaker
+}
+
+func (cbp *circuitBreakerQueryProviderCore) N1QLQuery(ctx context.Context, opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	permit, ok := cbp.breaker.AllowsRequest()
+	if !ok {
+		return nil, cbp.breaker.openError()
+	}
+
+	res, err := cbp.provider.N1QLQuery(ctx, opts)
+	cbp.breaker.MarkComplete(permit, err)
+	return res, err
+}
+
+func (cbp *circuitBreakerQueryProviderCore) PreparedN1QLQuery(ctx context.Context, opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	permit, ok := cbp.breaker.AllowsRequest()
+	if !ok {
+		return nil, cbp.breaker.openError()
+	}
+
+	res, err := cbp.provider.PreparedN1QLQuery(ctx, opts)
+	cbp.breaker.MarkComplete(permit, err)
+	return res, err
+}
+
+type circuitBreakerSearchProviderCore struct {
+	provider searchProviderCoreProvider
+	breaker  *serviceCircuitBreaker
+}
+
+func (cbp *circuitBreakerSearchProviderCore) SearchQuery(ctx context.Context, opts gocbcore.SearchQueryOptions) (searchRowReader, error) {
+	permit, ok := cbp.breaker.AllowsRequest()
+	if !ok {
+		return nil, cbp.breaker.openError()
+	}
+
+	res, err := cbp.provider.SearchQuery(ctx, opts)
+	cbp.breaker.MarkComplete(permit, err)
+	return res, err
+}
+
+type circuitBreakerAnalyticsProviderCore struct {
+	provider analyticsProviderCoreProvider
+	breaker  *serviceCircuitBreaker
+}
+
+func (cbp *circuitBreakerAnalyticsProviderCore) AnalyticsQuery(ctx context.Context, opts gocbcore.AnalyticsQueryOptions) (analyticsRowReader, error) {
+	permit, ok := cbp.breaker.AllowsRequest()
+	if !ok {
+		return nil, cbp.breaker.openError()
+	}
+
+	res, err := cbp.provider.AnalyticsQuery(ctx, opts)
+	cbp.breaker.MarkComplete(permit, err)
+	return res, err
+}
+
+type circuitBreakerHTTPProviderCore struct {
+	provider httpProvider
+	breakers *serviceCircuitBreakers
+}
+
+func (cbp *circuitBreakerHTTPProviderCore) DoHTTPRequest(ctx context.Context, req *gocbcore.HTTPRequest) (*gocbcore.HTTPResponse, error) {
+	breaker := cbp.breakers.forService(ServiceType(req.Service))
+	permit, ok := breaker.AllowsRequest()
+	if !ok {
+		return nil, breaker.openError()
+	}
+
+	res, err := cbp.provider.DoHTTPRequest(ctx, req)
+	breaker.MarkComplete(permit, err)
+	return res, err
+}
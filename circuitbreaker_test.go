@@ -0,0 +1,217 @@
+package gocb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeCircuitBreakerClock replaces the clock of a circuit breaker so that tests can move time forward.
+type fakeCircuitBreakerClock struct {
+	now time.Time
+}
+
+func newFakeCircuitBreakerClock(breaker *serviceCircuitBreaker) *fakeCircuitBreakerClock {
+	clock := &fakeCircuitBreakerClock{now: breaker.windowStart}
+	breaker.now = func() time.Time {
+		return clock.now
+	}
+	return clock
+}
+
+func (c *fakeCircuitBreakerClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func (suite *UnitTestSuite) TestServiceCircuitBreakerOpensAndCloses() {
+	type stateChange struct {
+		from CircuitBreakerState
+		to   CircuitBreakerState
+	}
+	var changes []stateChange
+	breaker := newServiceCircuitBreaker(ServiceTypeQuery, CircuitBreakerConfig{
+		VolumeThreshold:          4,
+		ErrorThresholdPercentage: 50,
+		SleepWindow:              50 * time.Millisecond,
+	}, func(service ServiceType, from, to CircuitBreakerState) {
+		suite.Assert().Equal(ServiceTypeQuery, service)
+		changes = append(changes, stateChange{from: from, to: to})
+	})
+	clock := newFakeCircuitBreakerClock(breaker)
+
+	breaker.MarkComplete(circuitBreakerPermit{}, nil)
+	breaker.MarkComplete(circuitBreakerPermit{}, nil)
+	breaker.MarkComplete(circuitBreakerPermit{}, ErrTimeout)
+	suite.Assert().Equal(CircuitBreakerStateClosed, breaker.State())
+
+	breaker.MarkComplete(circuitBreakerPermit{}, ErrTimeout)
+	suite.Require().Equal(CircuitBreakerStateOpen, breaker.State())
+	_, ok := breaker.AllowsRequest()
+	suite.Assert().False(ok)
+
+	clock.advance(60 * time.Millisecond)
+
+	// The first request after the sleep window is the canary, others are rejected until it completes.
+	canary, ok := breaker.AllowsRequest()
+	suite.Require().True(ok)
+	suite.Assert().True(canary.canary)
+	suite.Assert().Equal(CircuitBreakerStateHalfOpen, breaker.State())
+	_, ok = breaker.AllowsRequest()
+	suite.Assert().False(ok)
+
+	// Requests allowed before the circuit opened don't affect the canary.
+	breaker.MarkComplete(circuitBreakerPermit{}, nil)
+	suite.Assert().Equal(CircuitBreakerStateHalfOpen, breaker.State())
+
+	breaker.MarkComplete(canary, nil)
+	suite.Assert().Equal(CircuitBreakerStateClosed, breaker.State())
+	_, ok = breaker.AllowsRequest()
+	suite.Assert().True(ok)
+
+	suite.Assert().Equal([]stateChange{
+		{from: CircuitBreakerStateClosed, to: CircuitBreakerStateOpen},
+		{from: CircuitBreakerStateOpen, to: CircuitBreakerStateHalfOpen},
+		{from: CircuitBreakerStateHalfOpen, to: CircuitBreakerStateClosed},
+	}, changes)
+
+	metrics := breaker.metrics()
+	suite.Assert().Equal(uint64(2), metrics.Rejected)
+}
+
+func (suite *UnitTestSuite) TestServiceCircuitBreakerCanaryFails() {
+	breaker := newServiceCircuitBreaker(ServiceTypeQuery, CircuitBreakerConfig{
+		VolumeThreshold: 1,
+		SleepWindow:     10 * time.Millisecond,
+	}, nil)
+	clock := newFakeCircuitBreakerClock(breaker)
+
+	breaker.MarkComplete(circuitBreakerPermit{}, ErrTimeout)
+	clock.advance(20 * time.Millisecond)
+
+	canary, ok := breaker.AllowsRequest()
+	suite.Require().True(ok)
+	breaker.MarkComplete(canary, ErrTimeout)
+	suite.Assert().Equal(CircuitBreakerStateOpen, breaker.State())
+
+	// A new sleep window starts from the failure of the canary.
+	clock.advance(5 * time.Millisecond)
+	_, ok = breaker.AllowsRequest()
+	suite.Assert().False(ok)
+}
+
+func (suite *UnitTestSuite) TestServiceCircuitBreakerCanaryTimeout() {
+	breaker := newServiceCircuitBreaker(ServiceTypeSearch, CircuitBreakerConfig{
+		VolumeThreshold: 1,
+		SleepWindow:     10 * time.Millisecond,
+		CanaryTimeout:   10 * time.Millisecond,
+	}, nil)
+	clock := newFakeCircuitBreakerClock(breaker)
+
+	breaker.MarkComplete(circuitBreakerPermit{}, ErrTimeout)
+	suite.Require().Equal(CircuitBreakerStateOpen, breaker.State())
+
+	clock.advance(20 * time.Millisecond)
+	_, ok := breaker.AllowsRequest()
+	suite.Require().True(ok)
+
+	clock.advance(20 * time.Millisecond)
+	_, ok = breaker.AllowsRequest()
+	suite.Assert().False(ok)
+	suite.Assert().Equal(CircuitBreakerStateOpen, breaker.State())
+}
+
+func (suite *UnitTestSuite) TestServiceCircuitBreakerLateCanary() {
+	breaker := newServiceCircuitBreaker(ServiceTypeSearch, CircuitBreakerConfig{
+		VolumeThreshold: 1,
+		SleepWindow:     10 * time.Millisecond,
+		CanaryTimeout:   10 * time.Millisecond,
+	}, nil)
+	clock := newFakeCircuitBreakerClock(breaker)
+
+	breaker.MarkComplete(circuitBreakerPermit{}, ErrTimeout)
+	clock.advance(20 * time.Millisecond)
+
+	canary, ok := breaker.AllowsRequest()
+	suite.Require().True(ok)
+
+	// A canary which succeeds after the canary timeout is deemed to have failed.
+	clock.advance(20 * time.Millisecond)
+	breaker.MarkComplete(canary, nil)
+	suite.Assert().Equal(CircuitBreakerStateOpen, breaker.State())
+}
+
+func (suite *UnitTestSuite) TestServiceCircuitBreakerCompletionCallback() {
+	breaker := newServiceCircuitBreaker(ServiceTypeAnalytics, CircuitBreakerConfig{
+		VolumeThreshold: 1,
+		CompletionCallback: func(err error) bool {
+			return !errors.Is(err, ErrServiceNotAvailable)
+		},
+	}, nil)
+
+	breaker.MarkComplete(circuitBreakerPermit{}, ErrTimeout)
+	suite.Assert().Equal(CircuitBreakerStateClosed, breaker.State())
+
+	breaker.MarkComplete(circuitBreakerPermit{}, ErrServiceNotAvailable)
+	suite.Assert().Equal(CircuitBreakerStateOpen, breaker.State())
+}
+
+func (suite *UnitTestSuite) TestServiceCircuitBreakersDisabled() {
+	breakers := newServiceCircuitBreakers(ServiceCircuitBreakerConfig{})
+	suite.Assert().Nil(breakers.forService(ServiceTypeQuery))
+	_, ok := breakers.forService(ServiceTypeQuery).AllowsRequest()
+	suite.Assert().True(ok)
+
+	breakers = newServiceCircuitBreakers(ServiceCircuitBreakerConfig{
+		Enabled: true,
+		Search:  CircuitBreakerConfig{Disabled: true},
+	})
+	suite.Assert().NotNil(breakers.forService(ServiceTypeQuery))
+	suite.Assert().Nil(breakers.forService(ServiceTypeSearch))
+	suite.Assert().Same(breakers.forService(ServiceTypeManagement), breakers.forService(ServiceTypeEventing))
+
+	metrics := breakers.metrics()
+	suite.Assert().Equal(CircuitBreakerStateClosed, metrics[ServiceTypeQuery].State)
+	suite.Assert().Equal(CircuitBreakerStateDisabled, metrics[ServiceTypeSearch].State)
+}
+
+func (suite *UnitTestSuite) TestQueryCircuitBreakerOpen() {
+	retErr := errors.New("an error")
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(nil, retErr).
+		Once()
+
+	breaker := newServiceCircuitBreaker(ServiceTypeQuery, CircuitBreakerConfig{
+		VolumeThreshold: 1,
+		CompletionCallback: func(err error) bool {
+			return false
+		},
+	}, nil)
+
+	queryProvider := &queryProviderCore{
+		provider: &circuitBreakerQueryProviderCore{provider: provider, breaker: breaker},
+	}
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+
+	cluster := suite.newCluster(cli)
+
+	queryProvider.meter = cluster.meter
+	queryProvider.tracer = cluster.tracer
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	_, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{
+		Adhoc: true,
+	})
+	suite.Require().Equal(retErr, err)
+
+	_, err = cluster.Query("SELECT * FROM dataset", &QueryOptions{
+		Adhoc: true,
+	})
+	suite.Require().ErrorIs(err, ErrCircuitBreakerOpen)
+	suite.Assert().Contains(err.Error(), "query")
+	provider.AssertExpectations(suite.T())
+}
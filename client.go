@@ -22,6 +22,9 @@ type connectionManager interface {
 	getWaitUntilReadyProvider(bucketName string) (waitUntilReadyProvider, error)
 	getCollectionsManagementProvider(bucketName string) (collectionsManagementProvider, error)
 	getBucketManagementProvider() (bucketManagementProvider, error)
+	getUserManagementProvider() (userManagementProvider, error)
+	getEventingManagementProvider() (eventingManagementProvider, error)
+	getAnalyticsIndexProvider() (analyticsIndexProvider, error)
 	getSearchIndexProvider() (searchIndexProvider, error)
 }
 
@@ -33,6 +36,12 @@ func (c *Cluster) newConnectionMgr(protocol string) connectionManager {
 			tracer:       c.tracer,
 			meter:        c.meter,
 			defaultRetry: c.retryStrategyWrapper.wrapped,
+			breakers:     c.circuitBreakers,
+			admission:    c.admission,
+			interceptors: c.interceptors,
+			compressor:   newPsCompressor(c.compressionConfig),
+			psConfig:     c.protostellarConfig,
+			events:       c.events,
 		}
 	default:
 		return &stdConnectionMgr{
@@ -41,6 +50,10 @@ func (c *Cluster) newConnectionMgr(protocol string) connectionManager {
 			timeouts:             c.timeoutsConfig,
 			tracer:               c.tracer,
 			meter:                c.meter,
+			breakers:             c.circuitBreakers,
+			admission:            c.admission,
+			interceptors:         c.interceptors,
+			events:               c.events,
 		}
 	}
 }
@@ -18,6 +18,11 @@ type stdConnectionMgr struct {
 	timeouts             TimeoutsConfig
 	tracer               RequestTracer
 	meter                *meterWrapper
+	breakers             *serviceCircuitBreakers
+	admission            *admissionController
+	interceptors         *operationInterceptors
+	events               *eventBus
+	monitor              *connectionMonitor
 }
 
 func (c *stdConnectionMgr) buildConfig(cluster *Cluster) error {
@@ -124,6 +129,18 @@ func (c *stdConnectionMgr) connect() error {
 		return maybeEnhanceKVErr(err, "", "", "", "")
 	}
 
+	// Subscribers can be added at any time so the monitor is always started, the tracker itself skips polling
+	// whilst there's neither a configured meter nor any subscribers.
+	agentgroup := c.agentgroup
+	tracker := newKvConnectionTracker(agentgroup, func(bucket string) coreDiagnosticsProvider {
+		if agent := agentgroup.GetAgent(bucket); agent != nil {
+			return agent
+		}
+		return nil
+	}, c.meter, c.events)
+	c.monitor = newConnectionMonitor(connectionMonitorInterval, tracker.poll)
+	c.monitor.start()
+
 	return nil
 }
 
@@ -132,7 +149,13 @@ func (c *stdConnectionMgr) openBucket(bucketName string) error {
 		return errors.New("cluster not yet connected")
 	}
 
-	return c.agentgroup.OpenBucket(bucketName)
+	err := c.agentgroup.OpenBucket(bucketName)
+	if err != nil {
+		return err
+	}
+
+	c.events.publish(BucketOpened{Bucket: bucketName})
+	return nil
 }
 
 func (c *stdConnectionMgr) getKvProvider(bucketName string) (kvProvider, error) {
@@ -189,7 +212,7 @@ func (c *stdConnectionMgr) getQueryProvider() (queryProvider, error) {
 	}
 
 	return &queryProviderCore{
-		provider: &queryProviderWrapper{provider: c.agentgroup},
+		provider: c.wrapQueryProvider(&queryProviderWrapper{provider: c.agentgroup}),
 
 		retryStrategyWrapper: c.retryStrategyWrapper,
 		transcoder:           c.transcoder,
@@ -205,7 +228,7 @@ func (c *stdConnectionMgr) getQueryIndexProvider() (queryIndexProvider, error) {
 	}
 
 	return &queryProviderCore{
-		provider: &queryProviderWrapper{provider: c.agentgroup},
+		provider: c.wrapQueryProvider(&queryProviderWrapper{provider: c.agentgroup}),
 
 		retryStrategyWrapper: c.retryStrategyWrapper,
 		transcoder:           c.transcoder,
@@ -220,7 +243,14 @@ func (c *stdConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
 		return nil, errors.New("cluster not yet connected")
 	}
 
-	return &analyticsProviderWrapper{provider: c.agentgroup}, nil
+	return &analyticsProviderCore{
+		provider: c.wrapAnalyticsProvider(&analyticsProviderWrapper{provider: c.agentgroup}),
+
+		retryStrategyWrapper: c.retryStrategyWrapper,
+		timeouts:             c.timeouts,
+		tracer:               c.tracer,
+		meter:                c.meter,
+	}, nil
 }
 
 func (c *stdConnectionMgr) getSearchProvider() (searchProvider, error) {
@@ -229,7 +259,7 @@ func (c *stdConnectionMgr) getSearchProvider() (searchProvider, error) {
 	}
 
 	return &searchProviderCore{
-		provider:             &searchProviderWrapper{agent: c.agentgroup},
+		provider:             c.wrapSearchProvider(&searchProviderWrapper{agent: c.agentgroup}),
 		retryStrategyWrapper: c.retryStrategyWrapper,
 		transcoder:           c.transcoder,
 		timeouts:             c.timeouts,
@@ -245,13 +275,9 @@ func (c *stdConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error)
 	}
 
 	return &searchIndexProviderCore{
-		mgmtProvider: &mgmtProviderCore{
-			provider:             provider,
-			mgmtTimeout:          c.timeouts.ManagementTimeout,
-			retryStrategyWrapper: c.retryStrategyWrapper,
-		},
-		tracer: c.tracer,
-		meter:  c.meter,
+		mgmtProvider: c.newMgmtProvider(provider),
+		tracer:       c.tracer,
+		meter:        c.meter,
 	}, nil
 }
 
@@ -261,7 +287,7 @@ func (c *stdConnectionMgr) getHTTPProvider(bucketName string) (httpProvider, err
 	}
 
 	if bucketName == "" {
-		return &httpProviderWrapper{provider: c.agentgroup}, nil
+		return c.wrapHTTPProvider(&httpProviderWrapper{provider: c.agentgroup}), nil
 	}
 
 	agent := c.agentgroup.GetAgent(bucketName)
@@ -269,7 +295,52 @@ func (c *stdConnectionMgr) getHTTPProvider(bucketName string) (httpProvider, err
 		return nil, errors.New("bucket not yet connected")
 	}
 
-	return &httpProviderWrapper{provider: agent}, nil
+	return c.wrapHTTPProvider(&httpProviderWrapper{provider: agent}), nil
+}
+
+func (c *stdConnectionMgr) wrapQueryProvider(provider queryProviderCoreProvider) queryProviderCoreProvider {
+	if breaker := c.breakers.forService(ServiceTypeQuery); breaker != nil {
+		provider = &circuitBreakerQueryProviderCore{provider: provider, breaker: breaker}
+	}
+	if limiter := c.admission.forService(ServiceTypeQuery); limiter != nil {
+		provider = &admissionQueryProviderCore{provider: provider, limiter: limiter}
+	}
+
+	return provider
+}
+
+func (c *stdConnectionMgr) wrapAnalyticsProvider(provider analyticsProviderCoreProvider) analyticsProviderCoreProvider {
+	if breaker := c.breakers.forService(ServiceTypeAnalytics); breaker != nil {
+		provider = &circuitBreakerAnalyticsProviderCore{provider: provider, breaker: breaker}
+	}
+	if limiter := c.admission.forService(ServiceTypeAnalytics); limiter != nil {
+		provider = &admissionAnalyticsProviderCore{provider: provider, limiter: limiter}
+	}
+
+	return provider
+}
+
+func (c *stdConnectionMgr) wrapSearchProvider(provider searchProviderCoreProvider) searchProviderCoreProvider {
+	if breaker := c.breakers.forService(ServiceTypeSearch); breaker != nil {
+		provider = &circuitBreakerSearchProviderCore{provider: provider, breaker: breaker}
+	}
+	if limiter := c.admission.forService(ServiceTypeSearch); limiter != nil {
+		provider = &admissionSearchProviderCore{provider: provider, limiter: limiter}
+	}
+
+	return provider
+}
+
+func (c *stdConnectionMgr) wrapHTTPProvider(provider httpProvider) httpProvider {
+	if c.breakers != nil && (c.breakers.query != nil || c.breakers.search != nil || c.breakers.analytics != nil ||
+		c.breakers.management != nil) {
+		provider = &circuitBreakerHTTPProviderCore{provider: provider, breakers: c.breakers}
+	}
+	if c.admission.enabled() {
+		provider = &admissionHTTPProviderCore{provider: provider, admission: c.admission}
+	}
+
+	return provider
 }
 
 func (c *stdConnectionMgr) getDiagnosticsProvider(bucketName string) (diagnosticsProvider, error) {
@@ -324,11 +395,7 @@ func (c *stdConnectionMgr) getCollectionsManagementProvider(bucketName string) (
 	}
 
 	return &collectionsManagementProviderCore{
-		mgmtProvider: &mgmtProviderCore{
-			provider:             provider,
-			mgmtTimeout:          c.timeouts.ManagementTimeout,
-			retryStrategyWrapper: c.retryStrategyWrapper,
-		},
+		mgmtProvider:    c.newMgmtProvider(provider),
 		featureVerifier: capabilityProvider,
 		bucketName:      bucketName,
 		tracer:          c.tracer,
@@ -343,16 +410,68 @@ func (c *stdConnectionMgr) getBucketManagementProvider() (bucketManagementProvid
 	}
 
 	return &bucketManagementProviderCore{
-		mgmtProvider: &mgmtProviderCore{
-			provider:             provider,
-			mgmtTimeout:          c.timeouts.ManagementTimeout,
-			retryStrategyWrapper: c.retryStrategyWrapper,
-		},
-		tracer: c.tracer,
-		meter:  c.meter,
+		mgmtProvider: c.newMgmtProvider(provider),
+		tracer:       c.tracer,
+		meter:        c.meter,
+	}, nil
+}
+
+func (c *stdConnectionMgr) getUserManagementProvider() (userManagementProvider, error) {
+	provider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &userManagementProviderCore{
+		mgmtProvider: c.newMgmtProvider(provider),
+		tracer:       c.tracer,
+		meter:        c.meter,
+	}, nil
+}
+
+func (c *stdConnectionMgr) getEventingManagementProvider() (eventingManagementProvider, error) {
+	provider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventingManagementProviderCore{
+		mgmtProvider: c.newMgmtProvider(provider),
+		tracer:       c.tracer,
+		meter:        c.meter,
+	}, nil
+}
+
+func (c *stdConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+	aProvider, err := c.getAnalyticsProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &analyticsIndexProviderCore{
+		aProvider:     aProvider,
+		mgmtProvider:  c.newMgmtProvider(provider),
+		globalTimeout: c.timeouts.ManagementTimeout,
+		tracer:        c.tracer,
+		meter:         c.meter,
 	}, nil
 }
 
+func (c *stdConnectionMgr) newMgmtProvider(provider httpProvider) *mgmtProviderCore {
+	return &mgmtProviderCore{
+		provider:             provider,
+		mgmtTimeout:          c.timeouts.ManagementTimeout,
+		retryStrategyWrapper: c.retryStrategyWrapper,
+		meter:                c.meter,
+		interceptors:         c.interceptors.all(),
+	}
+}
+
 func (c *stdConnectionMgr) connection(bucketName string) (*gocbcore.Agent, error) {
 	if c.agentgroup == nil {
 		return nil, errors.New("cluster not yet connected")
@@ -372,5 +491,6 @@ func (c *stdConnectionMgr) close() error {
 		return errors.New("cluster not yet connected")
 	}
 	defer c.lock.Unlock()
+	c.monitor.stop()
 	return c.agentgroup.Close()
 }
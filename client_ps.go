@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
 
 	"github.com/couchbase/gocbcore/v10"
 	"github.com/couchbase/gocbcoreps"
@@ -19,10 +20,22 @@ type psConnectionMgr struct {
 	config *gocbcoreps.DialOptions
 	agent  *gocbcoreps.RoutingClient
 
+	// clients is the routing client, or psConn wrapping it when the ProtostellarConfig requires it.
+	clients  psServiceClients
+	psConn   *psClientConn
+	psConfig ProtostellarConfig
+
 	timeouts     TimeoutsConfig
 	tracer       RequestTracer
 	meter        *meterWrapper
 	defaultRetry RetryStrategy
+	breakers     *serviceCircuitBreakers
+	admission    *admissionController
+	interceptors *operationInterceptors
+	compressor   *psCompressor
+	events       *eventBus
+	monitor      *connectionMonitor
+	keepAlive    *connectionMonitor
 }
 
 func (c *psConnectionMgr) connect() error {
@@ -35,11 +48,35 @@ func (c *psConnectionMgr) connect() error {
 	}
 
 	c.agent = client
+	c.clients = client
+	if psClientConnRequired(c.psConfig) {
+		c.psConn, err = newPsClientConn(client, c.host, c.psConfig)
+		if err != nil {
+			_ = client.Close()
+			return err
+		}
+		c.clients = c.psConn
+	}
+
+	tracker := newPsReconnectTracker(client, c.meter, c.events, c.host)
+	c.monitor = newConnectionMonitor(connectionMonitorInterval, tracker.poll)
+	c.monitor.start()
+
+	if c.psConfig.KeepAliveTime > 0 {
+		keepAliveTimeout := c.psConfig.KeepAliveTimeout
+		if keepAliveTimeout == 0 {
+			keepAliveTimeout = psKeepAliveDefaultTimeout
+		}
+		keepAlive := newPsKeepAlive(c.clients.KvV1(), c.psConfig.PoolSize, keepAliveTimeout)
+		c.keepAlive = newConnectionMonitor(c.psConfig.KeepAliveTime, keepAlive.poll)
+		c.keepAlive.start()
+	}
 
 	return nil
 }
 
 func (c *psConnectionMgr) openBucket(bucketName string) error {
+	// Buckets are not connected to over protostellar, so there's nothing to open and no BucketOpened event.
 	return nil
 }
 
@@ -51,6 +88,10 @@ func (c *psConnectionMgr) buildConfig(cluster *Cluster) error {
 		return err
 	}
 
+	if c.psConfig.Compressor != "" && encoding.GetCompressor(c.psConfig.Compressor) == nil {
+		return makeInvalidArgumentsError(fmt.Sprintf("unknown grpc compressor %s", c.psConfig.Compressor))
+	}
+
 	logger := newZapLogger()
 
 	c.config = &gocbcoreps.DialOptions{
@@ -59,19 +100,20 @@ func (c *psConnectionMgr) buildConfig(cluster *Cluster) error {
 		InsecureSkipVerify: cluster.securityConfig.TLSSkipVerify,
 		ClientCertificate:  cluster.securityConfig.TLSRootCAs,
 		Logger:             logger,
+		PoolSize:           c.psConfig.PoolSize,
 	}
 
 	return nil
 }
 
 func (c *psConnectionMgr) getKvProvider(bucketName string) (kvProvider, error) {
-	kv := c.agent.KvV1()
-	return &kvProviderPs{client: kv}, nil
+	kv := c.clients.KvV1()
+	return &kvProviderPs{client: kv, compressor: c.compressor}, nil
 }
 
 func (c *psConnectionMgr) getKvBulkProvider(bucketName string) (kvBulkProvider, error) {
-	kv := c.agent.KvV1()
-	return &kvBulkProviderPs{client: kv}, nil
+	kv := c.clients.KvV1()
+	return &kvBulkProviderPs{client: kv, compressor: c.compressor}, nil
 }
 
 func (c *psConnectionMgr) getKvCapabilitiesProvider(bucketName string) (kvCapabilityVerifier, error) {
@@ -83,64 +125,111 @@ func (c *psConnectionMgr) getViewProvider(bucketName string) (viewProvider, erro
 }
 
 func (c *psConnectionMgr) getQueryProvider() (queryProvider, error) {
-	provider := c.agent.QueryV1()
+	provider := c.clients.QueryV1()
 	return &queryProviderPs{
 		provider: provider,
 
-		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceQuery),
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceQuery,
+			c.breakers.forService(ServiceTypeQuery), c.admission.forService(ServiceTypeQuery), nil),
 	}, nil
 }
 
 func (c *psConnectionMgr) getQueryIndexProvider() (queryIndexProvider, error) {
-	provider := c.agent.QueryAdminV1()
+	provider := c.clients.QueryAdminV1()
 	return &queryIndexProviderPs{
 		provider: provider,
 
-		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceManagement),
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceManagement,
+			c.breakers.forService(ServiceTypeManagement), c.admission.forService(ServiceTypeManagement),
+			c.interceptors.all()),
 	}, nil
 }
 
 func (c *psConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
-	provider := c.agent.SearchAdminV1()
+	provider := c.clients.SearchAdminV1()
 	return &searchIndexProviderPs{
 		provider: provider,
 
-		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceManagement),
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceManagement,
+			c.breakers.forService(ServiceTypeManagement), c.admission.forService(ServiceTypeManagement),
+			c.interceptors.all()),
 	}, nil
 }
 
 func (c *psConnectionMgr) getCollectionsManagementProvider(bucketName string) (collectionsManagementProvider, error) {
 	return &collectionsManagementProviderPs{
-		provider:   c.agent.CollectionV1(),
+		provider:   c.clients.CollectionV1(),
 		bucketName: bucketName,
 
-		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceManagement),
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceManagement,
+			c.breakers.forService(ServiceTypeManagement), c.admission.forService(ServiceTypeManagement),
+			c.interceptors.all()),
 	}, nil
 }
 
 func (c *psConnectionMgr) getBucketManagementProvider() (bucketManagementProvider, error) {
 	return &bucketManagementProviderPs{
-		provider: c.agent.BucketV1(),
+		provider: c.clients.BucketV1(),
+
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceManagement,
+			c.breakers.forService(ServiceTypeManagement), c.admission.forService(ServiceTypeManagement),
+			c.interceptors.all()),
+	}, nil
+}
+
+func (c *psConnectionMgr) getUserManagementProvider() (userManagementProvider, error) {
+	return &userManagementProviderPs{}, nil
+}
 
-		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceManagement),
+func (c *psConnectionMgr) getEventingManagementProvider() (eventingManagementProvider, error) {
+	return &eventingManagementProviderPs{}, nil
+}
+
+func (c *psConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+	aProvider, err := c.getAnalyticsProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &analyticsIndexProviderPs{
+		core: &analyticsIndexProviderCore{
+			aProvider:     aProvider,
+			globalTimeout: c.timeouts.ManagementTimeout,
+			tracer:        c.tracer,
+			meter:         c.meter,
+		},
 	}, nil
 }
 
 func (c *psConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
-	return &analyticsProviderWrapper{}, ErrFeatureNotAvailable
+	return &analyticsProviderPs{
+		provider: c.clients.AnalyticsV1(),
+
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.AnalyticsTimeout, c.meter,
+			meterValueServiceAnalytics, c.breakers.forService(ServiceTypeAnalytics),
+			c.admission.forService(ServiceTypeAnalytics), nil),
+	}, nil
 }
 func (c *psConnectionMgr) getSearchProvider() (searchProvider, error) {
 	return &searchProviderPs{
-		provider: c.agent.SearchV1(),
+		provider: c.clients.SearchV1(),
 
-		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceSearch),
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, meterValueServiceSearch,
+			c.breakers.forService(ServiceTypeSearch), c.admission.forService(ServiceTypeSearch), nil),
 	}, nil
 }
 func (c *psConnectionMgr) getHTTPProvider(bucketName string) (httpProvider, error) {
 	return &httpProviderWrapper{}, ErrFeatureNotAvailable
 }
 func (c *psConnectionMgr) getDiagnosticsProvider(bucketName string) (diagnosticsProvider, error) {
-	return &diagnosticsProviderWrapper{}, ErrFeatureNotAvailable
+	return &diagnosticsProviderPs{
+		client: struct {
+			psServiceClients
+			psConnectionStateProvider
+		}{c.clients, c.agent},
+		host:       c.host,
+		bucketName: bucketName,
+	}, nil
 }
 func (c *psConnectionMgr) getWaitUntilReadyProvider(bucketName string) (waitUntilReadyProvider, error) {
 	return &waitUntilReadyProviderPs{
@@ -152,6 +241,11 @@ func (c *psConnectionMgr) connection(bucketName string) (*gocbcore.Agent, error)
 	return &gocbcore.Agent{}, ErrFeatureNotAvailable
 }
 func (c *psConnectionMgr) close() error {
+	c.keepAlive.stop()
+	c.monitor.stop()
+	if c.psConn != nil {
+		_ = c.psConn.Close()
+	}
 	return c.agent.Close()
 }
 
@@ -161,16 +255,23 @@ type psOpManagerProvider struct {
 	defaultTimeout       time.Duration
 	meter                *meterWrapper
 	service              string
+	breaker              *serviceCircuitBreaker
+	limiter              *admissionLimiter
+	interceptors         []OperationInterceptor
 }
 
 func newPsOpManagerProvider(retry RetryStrategy, tracer RequestTracer, timeout time.Duration, meter *meterWrapper,
-	service string) *psOpManagerProvider {
+	service string, breaker *serviceCircuitBreaker, limiter *admissionLimiter,
+	interceptors []OperationInterceptor) *psOpManagerProvider {
 	return &psOpManagerProvider{
 		defaultRetryStrategy: retry,
 		tracer:               tracer,
 		defaultTimeout:       timeout,
 		meter:                meter,
 		service:              service,
+		breaker:              breaker,
+		limiter:              limiter,
+		interceptors:         interceptors,
 	}
 }
 
@@ -181,6 +282,9 @@ func (p *psOpManagerProvider) NewManager(parentSpan RequestSpan, opName string,
 		defaultTimeout:       p.defaultTimeout,
 		meter:                p.meter,
 		service:              p.service,
+		breaker:              p.breaker,
+		limiter:              p.limiter,
+		interceptors:         p.interceptors,
 		createdTime:          time.Now(),
 
 		span:   createSpan(p.tracer, parentSpan, opName, p.service),
@@ -188,7 +292,7 @@ func (p *psOpManagerProvider) NewManager(parentSpan RequestSpan, opName string,
 	}
 
 	for key, value := range attribs {
-		m.span.SetAttribute(key, value)
+		m.span.SetAttribute(key, redactSpanAttribute(key, value))
 	}
 
 	return m
@@ -199,7 +303,13 @@ type psOpManager interface {
 	TraceSpanContext() RequestSpanContext
 	OperationID() string
 	RetryStrategy() RetryStrategy
+	Meter() *meterWrapper
 	OpName() string
+	Service() string
+	CircuitBreaker() *serviceCircuitBreaker
+	AdmissionLimiter() *admissionLimiter
+	Interceptors() []OperationInterceptor
+	RecordError(err error)
 	CreatedAt() time.Time
 	Tracer() RequestTracer
 	RetryInfo() retriedRequestInfo
@@ -215,7 +325,11 @@ type psOpManagerDefault struct {
 	defaultTimeout       time.Duration
 	meter                *meterWrapper
 	service              string
+	breaker              *serviceCircuitBreaker
+	limiter              *admissionLimiter
+	interceptors         []OperationInterceptor
 	createdTime          time.Time
+	opErr                error
 
 	span   RequestSpan
 	opName string
@@ -238,6 +352,7 @@ func (m *psOpManagerDefault) SetRetryStrategy(retryStrategy RetryStrategy) {
 	if retryStrategy != nil {
 		strat = retryStrategy
 	}
+	observeRetryBudgets(strat)
 	m.retryStrategy = strat
 }
 
@@ -262,10 +377,13 @@ func (m *psOpManagerDefault) NewSpan(name string) RequestSpan {
 }
 
 func (m *psOpManagerDefault) Finish(noMetrics bool) {
+	if m.req != nil {
+		spanSetRetryReasons(m.span, m.req.RetryReasons())
+	}
 	m.span.End()
 
 	if !noMetrics {
-		m.meter.ValueRecord(m.service, m.opName, m.createdTime)
+		m.meter.ValueRecordOutcome(meterOperation{service: m.service, operation: m.opName}, m.opErr, m.createdTime)
 	}
 }
 
@@ -277,6 +395,10 @@ func (m *psOpManagerDefault) TraceSpan() RequestSpan {
 	return m.span
 }
 
+func (m *psOpManagerDefault) Meter() *meterWrapper {
+	return m.meter
+}
+
 func (m *psOpManagerDefault) RetryStrategy() RetryStrategy {
 	return m.retryStrategy
 }
@@ -295,11 +417,13 @@ func (m *psOpManagerDefault) Timeout() time.Duration {
 
 func (m *psOpManagerDefault) CheckReadyForOp() error {
 	if m.Timeout() == 0 {
-		return errors.New("op manager had no timeout specified")
+		m.opErr = errors.New("op manager had no timeout specified")
+		return m.opErr
 	}
 
 	if m.span == nil {
-		return errors.New("op manager had no span specified")
+		m.opErr = errors.New("op manager had no span specified")
+		return m.opErr
 	}
 
 	return nil
@@ -317,6 +441,26 @@ func (m *psOpManagerDefault) OpName() string {
 	return m.opName
 }
 
+func (m *psOpManagerDefault) Service() string {
+	return m.service
+}
+
+func (m *psOpManagerDefault) CircuitBreaker() *serviceCircuitBreaker {
+	return m.breaker
+}
+
+func (m *psOpManagerDefault) AdmissionLimiter() *admissionLimiter {
+	return m.limiter
+}
+
+func (m *psOpManagerDefault) Interceptors() []OperationInterceptor {
+	return m.interceptors
+}
+
+func (m *psOpManagerDefault) RecordError(err error) {
+	m.opErr = err
+}
+
 func (m *psOpManagerDefault) RetryInfo() retriedRequestInfo {
 	return m.req
 }
@@ -356,11 +500,42 @@ func wrapPSOp[ReqT any, RespT any](m psOpManager, req ReqT,
 func wrapPSOpCtx[ReqT any, RespT any](ctx context.Context, m psOpManager,
 	req ReqT,
 	fn func(context.Context, ReqT, ...grpc.CallOption) (RespT, error)) (RespT, error) {
-	retryReq := newRetriableRequestPS(m.OpName(), m.IsIdempotent(), m.TraceSpanContext(), m.OperationID(), m.RetryStrategy())
+	limiter := m.AdmissionLimiter()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = m.CreatedAt().Add(m.Timeout())
+	}
+	if err := limiter.Acquire(ctx, deadline); err != nil {
+		m.RecordError(err)
+		var emptyResp RespT
+		return emptyResp, err
+	}
+	defer limiter.Release()
+
+	breaker := m.CircuitBreaker()
+	permit, ok := breaker.AllowsRequest()
+	if !ok {
+		err := breaker.openError()
+		m.RecordError(err)
+		var emptyResp RespT
+		return emptyResp, err
+	}
+
+	retryReq := newRetriableRequestPS(m.OpName(), m.Service(), m.IsIdempotent(), m.TraceSpanContext(), m.OperationID(),
+		m.RetryStrategy(), m.Meter())
 	m.SetRetryRequest(retryReq)
 
-	res, err := handleRetriableRequest(ctx, m.CreatedAt(), m.Tracer(), req, retryReq, fn, m.RetryReasonFor)
+	service, _ := serviceTypeFromMeterService(m.Service())
+	info := &OperationInfo{
+		Service:   service,
+		Operation: m.OpName(),
+	}
+	res, err := interceptRequest(ctx, m.Interceptors(), info, func(ctx context.Context) (RespT, error) {
+		return handleRetriableRequest(ctx, m.CreatedAt(), m.Tracer(), req, retryReq, fn, m.RetryReasonFor)
+	})
+	breaker.MarkComplete(permit, err)
 	if err != nil {
+		m.RecordError(err)
 		var emptyResp RespT
 		return emptyResp, err
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	gocbconnstr "github.com/couchbaselabs/gocbconnstr/v2"
+	"google.golang.org/grpc"
 )
 
 // Cluster represents a connection to a specific Couchbase cluster.
@@ -34,10 +35,15 @@ type Cluster struct {
 	meter  *meterWrapper
 
 	circuitBreakerConfig CircuitBreakerConfig
+	circuitBreakers      *serviceCircuitBreakers
+	admission            *admissionController
+	interceptors         *operationInterceptors
+	events               *eventBus
 	securityConfig       SecurityConfig
 	internalConfig       InternalConfig
 	transactionsConfig   TransactionsConfig
 	compressionConfig    CompressionConfig
+	protostellarConfig   ProtostellarConfig
 
 	transactions *Transactions
 }
@@ -85,7 +91,7 @@ type SecurityConfig struct {
 }
 
 // CompressionConfig specifies options for controlling compression applied to documents before sending to Couchbase
-// Server.
+// Server. This applies to both the couchbase and couchbase2 protocols.
 type CompressionConfig struct {
 	Disabled bool
 
@@ -95,6 +101,44 @@ type CompressionConfig struct {
 	MinRatio float64
 }
 
+// ProtostellarConfig specifies options for tuning the gRPC connections used by the couchbase2 protocol. These
+// options are ignored when using the couchbase protocol.
+// The connections are dialled by the underlying routing client, whose dial options only cover the pool size,
+// credentials and TLS. gRPC channel keepalive (HTTP/2 pings) and custom dial options are therefore not supported.
+// KeepAliveTime instead periodically sends a lightweight request over each connection, and the remaining options are
+// applied to every request.
+// VOLATILE: This API is subject to change at any time.
+type ProtostellarConfig struct {
+	// PoolSize specifies the number of connections to open to the cluster, defaults to 1.
+	PoolSize uint32
+
+	// KeepAliveTime specifies how often a request is sent over each connection to keep it from being considered idle.
+	// This is not gRPC channel keepalive, which isn't supported, so it will not detect a dead connection any sooner
+	// than a request would. Keepalive requests are disabled when this is zero.
+	KeepAliveTime time.Duration
+	// KeepAliveTimeout specifies how long to wait for a response to a keepalive request, defaults to 10 seconds.
+	KeepAliveTimeout time.Duration
+
+	// MaxSendMessageSize specifies the maximum size, in bytes, of a request message.
+	MaxSendMessageSize int
+	// MaxRecvMessageSize specifies the maximum size, in bytes, of a response message.
+	MaxRecvMessageSize int
+
+	// Compressor specifies the name of the gRPC compressor to use for requests, such as "gzip". The compressor must be
+	// registered with google.golang.org/grpc/encoding, gzip is registered by default.
+	Compressor string
+
+	// CallOptions specifies additional gRPC call options to apply to every request.
+	CallOptions []grpc.CallOption
+
+	// UnaryInterceptors and StreamInterceptors are invoked, in order, for every request. The connection used is only
+	// chosen once the interceptors have completed, so the *grpc.ClientConn passed to them is a placeholder for the
+	// cluster address which never connects or sends requests itself, its state is always idle. As with grpc the
+	// response is copied into the reply passed to unary interceptors once the invoker returns.
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+}
+
 // InternalConfig specifies options for controlling various internal
 // items.
 // Internal: This should never be used and is not supported.
@@ -129,11 +173,31 @@ type ClusterOptions struct {
 	Meter Meter
 
 	// OrphanReporterConfig specifies options for the orphan reporter.
+	// Disabled can't be set back to false over a config_profile connection string option, see ApplyProfile.
 	OrphanReporterConfig OrphanReporterConfig
 
 	// CircuitBreakerConfig specifies options for the circuit breakers.
+	// Disabled can't be set back to false over a config_profile connection string option, see ApplyProfile.
 	CircuitBreakerConfig CircuitBreakerConfig
 
+	// ServiceCircuitBreakerConfig specifies options for the circuit breakers for the HTTP based services.
+	// VOLATILE: This API is subject to change at any time.
+	ServiceCircuitBreakerConfig ServiceCircuitBreakerConfig
+
+	// AdmissionConfig specifies options for limiting the number of operations in flight to each service.
+	// VOLATILE: This API is subject to change at any time.
+	AdmissionConfig AdmissionConfig
+
+	// Interceptors specifies the interceptors that wrap every key-value, query, search, analytics and management
+	// operation. Interceptors are called in the order that they are specified.
+	// VOLATILE: This API is subject to change at any time.
+	Interceptors []OperationInterceptor
+
+	// CollectionInterceptors specifies interceptors for key-value operations against specific collections, which
+	// are used instead of Interceptors. The map is keyed by "bucket.scope.collection".
+	// VOLATILE: This API is subject to change at any time.
+	CollectionInterceptors map[string][]OperationInterceptor
+
 	// IoConfig specifies IO related configuration options.
 	IoConfig IoConfig
 
@@ -146,6 +210,10 @@ type ClusterOptions struct {
 	// CompressionConfig specifies compression related configuration options.
 	CompressionConfig CompressionConfig
 
+	// ProtostellarConfig specifies options for the gRPC connections used by the couchbase2 protocol.
+	// VOLATILE: This API is subject to change at any time.
+	ProtostellarConfig ProtostellarConfig
+
 	// Internal: This should never be used and is not supported.
 	InternalConfig InternalConfig
 }
@@ -229,6 +297,9 @@ func clusterFromOptions(opts ClusterOptions) *Cluster {
 		meter = agMeter
 	}
 
+	mw := newMeterWrapper(meter)
+	mw.isDefaultMeter = opts.Meter == nil
+
 	return &Cluster{
 		auth: opts.Authenticator,
 		timeoutsConfig: TimeoutsConfig{
@@ -250,12 +321,17 @@ func clusterFromOptions(opts ClusterOptions) *Cluster {
 		orphanLoggerSampleSize: opts.OrphanReporterConfig.SampleSize,
 		useServerDurations:     useServerDurations,
 		tracer:                 initialTracer,
-		meter:                  newMeterWrapper(meter),
+		meter:                  mw,
 		circuitBreakerConfig:   opts.CircuitBreakerConfig,
+		circuitBreakers:        newServiceCircuitBreakers(opts.ServiceCircuitBreakerConfig),
+		admission:              newAdmissionController(opts.AdmissionConfig),
+		interceptors:           newOperationInterceptors(opts.Interceptors, opts.CollectionInterceptors),
+		events:                 newEventBus(),
 		securityConfig:         opts.SecurityConfig,
 		internalConfig:         opts.InternalConfig,
 		transactionsConfig:     opts.TransactionsConfig,
 		compressionConfig:      opts.CompressionConfig,
+		protostellarConfig:     opts.ProtostellarConfig,
 	}
 }
 
@@ -271,6 +347,13 @@ func Connect(connStr string, opts ClusterOptions) (*Cluster, error) {
 		return nil, errors.New("http scheme is not supported")
 	}
 
+	if profiles := connSpec.Options["config_profile"]; len(profiles) > 0 {
+		opts, err = applyConnStrProfile(ClusterConfigProfile(profiles[len(profiles)-1]), opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	cluster := clusterFromOptions(opts)
 	cluster.cSpec = connSpec
 
@@ -381,6 +464,7 @@ func (c *Cluster) Bucket(bucketName string) *Bucket {
 	b := newBucket(c, bucketName)
 	err := c.connectionManager.openBucket(bucketName)
 	if err != nil {
+		c.events.publishAuthFailure(bucketName, err)
 		b.setBootstrapError(err)
 	}
 
@@ -436,7 +520,9 @@ func (c *Cluster) WaitUntilReady(timeout time.Duration, opts *WaitUntilReadyOpti
 		opts,
 	)
 	if err != nil {
-		return maybeEnhanceCoreErr(err)
+		err = maybeEnhanceCoreErr(err)
+		c.events.publishAuthFailure("", err)
+		return err
 	}
 
 	return nil
@@ -467,6 +553,8 @@ func (c *Cluster) Close(opts *ClusterCloseOptions) error {
 		tracerDecRef(c.tracer)
 		c.tracer = nil
 	}
+	c.events.close()
+
 	if c.meter != nil {
 		if meter, ok := c.meter.meter.(*LoggingMeter); ok {
 			meter.close()
@@ -540,6 +628,33 @@ func (c *Cluster) getHTTPProvider() (httpProvider, error) {
 	return provider, nil
 }
 
+func (c *Cluster) getUserManagementProvider() (userManagementProvider, error) {
+	provider, err := c.connectionManager.getUserManagementProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+func (c *Cluster) getEventingManagementProvider() (eventingManagementProvider, error) {
+	provider, err := c.connectionManager.getEventingManagementProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+func (c *Cluster) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+	provider, err := c.connectionManager.getAnalyticsIndexProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
 func (c *Cluster) getbucketManagementProvider() (bucketManagementProvider, error) {
 	provider, err := c.connectionManager.getBucketManagementProvider()
 	if err != nil {
@@ -552,9 +667,7 @@ func (c *Cluster) getbucketManagementProvider() (bucketManagementProvider, error
 // Users returns a UserManager for managing users.
 func (c *Cluster) Users() *UserManager {
 	return &UserManager{
-		provider: c,
-		tracer:   c.tracer,
-		meter:    c.meter,
+		getProvider: c.getUserManagementProvider,
 	}
 }
 
@@ -568,11 +681,7 @@ func (c *Cluster) Buckets() *BucketManager {
 // AnalyticsIndexes returns an AnalyticsIndexManager for managing analytics indexes.
 func (c *Cluster) AnalyticsIndexes() *AnalyticsIndexManager {
 	return &AnalyticsIndexManager{
-		aProvider:     c,
-		mgmtProvider:  c,
-		globalTimeout: c.timeoutsConfig.ManagementTimeout,
-		tracer:        c.tracer,
-		meter:         c.meter,
+		getProvider: c.getAnalyticsIndexProvider,
 	}
 }
 
@@ -594,13 +703,12 @@ func (c *Cluster) SearchIndexes() *SearchIndexManager {
 // UNCOMMITTED: This API may change in the future.
 func (c *Cluster) EventingFunctions() *EventingFunctionManager {
 	return &EventingFunctionManager{
-		mgmtProvider: c,
-		tracer:       c.tracer,
-		meter:        c.meter,
+		getProvider: c.getEventingManagementProvider,
 	}
 }
 
 // Transactions returns a Transactions instance for performing transactions.
+// Transactions are not supported when connected using the couchbase2 protocol.
 func (c *Cluster) Transactions() *Transactions {
 	return c.transactions
 }
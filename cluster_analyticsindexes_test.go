@@ -3,6 +3,9 @@ package gocb
 import (
 	"errors"
 	"net/url"
+	"time"
+
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
 )
 
 func (suite *IntegrationTestSuite) TestAnalyticsIndexesCrud() {
@@ -880,3 +883,35 @@ func (suite *UnitTestSuite) TestAnalyticsIndexesCouchbaseLinksFormEncode() {
 	suite.Assert().Equal("clientcertificate", q.Get("clientCertificate"))
 	suite.Assert().Equal("clientkey", q.Get("clientKey"))
 }
+
+func (suite *UnitTestSuite) TestAnalyticsIndexManagerPs() {
+	client := &fakePsAnalyticsClient{
+		stream: &fakePsAnalyticsStream{
+			responses: []*analytics_v1.AnalyticsQueryResponse{{}},
+		},
+	}
+	mgr := &AnalyticsIndexManager{
+		getProvider: func() (analyticsIndexProvider, error) {
+			return &analyticsIndexProviderPs{
+				core: &analyticsIndexProviderCore{
+					aProvider:     suite.newPsAnalyticsProvider(client),
+					globalTimeout: time.Second,
+					tracer:        &NoopTracer{},
+					meter:         &meterWrapper{meter: &NoopMeter{}},
+				},
+			}, nil
+		},
+	}
+
+	err := mgr.CreateDataverse("travel/inventory", &CreateAnalyticsDataverseOptions{IgnoreIfExists: true})
+	suite.Require().NoError(err)
+	suite.Assert().Equal("CREATE DATAVERSE `travel`.`inventory` IF NOT EXISTS", client.req.Statement)
+
+	_, err = mgr.GetPendingMutations(nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+	suite.Assert().Contains(err.Error(), "GetPendingMutations")
+
+	err = mgr.DropLink("link", "travel/inventory", nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+	suite.Assert().Contains(err.Error(), "DropLink")
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"time"
-
-	gocbcore "github.com/couchbase/gocbcore/v10"
 )
 
 type jsonAnalyticsMetrics struct {
@@ -148,6 +146,11 @@ func (arr *AnalyticsResultRaw) Close() error {
 	return nil
 }
 
+// cancel cancels the underlying request if the reader supports it, see rowReaderCanceler.
+func (arr *AnalyticsResultRaw) cancel() {
+	cancelRowReader(arr.reader)
+}
+
 // MetaData returns any meta-data that was available from this query as bytes.
 func (arr *AnalyticsResultRaw) MetaData() ([]byte, error) {
 	return arr.reader.MetaData()
@@ -185,6 +188,18 @@ func (r *AnalyticsResult) Raw() *AnalyticsResultRaw {
 	return vr
 }
 
+// rows returns an AnalyticsResultRaw sharing the reader of this result. Unlike Raw the result remains valid, so that
+// the meta-data can be accessed once the rows have been read.
+func (r *AnalyticsResult) rows() (*AnalyticsResultRaw, error) {
+	if r.reader == nil {
+		return nil, r.Err()
+	}
+
+	return &AnalyticsResultRaw{
+		reader: r.reader,
+	}, nil
+}
+
 // Next assigns the next result from the results into the value pointer, returning whether the read was successful.
 func (r *AnalyticsResult) Next() bool {
 	if r.reader == nil {
@@ -303,50 +318,35 @@ func (c *Cluster) AnalyticsQuery(statement string, opts *AnalyticsOptions) (*Ana
 		opts = &AnalyticsOptions{}
 	}
 
-	start := time.Now()
-	defer c.meter.ValueRecord(meterValueServiceAnalytics, "analytics", start)
-
-	span := createSpan(c.tracer, opts.ParentSpan, "analytics", "analytics")
-	span.SetAttribute("db.statement", statement)
-	defer span.End()
-
-	timeout := opts.Timeout
-	if opts.Timeout == 0 {
-		timeout = c.timeoutsConfig.AnalyticsTimeout
-	}
-	deadline := time.Now().Add(timeout)
-
-	retryStrategy := c.retryStrategyWrapper
-	if opts.RetryStrategy != nil {
-		retryStrategy = newCoreRetryStrategyWrapper(opts.RetryStrategy)
+	info := &OperationInfo{
+		Service:   ServiceTypeAnalytics,
+		Operation: "analytics",
+		Statement: statement,
+		Options:   copyOptions(opts),
 	}
+	return interceptOperation(opts.Context, c.interceptors.all(), info,
+		func(ctx context.Context, info *OperationInfo) (*AnalyticsResult, error) {
+			opts, err := interceptedOptions[AnalyticsOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
 
-	queryOpts, err := opts.toMap()
-	if err != nil {
-		return nil, AnalyticsError{
-			InnerError:      wrapError(err, "failed to generate query options"),
-			Statement:       statement,
-			ClientContextID: opts.ClientContextID,
-		}
-	}
-
-	var priorityInt int32
-	if opts.Priority {
-		priorityInt = -1
-	}
-
-	queryOpts["statement"] = statement
+			return c.analyticsQuery(statement, opts)
+		})
+}
 
+func (c *Cluster) analyticsQuery(statement string, opts *AnalyticsOptions) (*AnalyticsResult, error) {
 	provider, err := c.getAnalyticsProvider()
 	if err != nil {
 		return nil, AnalyticsError{
 			InnerError:      wrapError(err, "failed to get query provider"),
 			Statement:       statement,
-			ClientContextID: maybeGetAnalyticsOption(queryOpts, "client_context_id"),
+			ClientContextID: opts.ClientContextID,
 		}
 	}
 
-	return execAnalyticsQuery(opts.Context, span, queryOpts, priorityInt, deadline, retryStrategy, provider, c.tracer, opts.Internal.User)
+	return provider.AnalyticsQuery(statement, nil, opts)
 }
 
 func maybeGetAnalyticsOption(options map[string]interface{}, name string) string {
@@ -355,40 +355,3 @@ func maybeGetAnalyticsOption(options map[string]interface{}, name string) string
 	}
 	return ""
 }
-
-func execAnalyticsQuery(
-	ctx context.Context,
-	span RequestSpan,
-	options map[string]interface{},
-	priority int32,
-	deadline time.Time,
-	retryStrategy *coreRetryStrategyWrapper,
-	provider analyticsProvider,
-	tracer RequestTracer,
-	user string,
-) (*AnalyticsResult, error) {
-	eSpan := createSpan(tracer, span, "request_encoding", "")
-	reqBytes, err := json.Marshal(options)
-	eSpan.End()
-	if err != nil {
-		return nil, AnalyticsError{
-			InnerError:      wrapError(err, "failed to marshall query body"),
-			Statement:       maybeGetAnalyticsOption(options, "statement"),
-			ClientContextID: maybeGetAnalyticsOption(options, "client_context_id"),
-		}
-	}
-
-	res, err := provider.AnalyticsQuery(ctx, gocbcore.AnalyticsQueryOptions{
-		Payload:       reqBytes,
-		Priority:      int(priority),
-		RetryStrategy: retryStrategy,
-		Deadline:      deadline,
-		TraceContext:  span.Context(),
-		User:          user,
-	})
-	if err != nil {
-		return nil, maybeEnhanceAnalyticsError(err)
-	}
-
-	return newAnalyticsResult(res), nil
-}
@@ -0,0 +1,101 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v10"
+)
+
+// used to allow mocking for testing
+type analyticsProviderCoreProvider interface {
+	AnalyticsQuery(ctx context.Context, opts gocbcore.AnalyticsQueryOptions) (analyticsRowReader, error)
+}
+
+type analyticsProviderCore struct {
+	provider analyticsProviderCoreProvider
+
+	retryStrategyWrapper *coreRetryStrategyWrapper
+	timeouts             TimeoutsConfig
+	tracer               RequestTracer
+	meter                *meterWrapper
+}
+
+func (apc *analyticsProviderCore) AnalyticsQuery(statement string, s *Scope,
+	opts *AnalyticsOptions) (resOut *AnalyticsResult, errOut error) {
+	start := time.Now()
+	defer func() {
+		op := meterOperation{service: meterValueServiceAnalytics, operation: "analytics"}
+		if s != nil {
+			op.bucket = s.BucketName()
+			op.scope = s.Name()
+		}
+		apc.meter.ValueRecordOutcome(op, errOut, start)
+	}()
+
+	span := createSpan(apc.tracer, opts.ParentSpan, "analytics", "analytics")
+	span.SetAttribute(spanAttribDBStatementKey, redactUserData(statement))
+	if s != nil {
+		span.SetAttribute("db.name", s.BucketName())
+		span.SetAttribute("db.couchbase.scope", s.Name())
+	}
+	defer span.End()
+
+	timeout := opts.Timeout
+	if opts.Timeout == 0 {
+		timeout = apc.timeouts.AnalyticsTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	retryStrategy := apc.retryStrategyWrapper
+	if opts.RetryStrategy != nil {
+		retryStrategy = newCoreRetryStrategyWrapper(opts.RetryStrategy)
+	}
+	retryStrategy = retryStrategy.forService(ServiceTypeAnalytics, apc.meter)
+
+	queryOpts, err := opts.toMap()
+	if err != nil {
+		return nil, AnalyticsError{
+			InnerError:      wrapError(err, "failed to generate query options"),
+			Statement:       statement,
+			ClientContextID: opts.ClientContextID,
+		}
+	}
+
+	var priorityInt int32
+	if opts.Priority {
+		priorityInt = -1
+	}
+
+	queryOpts["statement"] = statement
+	if s != nil {
+		queryOpts["query_context"] = fmt.Sprintf("default:`%s`.`%s`", s.BucketName(), s.Name())
+	}
+
+	eSpan := createSpan(apc.tracer, span, "request_encoding", "")
+	reqBytes, err := json.Marshal(queryOpts)
+	eSpan.End()
+	if err != nil {
+		return nil, AnalyticsError{
+			InnerError:      wrapError(err, "failed to marshall query body"),
+			Statement:       maybeGetAnalyticsOption(queryOpts, "statement"),
+			ClientContextID: maybeGetAnalyticsOption(queryOpts, "client_context_id"),
+		}
+	}
+
+	res, err := apc.provider.AnalyticsQuery(opts.Context, gocbcore.AnalyticsQueryOptions{
+		Payload:       reqBytes,
+		Priority:      int(priorityInt),
+		RetryStrategy: retryStrategy,
+		Deadline:      deadline,
+		TraceContext:  span.Context(),
+		User:          opts.Internal.User,
+	})
+	if err != nil {
+		return nil, maybeEnhanceAnalyticsError(err)
+	}
+
+	return newAnalyticsResult(res), nil
+}
@@ -0,0 +1,5 @@
+package gocb
+
+type analyticsProvider interface {
+	AnalyticsQuery(statement string, s *Scope, opts *AnalyticsOptions) (*AnalyticsResult, error)
+}
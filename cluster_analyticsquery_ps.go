@@ -0,0 +1,460 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
+	"google.golang.org/grpc/status"
+)
+
+type analyticsProviderPs struct {
+	provider analytics_v1.AnalyticsServiceClient
+
+	managerProvider *psOpManagerProvider
+}
+
+var _ analyticsProvider = &analyticsProviderPs{}
+
+// AnalyticsQuery executes an analytics query against PS, taking care of the translation.
+func (apc *analyticsProviderPs) AnalyticsQuery(statement string, s *Scope, opts *AnalyticsOptions) (*AnalyticsResult, error) {
+	attribs := map[string]interface{}{
+		spanAttribDBStatementKey: statement,
+	}
+	if s != nil {
+		attribs["db.name"] = s.BucketName()
+		attribs["db.couchbase.scope"] = s.Name()
+	}
+
+	manager := apc.managerProvider.NewManager(opts.ParentSpan, "analytics", attribs)
+	defer manager.Finish(false)
+
+	manager.SetIsIdempotent(opts.Readonly)
+	manager.SetRetryStrategy(opts.RetryStrategy)
+	manager.SetTimeout(opts.Timeout)
+
+	if err := manager.CheckReadyForOp(); err != nil {
+		return nil, err
+	}
+
+	req := &analytics_v1.AnalyticsQueryRequest{
+		Statement: statement,
+	}
+	if s != nil {
+		req.BucketName = &s.bucket.bucketName
+		req.ScopeName = &s.scopeName
+	}
+	if opts.Readonly {
+		req.ReadOnly = &opts.Readonly
+	}
+	if opts.Priority {
+		req.Priority = &opts.Priority
+	}
+	if opts.ClientContextID == "" {
+		clientContextID := uuid.NewString()
+		req.ClientContextId = &clientContextID
+	} else {
+		req.ClientContextId = &opts.ClientContextID
+	}
+	manager.SetOperationID(*req.ClientContextId)
+
+	if opts.ScanConsistency != 0 {
+		var consistency analytics_v1.AnalyticsQueryRequest_ScanConsistency
+		if opts.ScanConsistency == AnalyticsScanConsistencyNotBounded {
+			consistency = analytics_v1.AnalyticsQueryRequest_SCAN_CONSISTENCY_NOT_BOUNDED
+		} else if opts.ScanConsistency == AnalyticsScanConsistencyRequestPlus {
+			consistency = analytics_v1.AnalyticsQueryRequest_SCAN_CONSISTENCY_REQUEST_PLUS
+		} else {
+			return nil, makeInvalidArgumentsError("unexpected consistency option")
+		}
+		req.ScanConsistency = &consistency
+	}
+
+	if opts.PositionalParameters != nil && opts.NamedParameters != nil {
+		return nil, makeInvalidArgumentsError("positional and named parameters must be used exclusively")
+	}
+	if len(opts.PositionalParameters) > 0 {
+		params := make([][]byte, len(opts.PositionalParameters))
+		for i, param := range opts.PositionalParameters {
+			b, err := json.Marshal(param)
+			if err != nil {
+				return nil, err
+			}
+
+			params[i] = b
+		}
+
+		req.PositionalParameters = params
+	}
+	if len(opts.NamedParameters) > 0 {
+		params := make(map[string][]byte, len(opts.NamedParameters))
+		for k, param := range opts.NamedParameters {
+			b, err := json.Marshal(param)
+			if err != nil {
+				return nil, err
+			}
+
+			params[k] = b
+		}
+
+		req.NamedParameters = params
+	}
+	if len(opts.Raw) > 0 {
+		return nil, wrapError(ErrFeatureNotAvailable, "raw options are not supported for the couchbase2 protocol")
+	}
+
+	userCtx := opts.Context
+	if userCtx == nil {
+		userCtx = context.Background()
+	}
+	// We create a context with a timeout which will control timing out the initial request portion
+	// of the operation. We can defer the cancel for this as we aren't applying this context directly
+	// to the request so cancellation will not terminate any streams.
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), manager.Timeout())
+	defer timeoutCancel()
+
+	var cancellationIsTimeout uint32
+	// This second context has no real parent and will be cancelled if the user context is cancelled or the timeout
+	// is reached. However, if the user context does not get cancelled during the initial request portion of the
+	// operation then this context will live for the lifetime of the op and be used for cancelled if the user calls
+	// Close on the result.
+	doneCh := make(chan struct{})
+	reqCtx, reqCancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-userCtx.Done():
+			if errors.Is(userCtx.Err(), context.DeadlineExceeded) {
+				atomic.StoreUint32(&cancellationIsTimeout, 1)
+			}
+			reqCancel()
+		case <-timeoutCtx.Done():
+			atomic.StoreUint32(&cancellationIsTimeout, 1)
+			reqCancel()
+		case <-doneCh:
+		}
+	}()
+
+	res, err := wrapPSOpCtx(reqCtx, manager, req, apc.provider.AnalyticsQuery)
+	close(doneCh)
+	if err != nil {
+		reqCancel()
+		return nil, apc.makeError(err, statement, *req.ClientContextId, opts.Readonly,
+			atomic.LoadUint32(&cancellationIsTimeout) == 1, manager.ElapsedTime(), manager.RetryInfo())
+	}
+
+	firstRows, err := res.Recv()
+	if err != nil {
+		reqCancel()
+		gocbErr := mapPsErrorToGocbError(err, opts.Readonly)
+		return nil, apc.makeError(gocbErr, statement, *req.ClientContextId, opts.Readonly,
+			atomic.LoadUint32(&cancellationIsTimeout) == 1, manager.ElapsedTime(), manager.RetryInfo())
+	}
+
+	return newAnalyticsResult(&analyticsProviderPsRowReader{
+		cli:        res,
+		cancelFunc: reqCancel,
+
+		statement:       statement,
+		readOnly:        opts.Readonly,
+		clientContextID: *req.ClientContextId,
+
+		nextRows: firstRows.Rows,
+		meta:     firstRows.MetaData,
+	}), nil
+}
+
+func (apc *analyticsProviderPs) makeError(err error, statement, clientContextID string, readonly, hasTimedOut bool,
+	elapsed time.Duration, retryInfo retriedRequestInfo) error {
+	var gocbErr *GenericError
+	if !errors.As(err, &gocbErr) {
+		return err
+	}
+
+	if errors.Is(err, ErrRequestCanceled) && hasTimedOut {
+		var innerErr error
+		if readonly {
+			innerErr = ErrUnambiguousTimeout
+		} else {
+			innerErr = ErrAmbiguousTimeout
+		}
+
+		return &TimeoutError{
+			InnerError:    innerErr,
+			TimeObserved:  elapsed,
+			OperationID:   retryInfo.Operation(),
+			Opaque:        retryInfo.Identifier(),
+			RetryReasons:  retryInfo.RetryReasons(),
+			RetryAttempts: retryInfo.RetryAttempts(),
+		}
+	}
+
+	analyticsErr := makePsAnalyticsError(gocbErr, statement, clientContextID)
+	analyticsErr.RetryReasons = retryInfo.RetryReasons()
+	analyticsErr.RetryAttempts = retryInfo.RetryAttempts()
+
+	return analyticsErr
+}
+
+func makePsAnalyticsError(gocbErr *GenericError, statement, clientContextID string) *AnalyticsError {
+	errorText, _ := gocbErr.Context["server"].(string)
+	descs := parsePsAnalyticsErrorDescs(errorText)
+
+	innerErr := gocbErr.InnerError
+	if len(descs) > 0 && isGenericPsAnalyticsError(innerErr) {
+		if codeErr := analyticsErrorFromCode(descs[0].Code); codeErr != nil {
+			innerErr = codeErr
+		}
+	}
+
+	return &AnalyticsError{
+		InnerError:      innerErr,
+		Statement:       statement,
+		ClientContextID: clientContextID,
+		Errors:          descs,
+		ErrorText:       errorText,
+	}
+}
+
+// psAnalyticsErrorCodeRegexp matches the analytics error code in the forms that the server reports it, "error 24045:"
+// and "code: 24045" or "\"code\":24045". Numbers elsewhere in the message, such as values echoed from the statement, are
+// not codes.
+var psAnalyticsErrorCodeRegexp = regexp.MustCompile(`(?i)\berror\s+(2[0-5]\d{3}):|\bcode"?\s*[:=]\s*(2[0-5]\d{3})\b`)
+
+// parsePsAnalyticsErrorDescs is temporary until protostellar gives us the analytics error codes, the server message
+// includes the code of the error which caused it.
+func parsePsAnalyticsErrorDescs(errorText string) []AnalyticsErrorDesc {
+	match := psAnalyticsErrorCodeRegexp.FindStringSubmatch(errorText)
+	if match == nil {
+		return nil
+	}
+
+	codeText := match[1]
+	if codeText == "" {
+		codeText = match[2]
+	}
+	code, err := strconv.ParseUint(codeText, 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	return []AnalyticsErrorDesc{{
+		Code:    uint32(code),
+		Message: errorText,
+	}}
+}
+
+// isGenericPsAnalyticsError returns whether the error mapped from the gRPC status is one which the analytics error
+// code can refine.
+func isGenericPsAnalyticsError(err error) bool {
+	if errors.Is(err, ErrInternalServerFailure) || errors.Is(err, ErrInvalidArgument) {
+		return true
+	}
+
+	_, isStatus := status.FromError(err)
+	return err != nil && isStatus
+}
+
+// analyticsErrorFromCode maps analytics error codes to errors in the same way as the classic protocol.
+func analyticsErrorFromCode(code uint32) error {
+	switch code {
+	case 23000, 23003:
+		return ErrTemporaryFailure
+	case 23007:
+		return ErrJobQueueFull
+	case 24000:
+		return ErrParsingFailure
+	case 24006:
+		return ErrLinkNotFound
+	case 24025, 24044, 24045:
+		return ErrDatasetNotFound
+	case 24034:
+		return ErrDataverseNotFound
+	case 24039:
+		return ErrDataverseExists
+	case 24040:
+		return ErrDatasetExists
+	case 24047:
+		return ErrIndexNotFound
+	case 24048:
+		return ErrIndexExists
+	}
+
+	switch code / 1000 {
+	case 20:
+		return ErrAuthenticationFailure
+	case 24:
+		return ErrCompilationFailure
+	case 25:
+		return ErrInternalServerFailure
+	}
+
+	return nil
+}
+
+type analyticsProviderPsRowReader struct {
+	cli        analytics_v1.AnalyticsService_AnalyticsQueryClient
+	cancelFunc context.CancelFunc
+
+	statement       string
+	readOnly        bool
+	clientContextID string
+
+	nextRowsIndex int
+	nextRows      [][]byte
+	err           error
+	meta          *analytics_v1.AnalyticsQueryResponse_MetaData
+}
+
+func (r *analyticsProviderPsRowReader) NextRow() []byte {
+	if r.nextRowsIndex < len(r.nextRows) {
+		row := r.nextRows[r.nextRowsIndex]
+		r.nextRowsIndex++
+		return row
+	}
+
+	// The stream has already been finished, either by reaching the end or by an error.
+	if r.cli == nil {
+		return nil
+	}
+
+	res, err := r.cli.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			r.finishWithoutError()
+			return nil
+		}
+		r.finishWithError(err)
+		return nil
+	}
+
+	r.nextRows = res.Rows
+	r.nextRowsIndex = 1
+	if res.MetaData != nil {
+		r.meta = res.MetaData
+	}
+
+	if len(res.Rows) > 0 {
+		return res.Rows[0]
+	}
+
+	return nil
+}
+
+func (r *analyticsProviderPsRowReader) Err() error {
+	if r.err == nil {
+		return nil
+	}
+
+	return makePsAnalyticsError(mapPsErrorToGocbError(r.err, r.readOnly), r.statement, r.clientContextID)
+}
+
+func (r *analyticsProviderPsRowReader) MetaData() ([]byte, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if r.cli != nil {
+		return nil, errors.New("the result must be fully read before accessing the meta-data")
+	}
+	if r.meta == nil {
+		return nil, errors.New("an error occurred during querying which has made the meta-data unavailable")
+	}
+
+	meta := jsonAnalyticsResponse{
+		RequestID:       r.meta.RequestId,
+		ClientContextID: r.meta.ClientContextId,
+		Status:          r.meta.Status,
+	}
+	if len(r.meta.Signature) > 0 {
+		meta.Signature = json.RawMessage(r.meta.Signature)
+	}
+
+	if len(r.meta.Warnings) > 0 {
+		meta.Warnings = make([]jsonAnalyticsWarning, len(r.meta.Warnings))
+		for i, warning := range r.meta.Warnings {
+			meta.Warnings[i] = jsonAnalyticsWarning{
+				Code:    warning.Code,
+				Message: warning.Message,
+			}
+		}
+	}
+
+	if r.meta.Metrics != nil {
+		meta.Metrics = jsonAnalyticsMetrics{
+			ElapsedTime:      r.meta.Metrics.ElapsedTime.AsDuration().String(),
+			ExecutionTime:    r.meta.Metrics.ExecutionTime.AsDuration().String(),
+			ResultCount:      r.meta.Metrics.ResultCount,
+			ResultSize:       r.meta.Metrics.ResultSize,
+			MutationCount:    r.meta.Metrics.MutationCount,
+			SortCount:        r.meta.Metrics.SortCount,
+			ErrorCount:       r.meta.Metrics.ErrorCount,
+			WarningCount:     r.meta.Metrics.WarningCount,
+			ProcessedObjects: r.meta.Metrics.ProcessedObjects,
+		}
+	}
+
+	return json.Marshal(meta)
+}
+
+func (r *analyticsProviderPsRowReader) Close() error {
+	if r.err != nil {
+		return r.Err()
+	}
+	// if the client is nil then we must be closed already.
+	if r.cli == nil {
+		return nil
+	}
+	r.cancelFunc()
+	err := r.cli.CloseSend()
+	r.cli = nil
+	return err
+}
+
+// cancel cancels the request, causing a pending Recv to fail. It only cancels the context of the request and so, unlike
+// Close, is safe to call whilst another goroutine is reading rows.
+func (r *analyticsProviderPsRowReader) cancel() {
+	r.cancelFunc()
+}
+
+func (r *analyticsProviderPsRowReader) logFields(err error) []LogField {
+	return []LogField{
+		{Key: LogFieldComponent, Value: "analytics"},
+		{Key: LogFieldClientContextID, Value: r.clientContextID},
+		{Key: LogFieldError, Value: err},
+	}
+}
+
+func (r *analyticsProviderPsRowReader) finishWithoutError() {
+	r.cancelFunc()
+	// Close the stream now that we are done with it
+	err := r.cli.CloseSend()
+	if err != nil {
+		logWarnfWithFields(r.logFields(err), "analytics stream close failed after meta-data: %s", err)
+	}
+
+	r.cli = nil
+}
+
+func (r *analyticsProviderPsRowReader) finishWithError(err error) {
+	// Lets record the error that happened
+	r.err = err
+	r.cancelFunc()
+
+	// Lets Close the underlying stream
+	closeErr := r.cli.CloseSend()
+	if closeErr != nil {
+		// We log this at debug level, but its almost always going to be an
+		// error since thats the most likely reason we are in finishWithError
+		logDebugfWithFields(r.logFields(closeErr), "analytics stream close failed after error: %s", closeErr)
+	}
+
+	// Our client is invalidated as soon as an error occurs
+	r.cli = nil
+}
@@ -0,0 +1,232 @@
+package gocb
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type fakePsAnalyticsStream struct {
+	grpc.ClientStream
+
+	responses []*analytics_v1.AnalyticsQueryResponse
+	err       error
+
+	// block causes Recv to wait for the request context to be done once the responses have been sent, as though the
+	// server were slow to send the next response.
+	block bool
+	ctx   context.Context
+}
+
+func (s *fakePsAnalyticsStream) Recv() (*analytics_v1.AnalyticsQueryResponse, error) {
+	if len(s.responses) == 0 {
+		if s.block {
+			<-s.ctx.Done()
+			return nil, status.Error(codes.Canceled, s.ctx.Err().Error())
+		}
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+
+	res := s.responses[0]
+	s.responses = s.responses[1:]
+	return res, nil
+}
+
+func (s *fakePsAnalyticsStream) RecvMsg(m interface{}) error {
+	res, err := s.Recv()
+	if err != nil {
+		return err
+	}
+
+	proto.Merge(m.(proto.Message), res)
+	return nil
+}
+
+func (s *fakePsAnalyticsStream) CloseSend() error {
+	return nil
+}
+
+type fakePsAnalyticsClient struct {
+	req    *analytics_v1.AnalyticsQueryRequest
+	stream *fakePsAnalyticsStream
+	err    error
+}
+
+func (c *fakePsAnalyticsClient) AnalyticsQuery(ctx context.Context, in *analytics_v1.AnalyticsQueryRequest,
+	_ ...grpc.CallOption) (analytics_v1.AnalyticsService_AnalyticsQueryClient, error) {
+	c.req = in
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.stream.ctx = ctx
+	return c.stream, nil
+}
+
+func (suite *UnitTestSuite) newPsAnalyticsProvider(client analytics_v1.AnalyticsServiceClient) *analyticsProviderPs {
+	return &analyticsProviderPs{
+		provider: client,
+		managerProvider: newPsOpManagerProvider(NewBestEffortRetryStrategy(nil), &NoopTracer{}, 75*time.Second,
+			newMeterWrapper(&NoopMeter{}), meterValueServiceAnalytics, nil, nil, nil),
+	}
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryPs() {
+	client := &fakePsAnalyticsClient{
+		stream: &fakePsAnalyticsStream{
+			responses: []*analytics_v1.AnalyticsQueryResponse{
+				{Rows: [][]byte{[]byte(`{"name":"a"}`), []byte(`{"name":"b"}`)}},
+				{
+					Rows: [][]byte{[]byte(`{"name":"c"}`)},
+					MetaData: &analytics_v1.AnalyticsQueryResponse_MetaData{
+						RequestId:       "request",
+						ClientContextId: "context",
+						Status:          "success",
+						Signature:       []byte(`{"*":"*"}`),
+						Warnings: []*analytics_v1.AnalyticsQueryResponse_MetaData_Warning{
+							{Code: 1, Message: "careful"},
+						},
+						Metrics: &analytics_v1.AnalyticsQueryResponse_Metrics{
+							ElapsedTime:      durationpb.New(20 * time.Millisecond),
+							ExecutionTime:    durationpb.New(10 * time.Millisecond),
+							ResultCount:      3,
+							ProcessedObjects: 5,
+						},
+					},
+				},
+			},
+		},
+	}
+	provider := suite.newPsAnalyticsProvider(client)
+
+	scope := &Scope{bucket: &Bucket{bucketName: "travel"}, scopeName: "inventory"}
+	result, err := provider.AnalyticsQuery("SELECT * FROM airline WHERE name = $name", scope, &AnalyticsOptions{
+		ClientContextID: "context",
+		Priority:        true,
+		Readonly:        true,
+		ScanConsistency: AnalyticsScanConsistencyRequestPlus,
+		NamedParameters: map[string]interface{}{"name": "a"},
+	})
+	suite.Require().NoError(err)
+
+	req := client.req
+	suite.Assert().Equal("travel", req.GetBucketName())
+	suite.Assert().Equal("inventory", req.GetScopeName())
+	suite.Assert().Equal("context", req.GetClientContextId())
+	suite.Assert().True(req.GetPriority())
+	suite.Assert().True(req.GetReadOnly())
+	suite.Assert().Equal(analytics_v1.AnalyticsQueryRequest_SCAN_CONSISTENCY_REQUEST_PLUS, req.GetScanConsistency())
+	suite.Assert().Equal(map[string][]byte{"name": []byte(`"a"`)}, req.NamedParameters)
+
+	var names []string
+	for result.Next() {
+		var row struct {
+			Name string `json:"name"`
+		}
+		suite.Require().NoError(result.Row(&row))
+		names = append(names, row.Name)
+	}
+	suite.Require().NoError(result.Err())
+	suite.Assert().Equal([]string{"a", "b", "c"}, names)
+
+	meta, err := result.MetaData()
+	suite.Require().NoError(err)
+	suite.Assert().Equal("request", meta.RequestID)
+	suite.Assert().Equal("context", meta.ClientContextID)
+	suite.Assert().Equal(map[string]interface{}{"*": "*"}, meta.Signature)
+	suite.Assert().Equal([]AnalyticsWarning{{Code: 1, Message: "careful"}}, meta.Warnings)
+	suite.Assert().Equal(20*time.Millisecond, meta.Metrics.ElapsedTime)
+	suite.Assert().Equal(uint64(3), meta.Metrics.ResultCount)
+	suite.Assert().Equal(uint64(5), meta.Metrics.ProcessedObjects)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryPsErrors() {
+	provider := suite.newPsAnalyticsProvider(&fakePsAnalyticsClient{
+		stream: &fakePsAnalyticsStream{err: status.Error(codes.InvalidArgument, "syntax error")},
+	})
+
+	_, err := provider.AnalyticsQuery("SELEC 1", nil, &AnalyticsOptions{ClientContextID: "context"})
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	var analyticsErr *AnalyticsError
+	suite.Require().ErrorAs(err, &analyticsErr)
+	suite.Assert().Equal("SELEC 1", analyticsErr.Statement)
+	suite.Assert().Equal("context", analyticsErr.ClientContextID)
+	suite.Assert().Equal("syntax error", analyticsErr.ErrorText)
+
+	suite.Assert().Empty(analyticsErr.Errors)
+
+	// The error code in the server message refines the error.
+	provider = suite.newPsAnalyticsProvider(&fakePsAnalyticsClient{
+		stream: &fakePsAnalyticsStream{
+			err: status.Error(codes.Internal, "analytics error 24045: Cannot find dataset with name missing"),
+		},
+	})
+	_, err = provider.AnalyticsQuery("SELECT * FROM missing", nil, &AnalyticsOptions{})
+	suite.Require().ErrorIs(err, ErrDatasetNotFound)
+	suite.Require().ErrorAs(err, &analyticsErr)
+	suite.Assert().Equal([]AnalyticsErrorDesc{
+		{Code: 24045, Message: "analytics error 24045: Cannot find dataset with name missing"},
+	}, analyticsErr.Errors)
+
+	// The code is also reported in the same way as the classic protocol does.
+	suite.Assert().Equal([]AnalyticsErrorDesc{{Code: 24047, Message: `{"code": 24047, "msg": "Cannot find index"}`}},
+		parsePsAnalyticsErrorDescs(`{"code": 24047, "msg": "Cannot find index"}`))
+
+	// Numbers in the message which aren't the error code, such as values from the statement, don't refine the error.
+	for _, text := range []string{
+		"Syntax error: In line 1 >>SELECT * FROM airline WHERE id = 24045 AND<< Encountered <EOF>",
+		"analytics error: statement exceeded limit 25000",
+		"error 24045 occurred in the value",
+	} {
+		suite.Assert().Empty(parsePsAnalyticsErrorDescs(text), text)
+
+		provider = suite.newPsAnalyticsProvider(&fakePsAnalyticsClient{
+			stream: &fakePsAnalyticsStream{err: status.Error(codes.Internal, text)},
+		})
+		_, err = provider.AnalyticsQuery("SELECT * FROM airline WHERE id = 24045", nil, &AnalyticsOptions{})
+		suite.Assert().ErrorIs(err, ErrInternalServerFailure, text)
+		suite.Assert().NotErrorIs(err, ErrDatasetNotFound, text)
+	}
+
+	// Codes don't override errors which are already specific.
+	provider = suite.newPsAnalyticsProvider(&fakePsAnalyticsClient{
+		stream: &fakePsAnalyticsStream{err: status.Error(codes.PermissionDenied, "error 20001: not allowed")},
+	})
+	_, err = provider.AnalyticsQuery("SELECT 1", nil, &AnalyticsOptions{})
+	suite.Require().ErrorIs(err, ErrAuthenticationFailure)
+	suite.Require().ErrorAs(err, &analyticsErr)
+	suite.Assert().Equal(uint32(20001), analyticsErr.Errors[0].Code)
+
+	// Errors part way through the stream are surfaced from the result.
+	provider = suite.newPsAnalyticsProvider(&fakePsAnalyticsClient{
+		stream: &fakePsAnalyticsStream{
+			responses: []*analytics_v1.AnalyticsQueryResponse{{Rows: [][]byte{[]byte(`1`)}}},
+			err:       status.Error(codes.PermissionDenied, "no access"),
+		},
+	})
+	result, err := provider.AnalyticsQuery("SELECT 1", nil, &AnalyticsOptions{})
+	suite.Require().NoError(err)
+	suite.Assert().True(result.Next())
+	suite.Assert().False(result.Next())
+	suite.Assert().ErrorIs(result.Err(), ErrAuthenticationFailure)
+	suite.Assert().ErrorAs(result.Close(), &analyticsErr)
+
+	_, err = provider.AnalyticsQuery("SELECT 1", nil, &AnalyticsOptions{
+		PositionalParameters: []interface{}{1},
+		NamedParameters:      map[string]interface{}{"a": 1},
+	})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	_, err = provider.AnalyticsQuery("SELECT 1", nil, &AnalyticsOptions{Raw: map[string]interface{}{"a": 1}})
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+}
@@ -294,15 +294,12 @@ func (suite *UnitTestSuite) TestAnalyticsQueryResultsCloseErr() {
 
 func (suite *UnitTestSuite) TestAnalyticsQueryUntypedError() {
 	retErr := errors.New("an error")
-	analyticsProvider := new(mockAnalyticsProvider)
+	analyticsProvider := new(mockAnalyticsProviderCoreProvider)
 	analyticsProvider.
 		On("AnalyticsQuery", nil, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
 		Return(nil, retErr)
 
-	cli := new(mockConnectionManager)
-	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
-
-	cluster := suite.newCluster(cli)
+	cluster := suite.newAnalyticsCluster(analyticsProvider)
 
 	result, err := cluster.AnalyticsQuery("SELECT * FROM dataset", nil)
 	suite.Require().Equal(retErr, err)
@@ -317,15 +314,12 @@ func (suite *UnitTestSuite) TestAnalyticsQueryGocbcoreError() {
 		Errors:          []gocbcore.AnalyticsErrorDesc{{Code: 24001, Message: "Compilation error:"}},
 	}
 
-	analyticsProvider := new(mockAnalyticsProvider)
+	analyticsProvider := new(mockAnalyticsProviderCoreProvider)
 	analyticsProvider.
 		On("AnalyticsQuery", nil, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
 		Return(nil, retErr)
 
-	cli := new(mockConnectionManager)
-	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
-
-	cluster := suite.newCluster(cli)
+	cluster := suite.newAnalyticsCluster(analyticsProvider)
 
 	result, err := cluster.AnalyticsQuery("SELECT * FROM dataset", nil)
 	suite.Require().IsType(&AnalyticsError{}, err)
@@ -343,7 +337,7 @@ func (suite *UnitTestSuite) TestAnalyticsQueryPriority() {
 
 	statement := "SELECT * FROM dataset"
 
-	analyticsProvider := new(mockAnalyticsProvider)
+	analyticsProvider := new(mockAnalyticsProviderCoreProvider)
 	analyticsProvider.
 		On("AnalyticsQuery", nil, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
 		Run(func(args mock.Arguments) {
@@ -352,10 +346,7 @@ func (suite *UnitTestSuite) TestAnalyticsQueryPriority() {
 		}).
 		Return(reader, nil)
 
-	cli := new(mockConnectionManager)
-	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
-
-	cluster := suite.newCluster(cli)
+	cluster := suite.newAnalyticsCluster(analyticsProvider)
 
 	result, err := cluster.AnalyticsQuery(statement, &AnalyticsOptions{
 		Priority: true,
@@ -389,15 +380,12 @@ func (suite *UnitTestSuite) TestAnalyticsQueryTimeoutOption() {
 
 func (suite *UnitTestSuite) TestAnalyticsQueryGCCCPUnsupported() {
 	retErr := errors.New("an error")
-	analyticsProvider := new(mockAnalyticsProvider)
+	analyticsProvider := new(mockAnalyticsProviderCoreProvider)
 	analyticsProvider.
 		On("AnalyticsQuery", nil, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
 		Return(nil, retErr)
 
-	cli := new(mockConnectionManager)
-	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
-
-	cluster := suite.newCluster(cli)
+	cluster := suite.newAnalyticsCluster(analyticsProvider)
 
 	_, err := cluster.AnalyticsQuery("SELECT * FROM dataset", nil)
 	suite.Require().NotNil(err)
@@ -470,12 +458,9 @@ func (suite *UnitTestSuite) TestAnalyticsQueryBothParams() {
 		"$cilit":  "bang",
 	}
 
-	analyticsProvider := new(mockAnalyticsProvider)
+	analyticsProvider := new(mockAnalyticsProviderCoreProvider)
 
-	cli := new(mockConnectionManager)
-	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
-
-	cluster := suite.newCluster(cli)
+	cluster := suite.newAnalyticsCluster(analyticsProvider)
 
 	result, err := cluster.AnalyticsQuery(statement, &AnalyticsOptions{
 		PositionalParameters: params,
@@ -616,12 +601,9 @@ func (suite *UnitTestSuite) TestAnalyticsQueryConsistencyRequestPlus() {
 func (suite *UnitTestSuite) TestAnalyticsQueryConsistencyInvalid() {
 	statement := "SELECT * FROM dataset"
 
-	analyticsProvider := new(mockAnalyticsProvider)
-
-	cli := new(mockConnectionManager)
-	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
+	analyticsProvider := new(mockAnalyticsProviderCoreProvider)
 
-	cluster := suite.newCluster(cli)
+	cluster := suite.newAnalyticsCluster(analyticsProvider)
 
 	result, err := cluster.AnalyticsQuery(statement, &AnalyticsOptions{
 		ScanConsistency: 5,
@@ -634,17 +616,30 @@ func (suite *UnitTestSuite) TestAnalyticsQueryConsistencyInvalid() {
 }
 
 func (suite *UnitTestSuite) analyticsCluster(ctx context.Context, runFn func(args mock.Arguments), reader analyticsRowReader) *Cluster {
-	analyticsProvider := new(mockAnalyticsProvider)
+	analyticsProvider := new(mockAnalyticsProviderCoreProvider)
 	analyticsProvider.
 		On("AnalyticsQuery", ctx, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
 		Run(runFn).
 		Return(reader, nil)
 
+	return suite.newAnalyticsCluster(analyticsProvider)
+}
+
+func (suite *UnitTestSuite) newAnalyticsCluster(provider analyticsProviderCoreProvider) *Cluster {
+	analyticsProvider := &analyticsProviderCore{
+		provider: provider,
+	}
+
 	cli := new(mockConnectionManager)
 	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
 
 	cluster := suite.newCluster(cli)
 
+	analyticsProvider.meter = cluster.meter
+	analyticsProvider.tracer = cluster.tracer
+	analyticsProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	analyticsProvider.timeouts = cluster.timeoutsConfig
+
 	return cluster
 }
 
@@ -9,7 +9,8 @@ import (
 	"github.com/google/uuid"
 )
 
-// EndPointDiagnostics represents a single entry in a diagnostics report.
+// EndPointDiagnostics represents a single entry in a diagnostics report. LastActivity is the zero time when the
+// activity of the endpoint isn't known, such as when using the couchbase2 protocol.
 type EndPointDiagnostics struct {
 	Type         ServiceType
 	ID           string
@@ -61,11 +62,16 @@ func (report *DiagnosticsResult) MarshalJSON() ([]byte, error) {
 			serviceStr := serviceTypeToString(service.Type)
 			stateStr := endpointStateToString(service.State)
 
+			var lastActivity uint64
+			if !service.LastActivity.IsZero() {
+				lastActivity = uint64(time.Since(service.LastActivity).Nanoseconds())
+			}
+
 			jsonReport.Services[serviceStr] = append(jsonReport.Services[serviceStr], jsonDiagnosticEntry{
 				ID:             service.ID,
-				LastActivityUs: uint64(time.Since(service.LastActivity).Nanoseconds()),
-				Remote:         service.Remote,
-				Local:          service.Local,
+				LastActivityUs: lastActivity,
+				Remote:         redactSystemData(service.Remote),
+				Local:          redactSystemData(service.Local),
 				State:          stateStr,
 				Details:        "",
 				Namespace:      service.Namespace,
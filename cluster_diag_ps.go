@@ -0,0 +1,224 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/couchbase/gocbcoreps"
+	"github.com/couchbase/goprotostellar/genproto/admin_bucket_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_search_v1"
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
+	"github.com/couchbase/goprotostellar/genproto/kv_v1"
+	"github.com/couchbase/goprotostellar/genproto/query_v1"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// used to allow mocking for testing
+type psDiagnosticsClient interface {
+	ConnectionState() gocbcoreps.ConnState
+	KvV1() kv_v1.KvServiceClient
+	QueryV1() query_v1.QueryServiceClient
+	AnalyticsV1() analytics_v1.AnalyticsServiceClient
+	SearchAdminV1() admin_search_v1.SearchAdminServiceClient
+	BucketV1() admin_bucket_v1.BucketAdminServiceClient
+}
+
+// diagnosticsProviderPs provides diagnostics and ping over PS. The routing client does not expose the state of the
+// individual grpc channels within its pool so the connection to the host is reported as a single endpoint.
+type diagnosticsProviderPs struct {
+	client     psDiagnosticsClient
+	host       string
+	bucketName string
+}
+
+var _ diagnosticsProvider = &diagnosticsProviderPs{}
+
+func (dp *diagnosticsProviderPs) Diagnostics(opts gocbcore.DiagnosticsOptions) (*gocbcore.DiagnosticInfo, error) {
+	var clusterState gocbcore.ClusterState
+	endpointState := gocbcore.EndpointStateConnected
+	switch dp.client.ConnectionState() {
+	case gocbcoreps.ConnStateOnline:
+		clusterState = gocbcore.ClusterStateOnline
+	case gocbcoreps.ConnStateDegraded:
+		clusterState = gocbcore.ClusterStateDegraded
+	default:
+		clusterState = gocbcore.ClusterStateOffline
+		endpointState = gocbcore.EndpointStateDisconnected
+	}
+
+	return &gocbcore.DiagnosticInfo{
+		State: clusterState,
+		MemdConns: []gocbcore.MemdConnInfo{
+			{
+				RemoteAddr: dp.host,
+				// grpc does not expose activity times, so LastActivity is left zero rather than made up.
+				Scope: dp.bucketName,
+				State: endpointState,
+			},
+		},
+	}, nil
+}
+
+func (dp *diagnosticsProviderPs) Ping(ctx context.Context, opts gocbcore.PingOptions) (*gocbcore.PingResult, error) {
+	services := opts.ServiceTypes
+	if len(services) == 0 {
+		services = []gocbcore.ServiceType{gocbcore.CbasService, gocbcore.FtsService, gocbcore.N1qlService,
+			gocbcore.MgmtService}
+		if dp.bucketName != "" {
+			services = append([]gocbcore.ServiceType{gocbcore.MemdService}, services...)
+		}
+	}
+
+	for _, svc := range services {
+		switch svc {
+		case gocbcore.MemdService:
+			if dp.bucketName == "" {
+				return nil, makeInvalidArgumentsError("kv service is not valid for use without a bucket")
+			}
+		case gocbcore.CapiService:
+			return nil, wrapError(ErrFeatureNotAvailable, "views are not supported by the couchbase2 protocol")
+		}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	type servicePingResult struct {
+		service gocbcore.ServiceType
+		result  gocbcore.EndpointPingResult
+	}
+
+	resultsCh := make(chan servicePingResult, len(services))
+	for _, svc := range services {
+		go func(svc gocbcore.ServiceType) {
+			resultsCh <- servicePingResult{
+				service: svc,
+				result:  dp.pingService(ctx, svc, dp.pingDeadline(svc, opts)),
+			}
+		}(svc)
+	}
+
+	result := &gocbcore.PingResult{
+		Services: make(map[gocbcore.ServiceType][]gocbcore.EndpointPingResult),
+	}
+	for range services {
+		res := <-resultsCh
+		result.Services[res.service] = append(result.Services[res.service], res.result)
+	}
+
+	return result, nil
+}
+
+func (dp *diagnosticsProviderPs) pingDeadline(svc gocbcore.ServiceType, opts gocbcore.PingOptions) time.Time {
+	switch svc {
+	case gocbcore.MemdService:
+		return opts.KVDeadline
+	case gocbcore.N1qlService:
+		return opts.N1QLDeadline
+	case gocbcore.CbasService:
+		return opts.CbasDeadline
+	case gocbcore.FtsService:
+		return opts.FtsDeadline
+	default:
+		return opts.MgmtDeadline
+	}
+}
+
+func (dp *diagnosticsProviderPs) pingService(ctx context.Context, svc gocbcore.ServiceType,
+	deadline time.Time) gocbcore.EndpointPingResult {
+	var cancel context.CancelFunc
+	if deadline.IsZero() {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	result := gocbcore.EndpointPingResult{
+		Endpoint: dp.host,
+		ID:       uuid.NewString(),
+		State:    gocbcore.PingStateOK,
+	}
+	if svc == gocbcore.MemdService {
+		result.Scope = dp.bucketName
+	}
+
+	start := time.Now()
+	err := dp.sendPing(ctx, svc)
+	result.Latency = time.Since(start)
+	if err == nil || psPingResponded(err) {
+		return result
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		result.State = gocbcore.PingStateTimeout
+		result.Error = ErrUnambiguousTimeout
+		return result
+	}
+
+	result.State = gocbcore.PingStateError
+	result.Error = mapPsErrorToGocbError(err, true)
+	return result
+}
+
+// sendPing issues a lightweight request to the service, PS has no dedicated ping so any response from the service
+// tells us that it is reachable.
+func (dp *diagnosticsProviderPs) sendPing(ctx context.Context, svc gocbcore.ServiceType) error {
+	switch svc {
+	case gocbcore.MemdService:
+		_, err := dp.client.KvV1().Exists(ctx, &kv_v1.ExistsRequest{
+			BucketName:     dp.bucketName,
+			ScopeName:      "_default",
+			CollectionName: "_default",
+			Key:            "gocb-ping",
+		})
+		return err
+	case gocbcore.N1qlService:
+		res, err := dp.client.QueryV1().Query(ctx, &query_v1.QueryRequest{
+			Statement: "SELECT 1=1",
+		})
+		if err != nil {
+			return err
+		}
+		_, err = res.Recv()
+		return err
+	case gocbcore.CbasService:
+		res, err := dp.client.AnalyticsV1().AnalyticsQuery(ctx, &analytics_v1.AnalyticsQueryRequest{
+			Statement: "SELECT 1=1",
+		})
+		if err != nil {
+			return err
+		}
+		_, err = res.Recv()
+		return err
+	case gocbcore.FtsService:
+		_, err := dp.client.SearchAdminV1().ListIndexes(ctx, &admin_search_v1.ListIndexesRequest{})
+		return err
+	case gocbcore.MgmtService:
+		_, err := dp.client.BucketV1().ListBuckets(ctx, &admin_bucket_v1.ListBucketsRequest{})
+		return err
+	}
+
+	return makeInvalidArgumentsError("unsupported service type for ping")
+}
+
+// psPingResponded returns whether an error from a ping request came from the service itself, rather than from the
+// request failing to reach it.
+func psPingResponded(err error) bool {
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound, codes.AlreadyExists, codes.PermissionDenied, codes.InvalidArgument, codes.FailedPrecondition:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,180 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/couchbase/gocbcoreps"
+	"github.com/couchbase/goprotostellar/genproto/admin_bucket_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_search_v1"
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
+	"github.com/couchbase/goprotostellar/genproto/kv_v1"
+	"github.com/couchbase/goprotostellar/genproto/query_v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakePsDiagnosticsClient struct {
+	state     gocbcoreps.ConnState
+	kv        kv_v1.KvServiceClient
+	query     query_v1.QueryServiceClient
+	analytics analytics_v1.AnalyticsServiceClient
+	search    admin_search_v1.SearchAdminServiceClient
+	bucket    admin_bucket_v1.BucketAdminServiceClient
+}
+
+func (c *fakePsDiagnosticsClient) ConnectionState() gocbcoreps.ConnState {
+	return c.state
+}
+
+func (c *fakePsDiagnosticsClient) KvV1() kv_v1.KvServiceClient {
+	return c.kv
+}
+
+func (c *fakePsDiagnosticsClient) QueryV1() query_v1.QueryServiceClient {
+	return c.query
+}
+
+func (c *fakePsDiagnosticsClient) AnalyticsV1() analytics_v1.AnalyticsServiceClient {
+	return c.analytics
+}
+
+func (c *fakePsDiagnosticsClient) SearchAdminV1() admin_search_v1.SearchAdminServiceClient {
+	return c.search
+}
+
+func (c *fakePsDiagnosticsClient) BucketV1() admin_bucket_v1.BucketAdminServiceClient {
+	return c.bucket
+}
+
+type fakePsPingKvClient struct {
+	kv_v1.KvServiceClient
+
+	req *kv_v1.ExistsRequest
+}
+
+func (c *fakePsPingKvClient) Exists(_ context.Context, in *kv_v1.ExistsRequest,
+	_ ...grpc.CallOption) (*kv_v1.ExistsResponse, error) {
+	c.req = in
+	return nil, status.Error(codes.NotFound, "document not found")
+}
+
+type fakePsPingQueryClient struct {
+	query_v1.QueryServiceClient
+}
+
+func (c *fakePsPingQueryClient) Query(ctx context.Context, _ *query_v1.QueryRequest,
+	_ ...grpc.CallOption) (query_v1.QueryService_QueryClient, error) {
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+type fakePsPingSearchClient struct {
+	admin_search_v1.SearchAdminServiceClient
+}
+
+func (c *fakePsPingSearchClient) ListIndexes(context.Context, *admin_search_v1.ListIndexesRequest,
+	...grpc.CallOption) (*admin_search_v1.ListIndexesResponse, error) {
+	return nil, status.Error(codes.PermissionDenied, "no access")
+}
+
+type fakePsPingBucketClient struct {
+	admin_bucket_v1.BucketAdminServiceClient
+}
+
+func (c *fakePsPingBucketClient) ListBuckets(context.Context, *admin_bucket_v1.ListBucketsRequest,
+	...grpc.CallOption) (*admin_bucket_v1.ListBucketsResponse, error) {
+	return nil, status.Error(codes.Unavailable, "connection refused")
+}
+
+func (suite *UnitTestSuite) TestPingPs() {
+	kv := &fakePsPingKvClient{}
+	provider := &diagnosticsProviderPs{
+		client: &fakePsDiagnosticsClient{
+			kv:    kv,
+			query: &fakePsPingQueryClient{},
+			analytics: &fakePsAnalyticsClient{
+				stream: &fakePsAnalyticsStream{
+					responses: []*analytics_v1.AnalyticsQueryResponse{{Rows: [][]byte{[]byte(`{"$1":true}`)}}},
+				},
+			},
+			search: &fakePsPingSearchClient{},
+			bucket: &fakePsPingBucketClient{},
+		},
+		host:       "10.0.0.1:18098",
+		bucketName: "default",
+	}
+
+	timeouts := TimeoutsConfig{
+		KVTimeout:         time.Second,
+		QueryTimeout:      10 * time.Millisecond,
+		AnalyticsTimeout:  time.Second,
+		SearchTimeout:     time.Second,
+		ManagementTimeout: time.Second,
+	}
+	res, err := ping(context.Background(), provider, &PingOptions{ReportID: "report"}, timeouts, &noopSpan{})
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal("report", res.ID)
+	suite.Require().Len(res.Services, 5)
+	for svc, reports := range res.Services {
+		suite.Require().Len(reports, 1, serviceTypeToString(svc))
+		suite.Assert().Equal("10.0.0.1:18098", reports[0].Remote)
+	}
+
+	suite.Assert().Equal(PingStateOk, res.Services[ServiceTypeKeyValue][0].State)
+	suite.Assert().Equal("default", res.Services[ServiceTypeKeyValue][0].Namespace)
+	suite.Assert().Equal("default", kv.req.BucketName)
+	suite.Assert().Equal(PingStateOk, res.Services[ServiceTypeAnalytics][0].State)
+	suite.Assert().Equal(PingStateOk, res.Services[ServiceTypeSearch][0].State)
+	suite.Assert().Equal(PingStateTimeout, res.Services[ServiceTypeQuery][0].State)
+	suite.Assert().Equal(PingStateError, res.Services[ServiceTypeManagement][0].State)
+	suite.Assert().Contains(res.Services[ServiceTypeManagement][0].Error, "connection refused")
+
+	_, err = json.Marshal(res)
+	suite.Require().NoError(err)
+
+	res, err = ping(context.Background(), provider, &PingOptions{
+		ServiceTypes: []ServiceType{ServiceTypeSearch},
+	}, timeouts, &noopSpan{})
+	suite.Require().NoError(err)
+	suite.Assert().Len(res.Services, 1)
+
+	_, err = ping(context.Background(), provider, &PingOptions{
+		ServiceTypes: []ServiceType{ServiceTypeViews},
+	}, timeouts, &noopSpan{})
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+
+	provider.bucketName = ""
+	_, err = ping(context.Background(), provider, &PingOptions{
+		ServiceTypes: []ServiceType{ServiceTypeKeyValue},
+	}, timeouts, &noopSpan{})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestDiagnosticsPs() {
+	client := &fakePsDiagnosticsClient{state: gocbcoreps.ConnStateDegraded}
+	provider := &diagnosticsProviderPs{
+		client:     client,
+		host:       "10.0.0.1:18098",
+		bucketName: "default",
+	}
+
+	info, err := provider.Diagnostics(gocbcore.DiagnosticsOptions{})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(gocbcore.ClusterStateDegraded, info.State)
+	suite.Require().Len(info.MemdConns, 1)
+	suite.Assert().Equal("10.0.0.1:18098", info.MemdConns[0].RemoteAddr)
+	suite.Assert().Equal("default", info.MemdConns[0].Scope)
+	suite.Assert().Equal(gocbcore.EndpointStateConnected, info.MemdConns[0].State)
+	suite.Assert().True(info.MemdConns[0].LastActivity.IsZero())
+
+	client.state = gocbcoreps.ConnStateOffline
+	info, err = provider.Diagnostics(gocbcore.DiagnosticsOptions{})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(gocbcore.ClusterStateOffline, info.State)
+	suite.Assert().Equal(gocbcore.EndpointStateDisconnected, info.MemdConns[0].State)
+}
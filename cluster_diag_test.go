@@ -183,3 +183,33 @@ func (suite *UnitTestSuite) TestDiagnosticsWithID() {
 		suite.T().Fatalf("Report ID should have been myreportid but was %s", report.ID)
 	}
 }
+
+func (suite *UnitTestSuite) TestDiagnosticsRedaction() {
+	report := &DiagnosticsResult{
+		ID: "report",
+		Services: map[string][]EndPointDiagnostics{
+			"kv": {{
+				Type:      ServiceTypeKeyValue,
+				ID:        "conn",
+				Local:     "10.112.191.101",
+				Remote:    "10.112.191.102",
+				State:     EndpointStateConnected,
+				Namespace: "bucket",
+			}},
+		},
+		State: ClusterStateOnline,
+	}
+
+	SetLogRedactionLevel(RedactFull)
+	defer SetLogRedactionLevel(RedactNone)
+
+	b, err := json.Marshal(report)
+	suite.Require().Nil(err)
+
+	var out jsonDiagnosticReport
+	suite.Require().Nil(json.Unmarshal(b, &out))
+	suite.Require().Len(out.Services["kv"], 1)
+	suite.Assert().Equal("<sd>10.112.191.101</sd>", out.Services["kv"][0].Local)
+	suite.Assert().Equal("<sd>10.112.191.102</sd>", out.Services["kv"][0].Remote)
+	suite.Assert().Equal("bucket", out.Services["kv"][0].Namespace)
+}
@@ -3,11 +3,6 @@ package gocb
 import (
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
-	"net/url"
-	"strings"
 	"time"
 )
 
@@ -16,50 +11,7 @@ import (
 // versions but that is not tested and is not supported.
 // UNCOMMITTED: This API may change in the future.
 type EventingFunctionManager struct {
-	mgmtProvider mgmtProvider
-	tracer       RequestTracer
-	meter        *meterWrapper
-}
-
-func (efm *EventingFunctionManager) doMgmtRequest(ctx context.Context, req mgmtRequest) (*mgmtResponse, error) {
-	resp, err := efm.mgmtProvider.executeMgmtRequest(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (efm *EventingFunctionManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtResponse) error {
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logDebugf("Failed to read eventing function response body: %s", err)
-		return nil
-	}
-
-	var baseErr error
-	strBody := string(b)
-	if strings.Contains(strBody, "ERR_APP_NOT_FOUND_TS") {
-		baseErr = ErrEventingFunctionNotFound
-	} else if strings.Contains(strBody, "ERR_APP_NOT_DEPLOYED") {
-		baseErr = ErrEventingFunctionNotDeployed
-	} else if strings.Contains(strBody, "ERR_HANDLER_COMPILATION") {
-		baseErr = ErrEventingFunctionCompilationFailure
-	} else if strings.Contains(strBody, "ERR_SRC_MB_SAME") {
-		baseErr = ErrEventingFunctionIdenticalKeyspace
-	} else if strings.Contains(strBody, "ERR_APP_NOT_BOOTSTRAPPED") {
-		baseErr = ErrEventingFunctionNotBootstrapped
-	} else if strings.Contains(strBody, "ERR_APP_NOT_UNDEPLOYED") {
-		baseErr = ErrEventingFunctionDeployed
-	} else if strings.Contains(strBody, "ERR_COLLECTION_MISSING") {
-		baseErr = ErrCollectionNotFound
-	} else if strings.Contains(strBody, "ERR_BUCKET_MISSING") {
-		baseErr = ErrBucketNotFound
-	} else {
-		baseErr = errors.New(string(b))
-	}
-
-	return makeGenericMgmtError(baseErr, req, resp, strBody)
+	getProvider func() (eventingManagementProvider, error)
 }
 
 type jsonEventingFunction struct {
@@ -644,67 +596,6 @@ func (ef *EventingFunction) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-type eventingRequestOptions struct {
-	Timeout       time.Duration
-	RetryStrategy RetryStrategy
-	ParentSpan    RequestSpan
-	Context       context.Context
-}
-
-func (efm *EventingFunctionManager) doRequest(path string, method string, opName string, function *EventingFunction,
-	target interface{}, opts eventingRequestOptions) error {
-	start := time.Now()
-	defer efm.meter.ValueRecord(meterValueServiceManagement, opName, start)
-
-	op := "manager_eventing_" + opName
-	span := createSpan(efm.tracer, opts.ParentSpan, op, "management")
-	span.SetAttribute("db.operation", method+" "+path)
-	defer span.End()
-
-	var b []byte
-	if function != nil {
-		var err error
-		b, err = json.Marshal(function)
-		if err != nil {
-			return err
-		}
-	}
-
-	req := mgmtRequest{
-		Service:       ServiceTypeEventing,
-		Method:        method,
-		Path:          path,
-		RetryStrategy: opts.RetryStrategy,
-		Timeout:       opts.Timeout,
-		parentSpanCtx: span.Context(),
-		Body:          b,
-	}
-	resp, err := efm.doMgmtRequest(opts.Context, req)
-	if err != nil {
-		return err
-	}
-	defer ensureBodyClosed(resp.Body)
-
-	if resp.StatusCode != 200 {
-		idxErr := efm.tryParseErrorMessage(&req, resp)
-		if idxErr != nil {
-			return idxErr
-		}
-
-		return makeMgmtBadStatusError("failed eventing "+opName, &req, resp)
-	}
-
-	if target != nil {
-		jsonDec := json.NewDecoder(resp.Body)
-		err = jsonDec.Decode(target)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // UpsertEventingFunctionOptions are the options available when using the UpsertFunction operation.
 type UpsertEventingFunctionOptions struct {
 	Timeout       time.Duration
@@ -723,13 +614,12 @@ func (efm *EventingFunctionManager) UpsertFunction(function EventingFunction, op
 		opts = &UpsertEventingFunctionOptions{}
 	}
 
-	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s", url.PathEscape(function.Name)), "POST",
-		"upsert_function", &function, nil, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
+	if err != nil {
+		return err
+	}
+
+	return provider.UpsertFunction(function, opts)
 }
 
 // DropEventingFunctionOptions are the options available when using the DropFunction operation.
@@ -750,13 +640,12 @@ func (efm *EventingFunctionManager) DropFunction(name string, opts *DropEventing
 		opts = &DropEventingFunctionOptions{}
 	}
 
-	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s", url.PathEscape(name)), "DELETE",
-		"drop_function", nil, nil, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
+	if err != nil {
+		return err
+	}
+
+	return provider.DropFunction(name, opts)
 }
 
 // DeployEventingFunctionOptions are the options available when using the DeployFunction operation.
@@ -777,13 +666,12 @@ func (efm *EventingFunctionManager) DeployFunction(name string, opts *DeployEven
 		opts = &DeployEventingFunctionOptions{}
 	}
 
-	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s/deploy", url.PathEscape(name)), "POST",
-		"deploy_function", nil, nil, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
+	if err != nil {
+		return err
+	}
+
+	return provider.DeployFunction(name, opts)
 }
 
 // UndeployEventingFunctionOptions are the options available when using the UndeployFunction operation.
@@ -804,13 +692,12 @@ func (efm *EventingFunctionManager) UndeployFunction(name string, opts *Undeploy
 		opts = &UndeployEventingFunctionOptions{}
 	}
 
-	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s/undeploy", url.PathEscape(name)), "POST",
-		"undeploy_function", nil, nil, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
+	if err != nil {
+		return err
+	}
+
+	return provider.UndeployFunction(name, opts)
 }
 
 // GetAllEventingFunctionsOptions are the options available when using the GetAllFunctions operation.
@@ -831,19 +718,12 @@ func (efm *EventingFunctionManager) GetAllFunctions(opts *GetAllEventingFunction
 		opts = &GetAllEventingFunctionsOptions{}
 	}
 
-	var functions []EventingFunction
-	err := efm.doRequest("/api/v1/functions", "GET",
-		"get_all_functions", nil, &functions, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
 	if err != nil {
 		return nil, err
 	}
 
-	return functions, nil
+	return provider.GetAllFunctions(opts)
 }
 
 // GetEventingFunctionOptions are the options available when using the GetFunction operation.
@@ -864,19 +744,12 @@ func (efm *EventingFunctionManager) GetFunction(name string, opts *GetEventingFu
 		opts = &GetEventingFunctionOptions{}
 	}
 
-	var function *EventingFunction
-	err := efm.doRequest(fmt.Sprintf("/api/v1/functions/%s", url.PathEscape(name)), "GET",
-		"get_function", nil, &function, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
 	if err != nil {
 		return nil, err
 	}
 
-	return function, nil
+	return provider.GetFunction(name, opts)
 }
 
 // PauseEventingFunctionOptions are the options available when using the PauseFunction operation.
@@ -897,13 +770,12 @@ func (efm *EventingFunctionManager) PauseFunction(name string, opts *PauseEventi
 		opts = &PauseEventingFunctionOptions{}
 	}
 
-	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s/pause", url.PathEscape(name)), "POST",
-		"pause_function", nil, nil, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
+	if err != nil {
+		return err
+	}
+
+	return provider.PauseFunction(name, opts)
 }
 
 // ResumeEventingFunctionOptions are the options available when using the ResumeFunction operation.
@@ -924,13 +796,12 @@ func (efm *EventingFunctionManager) ResumeFunction(name string, opts *ResumeEven
 		opts = &ResumeEventingFunctionOptions{}
 	}
 
-	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s/resume", url.PathEscape(name)), "POST",
-		"resume_function", nil, nil, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
+	if err != nil {
+		return err
+	}
+
+	return provider.ResumeFunction(name, opts)
 }
 
 // EventingFunctionsStatusOptions are the options available when using the FunctionsStatus operation.
@@ -951,17 +822,10 @@ func (efm *EventingFunctionManager) FunctionsStatus(opts *EventingFunctionsStatu
 		opts = &EventingFunctionsStatusOptions{}
 	}
 
-	var functions *EventingStatus
-	err := efm.doRequest("/api/v1/status", "GET",
-		"functions_status", nil, &functions, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+	provider, err := efm.getProvider()
 	if err != nil {
 		return nil, err
 	}
 
-	return functions, nil
+	return provider.FunctionsStatus(opts)
 }
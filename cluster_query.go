@@ -1,6 +1,7 @@
 package gocb
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"time"
@@ -166,6 +167,11 @@ func (qrr *QueryResultRaw) Close() error {
 	return nil
 }
 
+// cancel cancels the underlying request if the reader supports it, see rowReaderCanceler.
+func (qrr *QueryResultRaw) cancel() {
+	cancelRowReader(qrr.reader)
+}
+
 // MetaData returns any meta-data that was available from this query as bytes.
 func (qrr *QueryResultRaw) MetaData() ([]byte, error) {
 	return qrr.reader.MetaData()
@@ -205,6 +211,22 @@ func (r *QueryResult) Raw() *QueryResultRaw {
 	return vr
 }
 
+// rows returns a QueryResultRaw sharing the reader of this result. Unlike Raw the result remains valid, so that the
+// meta-data can be accessed once the rows have been read.
+func (r *QueryResult) rows() (*QueryResultRaw, error) {
+	if r.reader == nil {
+		return nil, r.Err()
+	}
+
+	raw := &QueryResultRaw{
+		reader:        r.reader,
+		transactionID: r.transactionID,
+		nextRowBytes:  r.nextRowBytes,
+	}
+	r.nextRowBytes = nil
+	return raw, nil
+}
+
 func (r *QueryResult) peekNext() []byte {
 	return r.nextRowBytes
 }
@@ -356,6 +378,25 @@ func (c *Cluster) Query(statement string, opts *QueryOptions) (*QueryResult, err
 		opts = &QueryOptions{}
 	}
 
+	info := &OperationInfo{
+		Service:   ServiceTypeQuery,
+		Operation: "query",
+		Statement: statement,
+		Options:   copyOptions(opts),
+	}
+	return interceptOperation(opts.Context, c.interceptors.all(), info,
+		func(ctx context.Context, info *OperationInfo) (*QueryResult, error) {
+			opts, err := interceptedOptions[QueryOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return c.query(statement, opts)
+		})
+}
+
+func (c *Cluster) query(statement string, opts *QueryOptions) (*QueryResult, error) {
 	if opts.AsTransaction != nil {
 		return c.Transactions().singleQuery(statement, nil, *opts)
 	}
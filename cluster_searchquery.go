@@ -1,6 +1,7 @@
 package gocb
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
@@ -13,6 +14,25 @@ func (c *Cluster) SearchQuery(indexName string, query cbsearch.Query, opts *Sear
 		opts = &SearchOptions{}
 	}
 
+	info := &OperationInfo{
+		Service:   ServiceTypeSearch,
+		Operation: "search",
+		Statement: indexName,
+		Options:   copyOptions(opts),
+	}
+	return interceptOperation(opts.Context, c.interceptors.all(), info,
+		func(ctx context.Context, info *OperationInfo) (*SearchResult, error) {
+			opts, err := interceptedOptions[SearchOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return c.searchQuery(indexName, query, opts)
+		})
+}
+
+func (c *Cluster) searchQuery(indexName string, query cbsearch.Query, opts *SearchOptions) (*SearchResult, error) {
 	provider, err := c.getSearchProvider()
 	if err != nil {
 		return nil, SearchError{
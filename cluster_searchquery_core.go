@@ -55,9 +55,12 @@ type searchProviderCore struct {
 	meter                *meterWrapper
 }
 
-func (search *searchProviderCore) SearchQuery(indexName string, query cbsearch.Query, opts *SearchOptions) (*SearchResult, error) {
+func (search *searchProviderCore) SearchQuery(indexName string, query cbsearch.Query,
+	opts *SearchOptions) (resOut *SearchResult, errOut error) {
 	start := time.Now()
-	defer search.meter.ValueRecord(meterValueServiceSearch, "search", start)
+	defer func() {
+		search.meter.ValueRecordOutcome(meterOperation{service: meterValueServiceSearch, operation: "search"}, errOut, start)
+	}()
 
 	span := createSpan(search.tracer, opts.ParentSpan, "search", "search")
 	span.SetAttribute("db.operation", indexName)
@@ -73,6 +76,7 @@ func (search *searchProviderCore) SearchQuery(indexName string, query cbsearch.Q
 	if opts.RetryStrategy != nil {
 		retryStrategy = newCoreRetryStrategyWrapper(opts.RetryStrategy)
 	}
+	retryStrategy = retryStrategy.forService(ServiceTypeSearch, search.meter)
 
 	searchOpts, err := opts.toMap(indexName)
 	if err != nil {
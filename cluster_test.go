@@ -66,7 +66,7 @@ func (suite *IntegrationTestSuite) TestClusterWaitUntilReadyFastFailAuth() {
 	defer c.Close(nil)
 
 	err = c.WaitUntilReady(7*time.Second, &WaitUntilReadyOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if !errors.Is(err, ErrAuthenticationFailure) {
 		suite.T().Fatalf("Expected authentication error but was: %v", err)
@@ -89,7 +89,7 @@ func (suite *IntegrationTestSuite) TestClusterWaitUntilReadyFastFailConnStr() {
 	defer c.Close(nil)
 
 	err = c.WaitUntilReady(7*time.Second, &WaitUntilReadyOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if !errors.Is(err, ErrTimeout) {
 		suite.T().Fatalf("Expected timeout error but was: %v", err)
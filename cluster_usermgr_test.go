@@ -551,11 +551,7 @@ func (suite *UnitTestSuite) TestUserManagerGetUserDoesntExist() {
 		}).
 		Return(resp, nil)
 
-	usrMgr := &UserManager{
-		provider: mockProvider,
-		tracer:   &NoopTracer{},
-		meter:    &meterWrapper{meter: &NoopMeter{}},
-	}
+	usrMgr := newMockUserManager(mockProvider)
 	_, err := usrMgr.GetUser(username, &GetUserOptions{
 		Timeout: 1 * time.Second,
 	})
@@ -585,11 +581,7 @@ func (suite *UnitTestSuite) TestUserManagerDropUserDoesntExist() {
 		}).
 		Return(resp, nil)
 
-	usrMgr := &UserManager{
-		provider: mockProvider,
-		tracer:   &NoopTracer{},
-		meter:    &meterWrapper{meter: &NoopMeter{}},
-	}
+	usrMgr := newMockUserManager(mockProvider)
 	err := usrMgr.DropUser(username, &DropUserOptions{
 		Timeout: 1 * time.Second,
 	})
@@ -619,11 +611,7 @@ func (suite *UnitTestSuite) TestUserManagerGetGroupDoesntExist() {
 		}).
 		Return(resp, nil)
 
-	usrMgr := &UserManager{
-		provider: mockProvider,
-		tracer:   &NoopTracer{},
-		meter:    &meterWrapper{meter: &NoopMeter{}},
-	}
+	usrMgr := newMockUserManager(mockProvider)
 	_, err := usrMgr.GetGroup(name, &GetGroupOptions{
 		Timeout: 1 * time.Second,
 	})
@@ -653,11 +641,7 @@ func (suite *UnitTestSuite) TestUserManagerDropGroupDoesntExist() {
 		}).
 		Return(resp, nil)
 
-	usrMgr := &UserManager{
-		provider: mockProvider,
-		tracer:   &NoopTracer{},
-		meter:    &meterWrapper{meter: &NoopMeter{}},
-	}
+	usrMgr := newMockUserManager(mockProvider)
 	err := usrMgr.DropGroup(name, &DropGroupOptions{
 		Timeout: 1 * time.Second,
 	})
@@ -665,3 +649,31 @@ func (suite *UnitTestSuite) TestUserManagerDropGroupDoesntExist() {
 		suite.T().Fatalf("Expected user not found error, %s", err)
 	}
 }
+
+func (suite *UnitTestSuite) TestUserManagerPs() {
+	usrMgr := &UserManager{
+		getProvider: func() (userManagementProvider, error) {
+			return &userManagementProviderPs{}, nil
+		},
+	}
+
+	_, err := usrMgr.GetAllUsers(nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+	suite.Assert().Contains(err.Error(), "GetAllUsers")
+
+	err = usrMgr.ChangePassword("password", nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+	suite.Assert().Contains(err.Error(), "ChangePassword")
+}
+
+func newMockUserManager(provider mgmtProvider) *UserManager {
+	return &UserManager{
+		getProvider: func() (userManagementProvider, error) {
+			return &userManagementProviderCore{
+				mgmtProvider: provider,
+				tracer:       &NoopTracer{},
+				meter:        &meterWrapper{meter: &NoopMeter{}},
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,664 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10/memd"
+	"github.com/couchbaselabs/gocbconnstr/v2"
+	"github.com/lissteron/gocb"
+)
+
+const (
+	certExpiryWarning       = 30 * 24 * time.Hour
+	defaultProtostellarPort = 18098
+)
+
+var serviceNames = map[gocb.ServiceType]string{
+	gocb.ServiceTypeKeyValue:   "kv",
+	gocb.ServiceTypeManagement: "mgmt",
+	gocb.ServiceTypeViews:      "views",
+	gocb.ServiceTypeQuery:      "query",
+	gocb.ServiceTypeSearch:     "search",
+	gocb.ServiceTypeAnalytics:  "analytics",
+	gocb.ServiceTypeEventing:   "eventing",
+}
+
+// clusterMapPorts maps each service to the names of its plain and TLS ports in the cluster map.
+var clusterMapPorts = []struct {
+	service string
+	plain   string
+	tls     string
+}{
+	{service: "kv", plain: "kv", tls: "kvSSL"},
+	{service: "mgmt", plain: "mgmt", tls: "mgmtSSL"},
+	{service: "views", plain: "capi", tls: "capiSSL"},
+	{service: "query", plain: "n1ql", tls: "n1qlSSL"},
+	{service: "search", plain: "fts", tls: "ftsSSL"},
+	{service: "analytics", plain: "cbas", tls: "cbasSSL"},
+	{service: "eventing", plain: "eventingAdminPort", tls: "eventingSSL"},
+}
+
+// clusterMap is the subset of the /pools/default/nodeServices response which the doctor uses.
+type clusterMap struct {
+	Nodes []clusterMapNode `json:"nodesExt"`
+}
+
+type clusterMapNode struct {
+	Hostname           string                             `json:"hostname"`
+	Services           map[string]int                     `json:"services"`
+	AlternateAddresses map[string]clusterMapAlternateAddr `json:"alternateAddresses"`
+}
+
+type clusterMapAlternateAddr struct {
+	Hostname string         `json:"hostname"`
+	Ports    map[string]int `json:"ports"`
+}
+
+type serviceEndpoint struct {
+	service string
+	addr    gocbconnstr.Address
+}
+
+type doctorOptions struct {
+	ConnStr  string
+	Username string
+	Password string
+	Bucket   string
+	Timeout  time.Duration
+	Security gocb.SecurityConfig
+}
+
+// doctor runs each of the connectivity checks in turn, from name resolution up to pinging each service. The network
+// functions are fields so that the checks can be exercised without a cluster.
+type doctor struct {
+	opts   doctorOptions
+	report *report
+
+	lookupSRV  func(service, proto, name string) (string, []*net.SRV, error)
+	lookupHost func(host string) ([]string, error)
+	dial       func(network, address string, timeout time.Duration) (net.Conn, error)
+	httpDo     func(req *http.Request) (*http.Response, error)
+	connect    func(connStr string, opts gocb.ClusterOptions) (*gocb.Cluster, error)
+	now        func() time.Time
+
+	resolved map[string]struct{}
+	checked  map[string]struct{}
+}
+
+func newDoctor(opts doctorOptions) *doctor {
+	d := &doctor{
+		opts:       opts,
+		report:     &report{Target: opts.ConnStr},
+		lookupSRV:  net.LookupSRV,
+		lookupHost: net.LookupHost,
+		dial:       net.DialTimeout,
+		connect:    gocb.Connect,
+		now:        time.Now,
+		resolved:   make(map[string]struct{}),
+		checked:    make(map[string]struct{}),
+	}
+
+	client := &http.Client{
+		Timeout: opts.Timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: d.tlsConfig(""),
+		},
+	}
+	d.httpDo = client.Do
+
+	return d
+}
+
+func (d *doctor) run() *report {
+	spec, err := gocbconnstr.Parse(d.opts.ConnStr)
+	if err != nil {
+		d.report.fail("Connection string", err, "check the connection string, e.g. couchbases://cb.example.com")
+		return d.report
+	}
+
+	protostellar := spec.Scheme == "couchbase2"
+	var kvHosts, httpHosts []gocbconnstr.Address
+	useTLS := spec.Scheme == "couchbases" || protostellar
+	d.report.pass("Connection string", fmt.Sprintf("scheme %s, %d seed hosts, TLS %s", schemeName(spec.Scheme),
+		len(spec.Addresses), onOff(useTLS)))
+	if !useTLS {
+		d.report.warn("Transport security", "connection is not encrypted",
+			"use couchbases:// when connecting over untrusted networks, Capella requires it")
+	}
+
+	if protostellar {
+		for _, addr := range spec.Addresses {
+			if addr.Port <= 0 {
+				addr.Port = defaultProtostellarPort
+			}
+			kvHosts = append(kvHosts, addr)
+		}
+	} else if kvHosts = d.checkSRV(spec); kvHosts != nil {
+		mgmtPort := gocbconnstr.DefaultHttpPort
+		if useTLS {
+			mgmtPort = gocbconnstr.DefaultSslHttpPort
+		}
+		for _, addr := range kvHosts {
+			httpHosts = append(httpHosts, gocbconnstr.Address{Host: addr.Host, Port: mgmtPort})
+		}
+	} else {
+		resolved, err := gocbconnstr.Resolve(spec)
+		if err != nil {
+			d.report.fail("Seed hosts", err, "check the connection string, e.g. couchbases://cb.example.com")
+			return d.report
+		}
+		kvHosts = resolved.MemdHosts
+		httpHosts = resolved.HttpHosts
+	}
+
+	d.checkDNS(kvHosts)
+
+	var kvReachable, httpReachable []gocbconnstr.Address
+	for _, addr := range kvHosts {
+		if d.checkEndpoint("kv", addr, useTLS) {
+			kvReachable = append(kvReachable, addr)
+		}
+	}
+	for _, addr := range httpHosts {
+		if d.checkEndpoint("mgmt", addr, useTLS) {
+			httpReachable = append(httpReachable, addr)
+		}
+	}
+
+	if !protostellar {
+		if endpoints := d.checkClusterMap(httpReachable, useTLS); len(endpoints) > 0 {
+			hosts := make([]gocbconnstr.Address, len(endpoints))
+			for i, endpoint := range endpoints {
+				hosts[i] = endpoint.addr
+			}
+			d.checkDNS(hosts)
+
+			for _, endpoint := range endpoints {
+				if d.checkEndpoint(endpoint.service, endpoint.addr, useTLS) && endpoint.service == "kv" {
+					kvReachable = append(kvReachable, endpoint.addr)
+				}
+			}
+		}
+
+		d.checkSASL(kvReachable, useTLS)
+	}
+
+	d.checkCluster(spec)
+
+	return d.report
+}
+
+// checkSRV looks up the SRV record for the connection string, returning the hosts it points to if there is one.
+func (d *doctor) checkSRV(spec gocbconnstr.ConnSpec) []gocbconnstr.Address {
+	name := spec.SrvRecordName()
+	if name == "" {
+		d.report.info("DNS SRV", "not used, the connection string does not contain a single host without a port")
+		return nil
+	}
+
+	parts := strings.SplitN(name, ".", 3)
+	if len(parts) != 3 {
+		return nil
+	}
+
+	_, records, err := d.lookupSRV(strings.TrimPrefix(parts[0], "_"), strings.TrimPrefix(parts[1], "_"), parts[2])
+	if err != nil || len(records) == 0 {
+		d.report.info("DNS SRV", fmt.Sprintf("no record found for %s, connecting to the host directly", name))
+		return nil
+	}
+
+	srvHosts := make([]gocbconnstr.Address, 0, len(records))
+	targets := make([]string, 0, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		srvHosts = append(srvHosts, gocbconnstr.Address{Host: host, Port: int(record.Port)})
+		targets = append(targets, net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+	}
+	d.report.pass("DNS SRV", fmt.Sprintf("%s resolved to %s", name, strings.Join(targets, ", ")))
+
+	return srvHosts
+}
+
+func (d *doctor) checkDNS(hosts []gocbconnstr.Address) {
+	for _, addr := range hosts {
+		if _, ok := d.resolved[addr.Host]; ok {
+			continue
+		}
+		d.resolved[addr.Host] = struct{}{}
+
+		if net.ParseIP(addr.Host) != nil {
+			continue
+		}
+
+		name := "DNS " + addr.Host
+		ips, err := d.lookupHost(addr.Host)
+		if err != nil {
+			d.report.fail(name, err, "")
+			continue
+		}
+		d.report.pass(name, "resolved to "+strings.Join(ips, ", "))
+	}
+}
+
+// checkEndpoint checks that a service port can be connected to, returning whether it could. Each port is only checked
+// once, later checks return false.
+func (d *doctor) checkEndpoint(service string, addr gocbconnstr.Address, useTLS bool) bool {
+	address := net.JoinHostPort(addr.Host, strconv.Itoa(addr.Port))
+	if _, ok := d.checked[address]; ok {
+		return false
+	}
+	d.checked[address] = struct{}{}
+	name := fmt.Sprintf("TCP %s %s", service, address)
+
+	start := d.now()
+	conn, err := d.dial("tcp", address, d.opts.Timeout)
+	if err != nil {
+		d.report.fail(name, err, "")
+		return false
+	}
+	d.report.pass(name, fmt.Sprintf("connected in %s", d.now().Sub(start).Round(time.Millisecond)))
+
+	if !useTLS {
+		_ = conn.Close()
+		return true
+	}
+
+	d.checkTLS(service, addr.Host, address, conn)
+	return true
+}
+
+func (d *doctor) tlsConfig(host string) *tls.Config {
+	return &tls.Config{
+		ServerName:         host,
+		RootCAs:            d.opts.Security.TLSRootCAs,
+		InsecureSkipVerify: d.opts.Security.TLSSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+}
+
+func (d *doctor) checkTLS(service, host, address string, conn net.Conn) {
+	name := fmt.Sprintf("TLS %s %s", service, address)
+
+	tlsConn := tls.Client(conn, d.tlsConfig(host))
+	defer tlsConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.opts.Timeout)
+	defer cancel()
+
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		d.report.fail(name, err, "")
+		return
+	}
+
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		d.report.fail(name, errors.New("server presented no certificates"), "")
+		return
+	}
+
+	leaf := certs[0]
+	detail := fmt.Sprintf("%s, chain %s, expires %s", tls.VersionName(tlsConn.ConnectionState().Version),
+		describeChain(certs), leaf.NotAfter.Format("2006-01-02"))
+
+	switch {
+	case d.opts.Security.TLSSkipVerify:
+		d.report.warn(name, detail+", certificate not verified",
+			"certificate verification is disabled, pass the cluster CA certificate with -cacert instead")
+	case leaf.NotAfter.Sub(d.now()) < certExpiryWarning:
+		d.report.warn(name, detail, "the certificate expires soon, renew the certificate installed on the cluster")
+	default:
+		d.report.pass(name, detail)
+	}
+}
+
+// checkClusterMap fetches the cluster map from the first management port which returns it, returning the port of every
+// service on every node. The seed hosts only cover bootstrap, the SDK goes on to connect to each of these.
+func (d *doctor) checkClusterMap(mgmtHosts []gocbconnstr.Address, useTLS bool) []serviceEndpoint {
+	if len(mgmtHosts) == 0 {
+		d.report.info("Cluster map", "skipped, no management port could be reached")
+		return nil
+	}
+
+	var err error
+	for _, addr := range mgmtHosts {
+		var cfg *clusterMap
+		cfg, err = d.fetchClusterMap(addr, useTLS)
+		if err != nil {
+			continue
+		}
+
+		endpoints := clusterMapEndpoints(cfg, addr.Host, useTLS)
+		nodes := make(map[string]struct{})
+		for _, endpoint := range endpoints {
+			nodes[endpoint.addr.Host] = struct{}{}
+		}
+		d.report.pass("Cluster map", fmt.Sprintf("fetched from %s, %d nodes, %d service ports", addr.Host,
+			len(nodes), len(endpoints)))
+
+		return endpoints
+	}
+
+	hint := ""
+	if errors.Is(err, gocb.ErrAuthenticationFailure) {
+		hint = "check the username and password"
+	}
+	d.report.fail("Cluster map", err, hint)
+	return nil
+}
+
+func (d *doctor) fetchClusterMap(addr gocbconnstr.Address, useTLS bool) (*clusterMap, error) {
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.opts.Timeout)
+	defer cancel()
+
+	uri := fmt.Sprintf("%s://%s/pools/default/nodeServices", scheme, net.JoinHostPort(addr.Host, strconv.Itoa(addr.Port)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(d.opts.Username, d.opts.Password)
+
+	resp, err := d.httpDo(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("%s: %w", resp.Status, gocb.ErrAuthenticationFailure)
+	default:
+		return nil, fmt.Errorf("unexpected response %s", resp.Status)
+	}
+
+	var cfg clusterMap
+	if err := json.Unmarshal(body, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid cluster map: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// clusterMapEndpoints lists the service ports of every node in the cluster map. Like the SDK, the external alternate
+// addresses are used if the host the map was fetched from is one of them.
+func clusterMapEndpoints(cfg *clusterMap, fetchedFrom string, useTLS bool) []serviceEndpoint {
+	external := false
+	for _, node := range cfg.Nodes {
+		if alt, ok := node.AlternateAddresses["external"]; ok && alt.Hostname == fetchedFrom {
+			external = true
+			break
+		}
+	}
+
+	var endpoints []serviceEndpoint
+	for _, node := range cfg.Nodes {
+		host := node.Hostname
+		ports := node.Services
+		if external {
+			alt, ok := node.AlternateAddresses["external"]
+			if !ok {
+				continue
+			}
+			host = alt.Hostname
+			if alt.Ports != nil {
+				ports = alt.Ports
+			}
+		}
+		if host == "" {
+			// Single node clusters leave the host name out, it is the host that was asked.
+			host = fetchedFrom
+		}
+
+		for _, service := range clusterMapPorts {
+			key := service.plain
+			if useTLS {
+				key = service.tls
+			}
+			port, ok := ports[key]
+			if !ok {
+				continue
+			}
+
+			endpoints = append(endpoints, serviceEndpoint{
+				service: service.service,
+				addr:    gocbconnstr.Address{Host: host, Port: port},
+			})
+		}
+	}
+
+	return endpoints
+}
+
+// checkSASL authenticates directly against the first reachable key-value port, so that bad credentials are reported
+// on their own rather than as a connect or bucket failure.
+func (d *doctor) checkSASL(kvHosts []gocbconnstr.Address, useTLS bool) {
+	if d.opts.Username == "" {
+		d.report.info("SASL authentication", "skipped, pass -u and -p to check credentials")
+		return
+	}
+	if len(kvHosts) == 0 {
+		d.report.info("SASL authentication", "skipped, no key-value port could be reached")
+		return
+	}
+
+	addr := kvHosts[0]
+	address := net.JoinHostPort(addr.Host, strconv.Itoa(addr.Port))
+	name := "SASL authentication " + address
+
+	conn, err := d.dial("tcp", address, d.opts.Timeout)
+	if err != nil {
+		d.report.fail(name, err, "")
+		return
+	}
+	deadline := d.now().Add(d.opts.Timeout)
+	_ = conn.SetDeadline(deadline)
+
+	if useTLS {
+		tlsConn := tls.Client(conn, d.tlsConfig(addr.Host))
+		if err := tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+			d.report.fail(name, err, "")
+			return
+		}
+		conn = tlsConn
+	}
+	defer conn.Close()
+
+	client := &saslClient{
+		address: address,
+		conn:    memd.NewConn(conn),
+	}
+	mech, err := client.authenticate(d.opts.Username, d.opts.Password, useTLS, deadline)
+	if err != nil {
+		hint := ""
+		if errors.Is(err, gocb.ErrAuthenticationFailure) {
+			hint = "check the username and password, and that the user exists on the cluster"
+		}
+		d.report.fail(name, err, hint)
+		return
+	}
+	d.report.pass(name, fmt.Sprintf("authenticated as %s using %s", d.opts.Username, mech))
+}
+
+func (d *doctor) checkCluster(spec gocbconnstr.ConnSpec) {
+	cluster, err := d.connect(d.opts.ConnStr, gocb.ClusterOptions{
+		Authenticator: gocb.PasswordAuthenticator{
+			Username: d.opts.Username,
+			Password: d.opts.Password,
+		},
+		SecurityConfig: d.opts.Security,
+		TimeoutsConfig: gocb.TimeoutsConfig{
+			ConnectTimeout: d.opts.Timeout,
+		},
+	})
+	if err != nil {
+		d.report.fail("Connect", err, "")
+		return
+	}
+	defer func() {
+		_ = cluster.Close(nil)
+	}()
+	d.report.pass("Connect", "")
+
+	bucketName := d.opts.Bucket
+	if bucketName == "" {
+		bucketName = spec.Bucket
+	}
+
+	var bucket *gocb.Bucket
+	if bucketName != "" {
+		bucket = cluster.Bucket(bucketName)
+		start := d.now()
+		// Waiting for the bucket is what exercises SASL authentication, and then bucket access.
+		err = bucket.WaitUntilReady(d.opts.Timeout, nil)
+		if err != nil {
+			d.report.fail("Authentication and bucket access", err, "")
+		} else {
+			d.report.pass("Authentication and bucket access",
+				fmt.Sprintf("bucket %s ready in %s", bucketName, d.now().Sub(start).Round(time.Millisecond)))
+		}
+	} else {
+		d.report.info("Authentication and bucket access", "skipped, pass -bucket to check key-value access")
+	}
+
+	start := d.now()
+	err = cluster.WaitUntilReady(d.opts.Timeout, nil)
+	if err != nil {
+		d.report.fail("WaitUntilReady", err, "")
+	} else {
+		d.report.pass("WaitUntilReady", fmt.Sprintf("cluster ready in %s", d.now().Sub(start).Round(time.Millisecond)))
+	}
+
+	var result *gocb.PingResult
+	if bucket != nil {
+		result, err = bucket.Ping(&gocb.PingOptions{Timeout: d.opts.Timeout})
+	} else {
+		result, err = cluster.Ping(&gocb.PingOptions{Timeout: d.opts.Timeout})
+	}
+	if err != nil {
+		d.report.fail("Ping", err, "")
+	} else {
+		d.checkPing(result)
+	}
+
+	diag, err := cluster.Diagnostics(nil)
+	if err != nil {
+		// The diagnostics report is only available once a bucket has been opened.
+		return
+	}
+	d.checkDiagnostics(diag)
+}
+
+func (d *doctor) checkPing(result *gocb.PingResult) {
+	services := make([]gocb.ServiceType, 0, len(result.Services))
+	for service := range result.Services {
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i] < services[j]
+	})
+
+	for _, service := range services {
+		for _, endpoint := range result.Services[service] {
+			name := fmt.Sprintf("Ping %s %s", serviceName(service), endpoint.Remote)
+			if endpoint.State == gocb.PingStateOk {
+				d.report.pass(name, fmt.Sprintf("latency %s", endpoint.Latency.Round(time.Microsecond)))
+				continue
+			}
+
+			detail := endpoint.Error
+			if detail == "" {
+				detail = pingStateName(endpoint.State)
+			}
+
+			hint := "the node is reachable for bootstrap but not on this service's port, check firewalls allow " +
+				"all Couchbase service ports"
+			if endpoint.State == gocb.PingStateTimeout {
+				hint = "the service did not respond in time, check firewalls and the load on the node"
+			}
+			d.report.add(name, statusFail, detail, hint)
+		}
+	}
+}
+
+func (d *doctor) checkDiagnostics(diag *gocb.DiagnosticsResult) {
+	var connected, total int
+	for _, endpoints := range diag.Services {
+		for _, endpoint := range endpoints {
+			total++
+			if endpoint.State == gocb.EndpointStateConnected {
+				connected++
+			}
+		}
+	}
+
+	detail := fmt.Sprintf("%d of %d key-value connections connected", connected, total)
+	if connected < total {
+		d.report.warn("Diagnostics", detail, "some nodes could not be connected to, check the TCP checks above "+
+			"and that every node's advertised address is reachable")
+		return
+	}
+	d.report.pass("Diagnostics", detail)
+}
+
+func describeChain(certs []*x509.Certificate) string {
+	names := make([]string, 0, len(certs))
+	for _, cert := range certs {
+		names = append(names, cert.Subject.CommonName)
+	}
+	return strings.Join(names, " <- ")
+}
+
+func serviceName(service gocb.ServiceType) string {
+	if name, ok := serviceNames[service]; ok {
+		return name
+	}
+	return fmt.Sprintf("service(%d)", service)
+}
+
+func pingStateName(state gocb.PingState) string {
+	switch state {
+	case gocb.PingStateOk:
+		return "ok"
+	case gocb.PingStateTimeout:
+		return "timeout"
+	default:
+		return "error"
+	}
+}
+
+func schemeName(scheme string) string {
+	if scheme == "" {
+		return "couchbase (default)"
+	}
+	return scheme
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
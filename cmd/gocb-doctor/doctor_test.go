@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/couchbase/gocbcore/v10/memd"
+	"github.com/couchbaselabs/gocbconnstr/v2"
+	"github.com/lissteron/gocb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDoctor(connStr string) *doctor {
+	d := newDoctor(doctorOptions{
+		ConnStr:  connStr,
+		Username: "Administrator",
+		Password: "password",
+		Timeout:  time.Second,
+	})
+	d.lookupSRV = func(service, proto, name string) (string, []*net.SRV, error) {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	d.lookupHost = func(host string) ([]string, error) {
+		return []string{"10.0.0.1"}, nil
+	}
+	d.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+	}
+	d.httpDo = func(req *http.Request) (*http.Response, error) {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+	d.connect = func(connStr string, opts gocb.ClusterOptions) (*gocb.Cluster, error) {
+		return nil, gocb.ErrAuthenticationFailure
+	}
+
+	return d
+}
+
+func resultNames(rep *report) []string {
+	var names []string
+	for _, result := range rep.Results {
+		names = append(names, fmt.Sprintf("[%s] %s", result.Status, result.Name))
+	}
+
+	return names
+}
+
+func TestDoctorSRV(t *testing.T) {
+	d := newTestDoctor("couchbases://cb.example.com")
+	d.lookupSRV = func(service, proto, name string) (string, []*net.SRV, error) {
+		assert.Equal(t, "couchbases", service)
+		assert.Equal(t, "tcp", proto)
+		assert.Equal(t, "cb.example.com", name)
+		return "", []*net.SRV{
+			{Target: "node1.cb.example.com.", Port: 11207},
+			{Target: "node2.cb.example.com.", Port: 11207},
+		}, nil
+	}
+	d.lookupHost = func(host string) ([]string, error) {
+		if host == "node2.cb.example.com" {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return []string{"10.0.0.1"}, nil
+	}
+
+	rep := d.run()
+	assert.Equal(t, []string{
+		"[PASS] Connection string",
+		"[PASS] DNS SRV",
+		"[PASS] DNS node1.cb.example.com",
+		"[FAIL] DNS node2.cb.example.com",
+		"[FAIL] TCP kv node1.cb.example.com:11207",
+		"[FAIL] TCP kv node2.cb.example.com:11207",
+		"[FAIL] TCP mgmt node1.cb.example.com:18091",
+		"[FAIL] TCP mgmt node2.cb.example.com:18091",
+		"[INFO] Cluster map",
+		"[INFO] SASL authentication",
+		"[FAIL] Connect",
+	}, resultNames(rep))
+	assert.True(t, rep.failed())
+	assert.Equal(t, "_couchbases._tcp.cb.example.com resolved to node1.cb.example.com:11207, node2.cb.example.com:11207",
+		rep.Results[1].Detail)
+	assert.Contains(t, rep.Results[3].Hint, "could not be resolved")
+	assert.Contains(t, rep.Results[4].Hint, "nothing is listening")
+	assert.Contains(t, rep.Results[10].Hint, "username and password")
+}
+
+func TestDoctorPlaintext(t *testing.T) {
+	d := newTestDoctor("couchbase://10.0.0.1,10.0.0.2")
+
+	var dialed []string
+	d.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dialed = append(dialed, address)
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	}
+
+	rep := d.run()
+	assert.Equal(t, []string{"10.0.0.1:11210", "10.0.0.2:11210", "10.0.0.1:8091", "10.0.0.2:8091", "10.0.0.1:11210"},
+		dialed)
+	assert.Equal(t, []string{
+		"[PASS] Connection string",
+		"[WARN] Transport security",
+		"[INFO] DNS SRV",
+		"[PASS] TCP kv 10.0.0.1:11210",
+		"[PASS] TCP kv 10.0.0.2:11210",
+		"[PASS] TCP mgmt 10.0.0.1:8091",
+		"[PASS] TCP mgmt 10.0.0.2:8091",
+		"[FAIL] Cluster map",
+		"[FAIL] SASL authentication 10.0.0.1:11210",
+		"[FAIL] Connect",
+	}, resultNames(rep))
+
+	d = newTestDoctor("couchbase://10.0.0.1:8091")
+	rep = d.run()
+	assert.Equal(t, "[FAIL] Seed hosts", resultNames(rep)[len(rep.Results)-1])
+}
+
+func TestDoctorClusterMap(t *testing.T) {
+	d := newTestDoctor("couchbase://10.0.0.1")
+
+	var dialed []string
+	d.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dialed = append(dialed, address)
+		if strings.HasSuffix(address, ":8094") {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("i/o timeout")}
+		}
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	}
+	d.httpDo = func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "http://10.0.0.1:8091/pools/default/nodeServices", req.URL.String())
+		username, password, _ := req.BasicAuth()
+		assert.Equal(t, "Administrator", username)
+		assert.Equal(t, "password", password)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Body: io.NopCloser(strings.NewReader(`{"nodesExt":[
+				{"services":{"mgmt":8091,"mgmtSSL":18091,"kv":11210,"kvSSL":11207,"n1ql":8093,"n1qlSSL":18093},
+					"hostname":"10.0.0.1"},
+				{"services":{"mgmt":8091,"kv":11210,"fts":8094,"ftsSSL":18094},"hostname":"node2.example.com"}
+			]}`)),
+		}, nil
+	}
+
+	rep := d.run()
+	assert.Equal(t, []string{
+		"[PASS] Connection string",
+		"[WARN] Transport security",
+		"[INFO] DNS SRV",
+		"[PASS] TCP kv 10.0.0.1:11210",
+		"[PASS] TCP mgmt 10.0.0.1:8091",
+		"[PASS] Cluster map",
+		"[PASS] DNS node2.example.com",
+		"[PASS] TCP query 10.0.0.1:8093",
+		"[PASS] TCP kv node2.example.com:11210",
+		"[PASS] TCP mgmt node2.example.com:8091",
+		"[FAIL] TCP search node2.example.com:8094",
+		"[FAIL] SASL authentication 10.0.0.1:11210",
+		"[FAIL] Connect",
+	}, resultNames(rep))
+	assert.Equal(t, "fetched from 10.0.0.1, 2 nodes, 6 service ports", rep.Results[5].Detail)
+	assert.Contains(t, rep.Results[10].Hint, "firewalls")
+
+	d = newTestDoctor("couchbase://10.0.0.1")
+	d.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	}
+	d.httpDo = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Status:     "401 Unauthorized",
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+	rep = d.run()
+	require.Equal(t, "[FAIL] Cluster map", resultNames(rep)[5])
+	assert.Equal(t, "401 Unauthorized: "+gocb.ErrAuthenticationFailure.Error(), rep.Results[5].Detail)
+	assert.Equal(t, "check the username and password", rep.Results[5].Hint)
+}
+
+func TestClusterMapEndpointsExternal(t *testing.T) {
+	cfg := &clusterMap{Nodes: []clusterMapNode{
+		{
+			Hostname: "172.16.0.1",
+			Services: map[string]int{"kvSSL": 11207, "n1qlSSL": 18093},
+			AlternateAddresses: map[string]clusterMapAlternateAddr{
+				"external": {Hostname: "cb1.example.com", Ports: map[string]int{"kvSSL": 31207, "n1qlSSL": 31093}},
+			},
+		},
+		{
+			Hostname: "172.16.0.2",
+			Services: map[string]int{"kvSSL": 11207},
+			AlternateAddresses: map[string]clusterMapAlternateAddr{
+				"external": {Hostname: "cb2.example.com"},
+			},
+		},
+	}}
+
+	assert.Equal(t, []serviceEndpoint{
+		{service: "kv", addr: gocbconnstr.Address{Host: "cb1.example.com", Port: 31207}},
+		{service: "query", addr: gocbconnstr.Address{Host: "cb1.example.com", Port: 31093}},
+		{service: "kv", addr: gocbconnstr.Address{Host: "cb2.example.com", Port: 11207}},
+	}, clusterMapEndpoints(cfg, "cb1.example.com", true))
+
+	assert.Equal(t, []serviceEndpoint{
+		{service: "kv", addr: gocbconnstr.Address{Host: "172.16.0.1", Port: 11207}},
+		{service: "query", addr: gocbconnstr.Address{Host: "172.16.0.1", Port: 18093}},
+		{service: "kv", addr: gocbconnstr.Address{Host: "172.16.0.2", Port: 11207}},
+	}, clusterMapEndpoints(cfg, "172.16.0.1", true))
+}
+
+func TestDoctorSASL(t *testing.T) {
+	d := newTestDoctor("couchbase://10.0.0.1")
+
+	var received []memd.CmdCode
+	d.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			conn := memd.NewConn(server)
+			for {
+				req, _, err := conn.ReadPacket()
+				if err != nil {
+					return
+				}
+				received = append(received, req.Command)
+
+				resp := &memd.Packet{Magic: memd.CmdMagicRes, Command: req.Command, Opaque: req.Opaque}
+				switch req.Command {
+				case memd.CmdSASLListMechs:
+					resp.Value = []byte("PLAIN SCRAM-SHA1 SCRAM-SHA512")
+				case memd.CmdSASLAuth:
+					assert.Equal(t, "SCRAM-SHA512", string(req.Key))
+					resp.Status = memd.StatusAuthError
+				}
+				if err := conn.WritePacket(resp); err != nil {
+					return
+				}
+			}
+		}()
+		return client, nil
+	}
+
+	d.checkSASL([]gocbconnstr.Address{{Host: "10.0.0.1", Port: 11210}}, false)
+	require.Len(t, d.report.Results, 1)
+	result := d.report.Results[0]
+	assert.Equal(t, "SASL authentication 10.0.0.1:11210", result.Name)
+	assert.Equal(t, statusFail, result.Status)
+	assert.Contains(t, result.Detail, "the server rejected the credentials")
+	assert.Contains(t, result.Hint, "username and password")
+	assert.Equal(t, []memd.CmdCode{memd.CmdSASLListMechs, memd.CmdSASLAuth}, received)
+
+	d = newTestDoctor("couchbase://10.0.0.1")
+	d.opts.Username = ""
+	d.checkSASL([]gocbconnstr.Address{{Host: "10.0.0.1", Port: 11210}}, false)
+	assert.Equal(t, []string{"[INFO] SASL authentication"}, resultNames(d.report))
+}
+
+func TestPickSaslMechanism(t *testing.T) {
+	mech, ok := pickSaslMechanism([]gocbcore.AuthMechanism{"PLAIN", "SCRAM-SHA1", "SCRAM-SHA256"}, false)
+	assert.True(t, ok)
+	assert.Equal(t, gocbcore.ScramSha256AuthMechanism, mech)
+
+	_, ok = pickSaslMechanism([]gocbcore.AuthMechanism{"PLAIN"}, false)
+	assert.False(t, ok)
+
+	mech, ok = pickSaslMechanism([]gocbcore.AuthMechanism{"PLAIN"}, true)
+	assert.True(t, ok)
+	assert.Equal(t, gocbcore.PlainAuthMechanism, mech)
+}
+
+func TestDoctorTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	check := func(d *doctor) checkResult {
+		d.dial = net.DialTimeout
+		d.checkEndpoint("kv", gocbconnstr.Address{Host: "127.0.0.1", Port: addr.Port}, true)
+		require.Len(t, d.report.Results, 2)
+		assert.Equal(t, statusPass, d.report.Results[0].Status)
+		return d.report.Results[1]
+	}
+
+	result := check(newTestDoctor("couchbases://127.0.0.1"))
+	assert.Equal(t, statusFail, result.Status)
+	assert.Contains(t, result.Hint, "-cacert")
+
+	d := newTestDoctor("couchbases://127.0.0.1")
+	d.opts.Security.TLSRootCAs = x509.NewCertPool()
+	d.opts.Security.TLSRootCAs.AddCert(srv.Certificate())
+	result = check(d)
+	assert.Equal(t, statusPass, result.Status)
+	assert.Contains(t, result.Detail, "TLS 1.3")
+
+	// The test certificate expires far in the future.
+	d = newTestDoctor("couchbases://127.0.0.1")
+	d.opts.Security.TLSRootCAs = x509.NewCertPool()
+	d.opts.Security.TLSRootCAs.AddCert(srv.Certificate())
+	d.now = func() time.Time {
+		return srv.Certificate().NotAfter.Add(-24 * time.Hour)
+	}
+	result = check(d)
+	assert.Equal(t, statusWarn, result.Status)
+	assert.Contains(t, result.Hint, "expires soon")
+
+	d = newTestDoctor("couchbases://127.0.0.1")
+	d.opts.Security.TLSSkipVerify = true
+	result = check(d)
+	assert.Equal(t, statusWarn, result.Status)
+	assert.Contains(t, result.Detail, "certificate not verified")
+}
+
+func TestDoctorPing(t *testing.T) {
+	d := newTestDoctor("couchbase://10.0.0.1")
+	d.checkPing(&gocb.PingResult{
+		Services: map[gocb.ServiceType][]gocb.EndpointPingReport{
+			gocb.ServiceTypeQuery: {
+				{Remote: "10.0.0.1:8093", State: gocb.PingStateOk, Latency: 1500 * time.Microsecond},
+				{Remote: "10.0.0.2:8093", State: gocb.PingStateTimeout},
+			},
+			gocb.ServiceTypeKeyValue: {
+				{Remote: "10.0.0.1:11210", State: gocb.PingStateError, Error: "connection reset"},
+			},
+		},
+	})
+
+	assert.Equal(t, []checkResult{
+		{Name: "Ping kv 10.0.0.1:11210", Status: statusFail, Detail: "connection reset",
+			Hint: "the node is reachable for bootstrap but not on this service's port, check firewalls allow all " +
+				"Couchbase service ports"},
+		{Name: "Ping query 10.0.0.1:8093", Status: statusPass, Detail: "latency 1.5ms"},
+		{Name: "Ping query 10.0.0.2:8093", Status: statusFail, Detail: "timeout",
+			Hint: "the service did not respond in time, check firewalls and the load on the node"},
+	}, d.report.Results)
+}
+
+func TestReportWrite(t *testing.T) {
+	rep := &report{Target: "couchbase://10.0.0.1"}
+	rep.pass("TCP kv 10.0.0.1:11210", "connected in 1ms")
+	rep.warn("Transport security", "connection is not encrypted", "use couchbases://")
+	rep.fail("Connect", gocb.ErrBucketNotFound, "")
+	rep.info("DNS SRV", "not used")
+
+	var buf bytes.Buffer
+	rep.write(&buf)
+	assert.Equal(t, strings.Join([]string{
+		"gocb-doctor report for couchbase://10.0.0.1",
+		"",
+		"[PASS] TCP kv 10.0.0.1:11210: connected in 1ms",
+		"[WARN] Transport security: connection is not encrypted",
+		"       hint: use couchbases://",
+		"[FAIL] Connect: bucket not found",
+		"       hint: check the bucket name, bucket names are case sensitive",
+		"[INFO] DNS SRV: not used",
+		"",
+		"1 passed, 1 warnings, 1 failed",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestHintForError(t *testing.T) {
+	assert.Empty(t, hintForError(nil))
+	assert.Empty(t, hintForError(errors.New("something else")))
+	assert.Contains(t, hintForError(gocb.ErrUnambiguousTimeout), "network=external")
+	assert.Contains(t, hintForError(&net.OpError{Op: "dial", Err: errors.New("i/o timeout")}), "firewalls")
+	assert.Contains(t, hintForError(x509.HostnameError{Host: "cb.example.com"}), "host name")
+}
@@ -0,0 +1,75 @@
+// Command gocb-doctor checks connectivity to a Couchbase cluster and prints a report with hints on how to fix any
+// problems found.
+//
+// Usage:
+//
+//	gocb-doctor -u Administrator -p password -bucket travel-sample couchbases://cb.example.com
+package main
+
+import (
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lissteron/gocb"
+)
+
+func main() {
+	var (
+		username   = flag.String("u", "", "username to authenticate with")
+		password   = flag.String("p", "", "password to authenticate with")
+		bucket     = flag.String("bucket", "", "bucket to check access to")
+		caCert     = flag.String("cacert", "", "path to a PEM encoded CA certificate to trust")
+		skipVerify = flag.Bool("insecure", false, "skip verification of the cluster's TLS certificate")
+		timeout    = flag.Duration("timeout", 10*time.Second, "timeout for each check")
+		verbose    = flag.Bool("v", false, "enable SDK logging")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <connection string>\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *verbose {
+		gocb.SetLogger(gocb.VerboseStdioLogger())
+	}
+
+	security := gocb.SecurityConfig{
+		TLSSkipVerify: *skipVerify,
+	}
+	if *caCert != "" {
+		pem, err := os.ReadFile(*caCert)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read CA certificate: %s\n", err)
+			os.Exit(2)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			fmt.Fprintf(os.Stderr, "no certificates found in %s\n", *caCert)
+			os.Exit(2)
+		}
+		security.TLSRootCAs = pool
+	}
+
+	rep := newDoctor(doctorOptions{
+		ConnStr:  flag.Arg(0),
+		Username: *username,
+		Password: *password,
+		Bucket:   *bucket,
+		Timeout:  *timeout,
+		Security: security,
+	}).run()
+
+	rep.write(os.Stdout)
+	if rep.failed() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/lissteron/gocb"
+)
+
+type checkStatus int
+
+const (
+	statusPass checkStatus = iota
+	statusInfo
+	statusWarn
+	statusFail
+)
+
+func (s checkStatus) String() string {
+	switch s {
+	case statusPass:
+		return "PASS"
+	case statusInfo:
+		return "INFO"
+	case statusWarn:
+		return "WARN"
+	case statusFail:
+		return "FAIL"
+	default:
+		return "????"
+	}
+}
+
+type checkResult struct {
+	Name   string
+	Status checkStatus
+	Detail string
+	Hint   string
+}
+
+type report struct {
+	Target  string
+	Results []checkResult
+}
+
+func (r *report) add(name string, status checkStatus, detail, hint string) {
+	r.Results = append(r.Results, checkResult{
+		Name:   name,
+		Status: status,
+		Detail: detail,
+		Hint:   hint,
+	})
+}
+
+func (r *report) pass(name, detail string) {
+	r.add(name, statusPass, detail, "")
+}
+
+func (r *report) info(name, detail string) {
+	r.add(name, statusInfo, detail, "")
+}
+
+func (r *report) warn(name, detail, hint string) {
+	r.add(name, statusWarn, detail, hint)
+}
+
+// fail records a failed check, picking a remediation hint for err if one isn't given.
+func (r *report) fail(name string, err error, hint string) {
+	if hint == "" {
+		hint = hintForError(err)
+	}
+	r.add(name, statusFail, err.Error(), hint)
+}
+
+func (r *report) failed() bool {
+	for _, result := range r.Results {
+		if result.Status == statusFail {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *report) write(w io.Writer) {
+	fmt.Fprintf(w, "gocb-doctor report for %s\n\n", r.Target)
+
+	counts := make(map[checkStatus]int)
+	for _, result := range r.Results {
+		counts[result.Status]++
+
+		fmt.Fprintf(w, "[%s] %s", result.Status, result.Name)
+		if result.Detail != "" {
+			fmt.Fprintf(w, ": %s", result.Detail)
+		}
+		fmt.Fprintln(w)
+		if result.Hint != "" {
+			fmt.Fprintf(w, "       hint: %s\n", result.Hint)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", counts[statusPass], counts[statusWarn],
+		counts[statusFail])
+}
+
+// hintForError returns a remediation hint for the most common connectivity misconfigurations.
+func hintForError(err error) string {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCertErr x509.CertificateInvalidError
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, gocb.ErrAuthenticationFailure):
+		return "check the username and password, and that the user has been granted access to the bucket"
+	case errors.Is(err, gocb.ErrBucketNotFound):
+		return "check the bucket name, bucket names are case sensitive"
+	case errors.As(err, &unknownAuthErr):
+		return "the certificate is not signed by a trusted CA, pass the cluster CA certificate with -cacert"
+	case errors.As(err, &hostnameErr):
+		return "the certificate does not cover this host name, connect using a host name listed in the certificate"
+	case errors.As(err, &invalidCertErr):
+		return "the certificate is invalid or has expired, check the certificate installed on the cluster"
+	case errors.As(err, &dnsErr):
+		return "the host name could not be resolved, check the connection string and DNS configuration"
+	case errors.Is(err, gocb.ErrTimeout):
+		return "the cluster did not respond in time, check firewalls and whether the cluster advertises " +
+			"addresses reachable from this machine (try network=external for clusters behind NAT)"
+	case errors.As(err, &opErr):
+		if strings.Contains(err.Error(), "refused") {
+			return "nothing is listening on this port, check the port and that the service is running on the node"
+		}
+		return "the port could not be reached, check firewalls and security groups between this machine and the cluster"
+	case errors.Is(err, gocb.ErrServiceNotAvailable):
+		return "the service is not running on the cluster, check which services are enabled on its nodes"
+	case errors.Is(err, gocb.ErrInvalidArgument):
+		return "check the connection string, see https://docs.couchbase.com/go-sdk/current/howtos/managing-connections.html"
+	default:
+		return ""
+	}
+}
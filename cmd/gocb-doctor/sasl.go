@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/couchbase/gocbcore/v10/memd"
+	"github.com/lissteron/gocb"
+)
+
+// saslMechanisms are the mechanisms that the SASL check will use, strongest first. PLAIN sends the password in the
+// clear so is only used over TLS.
+var saslMechanisms = []gocbcore.AuthMechanism{
+	gocbcore.ScramSha512AuthMechanism,
+	gocbcore.ScramSha256AuthMechanism,
+	gocbcore.ScramSha1AuthMechanism,
+	gocbcore.PlainAuthMechanism,
+}
+
+// saslClient is a minimal, synchronous gocbcore.AuthClient over a single key-value connection. It lets the doctor
+// check credentials on their own, rather than as one of the many reasons that bootstrapping the SDK can fail.
+type saslClient struct {
+	address string
+	conn    *memd.Conn
+	opaque  uint32
+}
+
+func (c *saslClient) Address() string {
+	return c.address
+}
+
+func (c *saslClient) SupportsFeature(feature memd.HelloFeature) bool {
+	return false
+}
+
+func (c *saslClient) SaslListMechs(deadline time.Time, cb func(mechs []gocbcore.AuthMechanism, err error)) error {
+	value, err := c.roundTrip(memd.CmdSASLListMechs, nil, nil)
+	if err != nil {
+		cb(nil, err)
+		return nil
+	}
+
+	var mechs []gocbcore.AuthMechanism
+	for _, mech := range strings.Fields(string(value)) {
+		mechs = append(mechs, gocbcore.AuthMechanism(mech))
+	}
+	cb(mechs, nil)
+	return nil
+}
+
+func (c *saslClient) SaslAuth(k, v []byte, deadline time.Time, cb func(b []byte, err error)) error {
+	value, err := c.roundTrip(memd.CmdSASLAuth, k, v)
+	cb(value, err)
+	return nil
+}
+
+func (c *saslClient) SaslStep(k, v []byte, deadline time.Time, cb func(err error)) error {
+	_, err := c.roundTrip(memd.CmdSASLStep, k, v)
+	cb(err)
+	return nil
+}
+
+func (c *saslClient) roundTrip(command memd.CmdCode, key, value []byte) ([]byte, error) {
+	c.opaque++
+	err := c.conn.WritePacket(&memd.Packet{
+		Magic:   memd.CmdMagicReq,
+		Command: command,
+		Key:     key,
+		Value:   value,
+		Opaque:  c.opaque,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := c.conn.ReadPacket()
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status {
+	case memd.StatusSuccess:
+		return resp.Value, nil
+	case memd.StatusAuthContinue:
+		// gocbcore's SCRAM implementation expects continuations to be reported as a KeyValueError.
+		return resp.Value, &gocbcore.KeyValueError{
+			InnerError: errors.New("authentication continues"),
+			StatusCode: resp.Status,
+		}
+	case memd.StatusAuthError:
+		return nil, fmt.Errorf("the server rejected the credentials: %w", gocb.ErrAuthenticationFailure)
+	default:
+		return nil, fmt.Errorf("unexpected response to %s: %s", command.Name(), resp.Status)
+	}
+}
+
+// authenticate picks the strongest mechanism that both sides support and authenticates with it.
+func (c *saslClient) authenticate(username, password string, useTLS bool, deadline time.Time) (
+	gocbcore.AuthMechanism, error) {
+	var serverMechs []gocbcore.AuthMechanism
+	var err error
+	_ = c.SaslListMechs(deadline, func(mechs []gocbcore.AuthMechanism, listErr error) {
+		serverMechs, err = mechs, listErr
+	})
+	if err != nil {
+		return "", err
+	}
+
+	mech, ok := pickSaslMechanism(serverMechs, useTLS)
+	if !ok {
+		return "", fmt.Errorf("no supported mechanism offered by the server, it offered %s", joinMechs(serverMechs))
+	}
+
+	completed := func(authErr error) {
+		err = authErr
+	}
+	switch mech {
+	case gocbcore.PlainAuthMechanism:
+		_ = gocbcore.SaslAuthPlain(username, password, c, deadline, completed)
+	case gocbcore.ScramSha1AuthMechanism:
+		_ = gocbcore.SaslAuthScramSha1(username, password, c, deadline, func() {}, completed)
+	case gocbcore.ScramSha256AuthMechanism:
+		_ = gocbcore.SaslAuthScramSha256(username, password, c, deadline, func() {}, completed)
+	case gocbcore.ScramSha512AuthMechanism:
+		_ = gocbcore.SaslAuthScramSha512(username, password, c, deadline, func() {}, completed)
+	}
+
+	return mech, err
+}
+
+func pickSaslMechanism(serverMechs []gocbcore.AuthMechanism, useTLS bool) (gocbcore.AuthMechanism, bool) {
+	for _, mech := range saslMechanisms {
+		if mech == gocbcore.PlainAuthMechanism && !useTLS {
+			continue
+		}
+		for _, serverMech := range serverMechs {
+			if mech == serverMech {
+				return mech, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func joinMechs(mechs []gocbcore.AuthMechanism) string {
+	if len(mechs) == 0 {
+		return "none"
+	}
+
+	names := make([]string, len(mechs))
+	for i, mech := range mechs {
+		names[i] = string(mech)
+	}
+	return strings.Join(names, ", ")
+}
@@ -12,6 +12,8 @@ type Collection struct {
 	retryStrategyWrapper *coreRetryStrategyWrapper
 	tracer               RequestTracer
 	meter                *meterWrapper
+	admission            *admissionController
+	interceptors         *operationInterceptors
 
 	useMutationTokens bool
 
@@ -22,6 +24,27 @@ type Collection struct {
 }
 
 func newCollection(scope *Scope, collectionName string) *Collection {
+	getKvProvider := scope.getKvProvider
+	getKvBulkProvider := scope.getKvBulkProvider
+	if interceptors := scope.interceptors.forCollection(scope.BucketName(), scope.Name(), collectionName); len(interceptors) > 0 {
+		getKvProvider = func() (kvProvider, error) {
+			provider, err := scope.getKvProvider()
+			if err != nil {
+				return nil, err
+			}
+
+			return &interceptingKvProvider{provider: provider, interceptors: interceptors}, nil
+		}
+		getKvBulkProvider = func() (kvBulkProvider, error) {
+			provider, err := scope.getKvBulkProvider()
+			if err != nil {
+				return nil, err
+			}
+
+			return &interceptingKvBulkProvider{provider: provider, interceptors: interceptors}, nil
+		}
+	}
+
 	return &Collection{
 		collectionName: collectionName,
 		scope:          scope.Name(),
@@ -33,11 +56,13 @@ func newCollection(scope *Scope, collectionName string) *Collection {
 		retryStrategyWrapper: scope.retryStrategyWrapper,
 		tracer:               scope.tracer,
 		meter:                scope.meter,
+		admission:            scope.admission,
+		interceptors:         scope.interceptors,
 
 		useMutationTokens: scope.useMutationTokens,
 
-		getKvProvider:         scope.getKvProvider,
-		getKvBulkProvider:     scope.getKvBulkProvider,
+		getKvProvider:         getKvProvider,
+		getKvBulkProvider:     getKvBulkProvider,
 		getQueryIndexProvider: scope.getQueryIndexProvider,
 		getQueryProvider:      scope.getQueryProvider,
 	}
@@ -87,6 +112,16 @@ func (c *Collection) startKvOpTrace(operationName string, tracectx RequestSpanCo
 	return span
 }
 
+func (c *Collection) meterOperation(operation string) meterOperation {
+	return meterOperation{
+		service:    meterValueServiceKV,
+		operation:  operation,
+		bucket:     c.bucketName(),
+		scope:      c.ScopeName(),
+		collection: c.name(),
+	}
+}
+
 func (c *Collection) bucketName() string {
 	return c.bucket.Name()
 }
@@ -12,7 +12,9 @@ type bulkOp struct {
 }
 
 func (op *bulkOp) finish() {
-	op.finishFn()
+	if op.finishFn != nil {
+		op.finishFn()
+	}
 }
 
 // BulkOp represents a single operation that can be submitted (within a list of more operations) to .Do()
@@ -22,6 +24,7 @@ func (op *bulkOp) finish() {
 type BulkOp interface {
 	isBulkOp()
 	finish()
+	setErr(err error)
 }
 
 // BulkOpOptions are the set of options available when performing BulkOps using Do.
@@ -58,6 +61,10 @@ func (c *Collection) KvCoreBulkProvider() (kvProviderCoreProvider, error) {
 		return nil, err
 	}
 
+	if intercepting, ok := agent.(*interceptingKvBulkProvider); ok {
+		agent = intercepting.provider
+	}
+
 	if a, ok := agent.(*kvBulkProviderCore); ok {
 		return a.agent, nil
 	}
@@ -77,6 +84,10 @@ type GetOp struct {
 
 func (item *GetOp) isBulkOp() {}
 
+func (item *GetOp) setErr(err error) {
+	item.Err = err
+}
+
 // GetAndTouchOp represents a type of `BulkOp` used for GetAndTouch operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type GetAndTouchOp struct {
@@ -90,6 +101,10 @@ type GetAndTouchOp struct {
 
 func (item *GetAndTouchOp) isBulkOp() {}
 
+func (item *GetAndTouchOp) setErr(err error) {
+	item.Err = err
+}
+
 // TouchOp represents a type of `BulkOp` used for Touch operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type TouchOp struct {
@@ -103,6 +118,10 @@ type TouchOp struct {
 
 func (item *TouchOp) isBulkOp() {}
 
+func (item *TouchOp) setErr(err error) {
+	item.Err = err
+}
+
 // RemoveOp represents a type of `BulkOp` used for Remove operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type RemoveOp struct {
@@ -116,6 +135,10 @@ type RemoveOp struct {
 
 func (item *RemoveOp) isBulkOp() {}
 
+func (item *RemoveOp) setErr(err error) {
+	item.Err = err
+}
+
 // UpsertOp represents a type of `BulkOp` used for Upsert operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type UpsertOp struct {
@@ -131,6 +154,10 @@ type UpsertOp struct {
 
 func (item *UpsertOp) isBulkOp() {}
 
+func (item *UpsertOp) setErr(err error) {
+	item.Err = err
+}
+
 // InsertOp represents a type of `BulkOp` used for Insert operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type InsertOp struct {
@@ -145,6 +172,10 @@ type InsertOp struct {
 
 func (item *InsertOp) isBulkOp() {}
 
+func (item *InsertOp) setErr(err error) {
+	item.Err = err
+}
+
 // ReplaceOp represents a type of `BulkOp` used for Replace operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type ReplaceOp struct {
@@ -160,6 +191,10 @@ type ReplaceOp struct {
 
 func (item *ReplaceOp) isBulkOp() {}
 
+func (item *ReplaceOp) setErr(err error) {
+	item.Err = err
+}
+
 // AppendOp represents a type of `BulkOp` used for Append operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type AppendOp struct {
@@ -173,6 +208,10 @@ type AppendOp struct {
 
 func (item *AppendOp) isBulkOp() {}
 
+func (item *AppendOp) setErr(err error) {
+	item.Err = err
+}
+
 // PrependOp represents a type of `BulkOp` used for Prepend operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type PrependOp struct {
@@ -186,6 +225,10 @@ type PrependOp struct {
 
 func (item *PrependOp) isBulkOp() {}
 
+func (item *PrependOp) setErr(err error) {
+	item.Err = err
+}
+
 // IncrementOp represents a type of `BulkOp` used for Increment operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type IncrementOp struct {
@@ -202,6 +245,10 @@ type IncrementOp struct {
 
 func (item *IncrementOp) isBulkOp() {}
 
+func (item *IncrementOp) setErr(err error) {
+	item.Err = err
+}
+
 // DecrementOp represents a type of `BulkOp` used for Decrement operations. See BulkOp.
 // UNCOMMITTED: This API may change in the future.
 type DecrementOp struct {
@@ -217,3 +264,7 @@ type DecrementOp struct {
 }
 
 func (item *DecrementOp) isBulkOp() {}
+
+func (item *DecrementOp) setErr(err error) {
+	item.Err = err
+}
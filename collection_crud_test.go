@@ -1611,7 +1611,7 @@ func (suite *IntegrationTestSuite) TestGetAndLock() {
 	}
 
 	mutRes, err = globalCollection.Upsert("getAndLock", doc, &UpsertOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if err == nil {
 		suite.T().Fatalf("Expected error but was nil")
@@ -1624,7 +1624,7 @@ func (suite *IntegrationTestSuite) TestGetAndLock() {
 	globalCluster.TimeTravel(2000 * time.Millisecond)
 
 	mutRes, err = globalCollection.Upsert("getAndLock", doc, &UpsertOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if err != nil {
 		suite.T().Fatalf("Upsert failed, error was %v", err)
@@ -1766,7 +1766,7 @@ func (suite *IntegrationTestSuite) TestUnlockInvalidCas() {
 	}
 
 	err = globalCollection.Unlock("unlockInvalidCas", lockedDoc.Cas()+1, &UnlockOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if err == nil {
 		suite.T().Fatalf("Unlock should have failed")
@@ -1823,7 +1823,7 @@ func (suite *IntegrationTestSuite) TestDoubleLockFail() {
 	}
 
 	_, err = globalCollection.GetAndLock("doubleLock", 1, &GetAndLockOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if err == nil {
 		suite.T().Fatalf("Expected GetAndLock to fail")
@@ -125,6 +125,7 @@ func (rs SamplingScan) toCore() (*gocbcore.RangeScanCreateRandomSamplingConfig,
 }
 
 // Scan performs a scan across a Collection, returning a stream of documents.
+// Scans are not supported when connected using the couchbase2 protocol.
 // VOLATILE: This API is subject to change at any time.
 func (c *Collection) Scan(scanType ScanType, opts *ScanOptions) (*ScanResult, error) {
 	if opts == nil {
@@ -62,6 +62,7 @@ type LookupInAnyReplicaOptions struct {
 }
 
 // LookupInAnyReplica returns the value of a particular document from a replica server.
+// Not supported when connected using the couchbase2 protocol.
 //
 // # VOLATILE
 //
@@ -103,6 +104,7 @@ type LookupInAllReplicaOptions struct {
 
 // LookupInAllReplicas returns the value of a particular document from all replica servers. This will return an iterable
 // which streams results one at a time.
+// Not supported when connected using the couchbase2 protocol.
 //
 // # VOLATILE
 //
@@ -0,0 +1,79 @@
+package gocb
+
+import (
+	"github.com/couchbase/goprotostellar/genproto/kv_v1"
+	"github.com/golang/snappy"
+)
+
+const (
+	psCompressionDefaultMinSize  = 32
+	psCompressionDefaultMinRatio = 0.83
+)
+
+// psCompressor applies the CompressionConfig to documents sent and received over PS. The memcached client in
+// gocbcore handles this for the classic protocol, over PS we must do it ourselves, using the same thresholds.
+// A nil psCompressor sends all values uncompressed.
+type psCompressor struct {
+	minSize  int
+	minRatio float64
+}
+
+func newPsCompressor(config CompressionConfig) *psCompressor {
+	if config.Disabled {
+		return nil
+	}
+
+	compressor := &psCompressor{
+		minSize:  psCompressionDefaultMinSize,
+		minRatio: psCompressionDefaultMinRatio,
+	}
+	if config.MinSize > 0 {
+		compressor.minSize = int(config.MinSize)
+	}
+	if config.MinRatio > 0 {
+		compressor.minRatio = config.MinRatio
+		if compressor.minRatio >= 1.0 {
+			compressor.minRatio = 1.0
+		}
+	}
+
+	return compressor
+}
+
+// Compress returns the snappy compressed form of value and true if the value is large enough to be considered and
+// compresses well enough to be worth sending compressed, otherwise it returns value unchanged and false.
+func (c *psCompressor) Compress(value []byte) ([]byte, bool) {
+	if c == nil || len(value) <= c.minSize {
+		return value, false
+	}
+
+	compressed := snappy.Encode(nil, value)
+	if float64(len(compressed))/float64(len(value)) > c.minRatio {
+		return value, false
+	}
+
+	return compressed, true
+}
+
+// RequestCompression returns the compression preference to send on read requests, when compression is disabled the
+// server is left to return values uncompressed.
+func (c *psCompressor) RequestCompression() *kv_v1.CompressionEnabled {
+	if c == nil {
+		return nil
+	}
+
+	return kv_v1.CompressionEnabled_COMPRESSION_ENABLED_OPTIONAL.Enum()
+}
+
+// psDecompressValue decodes a snappy compressed value returned by the server. This is done regardless of whether
+// compression is enabled, the server may still choose to return compressed values.
+func psDecompressValue(value []byte) ([]byte, error) {
+	decoded, err := snappy.Decode(nil, value)
+	if err != nil {
+		return nil, makeGenericError(err, map[string]interface{}{
+			"reason": "failed to decompress value returned by the server",
+		})
+	}
+
+	return decoded, nil
+}
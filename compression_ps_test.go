@@ -0,0 +1,110 @@
+package gocb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/couchbase/goprotostellar/genproto/kv_v1"
+	"google.golang.org/grpc"
+)
+
+func (suite *UnitTestSuite) TestPsCompressor() {
+	compressible := bytes.Repeat([]byte(`{"name":"couchbase"}`), 10)
+
+	compressor := newPsCompressor(CompressionConfig{})
+	suite.Require().NotNil(compressor)
+	suite.Assert().Equal(psCompressionDefaultMinSize, compressor.minSize)
+	suite.Assert().Equal(psCompressionDefaultMinRatio, compressor.minRatio)
+	suite.Assert().Equal(kv_v1.CompressionEnabled_COMPRESSION_ENABLED_OPTIONAL, *compressor.RequestCompression())
+
+	compressed, ok := compressor.Compress(compressible)
+	suite.Require().True(ok)
+	suite.Assert().Less(len(compressed), len(compressible))
+
+	decompressed, err := psDecompressValue(compressed)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(compressible, decompressed)
+
+	// Values at or below the minimum size are never compressed.
+	small := []byte(`{"a":1}`)
+	value, ok := compressor.Compress(small)
+	suite.Assert().False(ok)
+	suite.Assert().Equal(small, value)
+
+	// Values which do not compress well enough are sent as is.
+	compressor = newPsCompressor(CompressionConfig{MinSize: 1, MinRatio: 0.01})
+	value, ok = compressor.Compress(compressible)
+	suite.Assert().False(ok)
+	suite.Assert().Equal(compressible, value)
+
+	compressor = newPsCompressor(CompressionConfig{Disabled: true})
+	suite.Assert().Nil(compressor)
+	suite.Assert().Nil(compressor.RequestCompression())
+	value, ok = compressor.Compress(compressible)
+	suite.Assert().False(ok)
+	suite.Assert().Equal(compressible, value)
+
+	_, err = psDecompressValue([]byte("not snappy"))
+	suite.Assert().Error(err)
+}
+
+type fakePsCompressionKvClient struct {
+	kv_v1.KvServiceClient
+
+	upsert *kv_v1.UpsertRequest
+	get    *kv_v1.GetRequest
+	stored []byte
+}
+
+func (c *fakePsCompressionKvClient) Upsert(_ context.Context, in *kv_v1.UpsertRequest,
+	_ ...grpc.CallOption) (*kv_v1.UpsertResponse, error) {
+	c.upsert = in
+	return &kv_v1.UpsertResponse{Cas: 1}, nil
+}
+
+func (c *fakePsCompressionKvClient) Get(_ context.Context, in *kv_v1.GetRequest,
+	_ ...grpc.CallOption) (*kv_v1.GetResponse, error) {
+	c.get = in
+	return &kv_v1.GetResponse{
+		Cas:          1,
+		Content:      &kv_v1.GetResponse_ContentCompressed{ContentCompressed: c.stored},
+		ContentFlags: 0x02000000,
+	}, nil
+}
+
+func (suite *UnitTestSuite) TestPsKvCompression() {
+	client := &fakePsCompressionKvClient{}
+	provider := &kvProviderPs{client: client, compressor: newPsCompressor(CompressionConfig{})}
+	col := suite.collection("default", "_default", "_default", provider)
+
+	doc := map[string]string{"description": strings.Repeat("couchbase ", 20)}
+	_, err := col.Upsert("key", doc, nil)
+	suite.Require().NoError(err)
+
+	content, ok := client.upsert.Content.(*kv_v1.UpsertRequest_ContentCompressed)
+	suite.Require().True(ok)
+	client.stored = content.ContentCompressed
+
+	res, err := col.Get("key", nil)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(kv_v1.CompressionEnabled_COMPRESSION_ENABLED_OPTIONAL, client.get.GetCompression())
+
+	var out map[string]string
+	suite.Require().NoError(res.Content(&out))
+	suite.Assert().Equal(doc, out)
+
+	provider.compressor = nil
+	_, err = col.Upsert("key", doc, nil)
+	suite.Require().NoError(err)
+	suite.Assert().IsType(&kv_v1.UpsertRequest_ContentUncompressed{}, client.upsert.Content)
+}
+
+func (suite *UnitTestSuite) TestPsMutateInMacrosNotSupported() {
+	col := suite.collection("default", "_default", "_default", &kvProviderPs{})
+
+	_, err := col.MutateIn("key", []MutateInSpec{
+		UpsertSpec("cas", MutationMacroCAS, &UpsertSpecOptions{IsXattr: true}),
+	}, nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+}
@@ -1,20 +1,12 @@
 package gocb
 
-import "time"
+import (
+	"reflect"
+	"sync"
+	"time"
 
-var developmentProfile = ClusterOptions{
-	TimeoutsConfig: TimeoutsConfig{
-		KVTimeout:         20 * time.Second,
-		ConnectTimeout:    20 * time.Second,
-		KVDurableTimeout:  20 * time.Second,
-		KVScanTimeout:     20 * time.Second,
-		ViewTimeout:       120 * time.Second,
-		AnalyticsTimeout:  120 * time.Second,
-		SearchTimeout:     120 * time.Second,
-		ManagementTimeout: 120 * time.Second,
-		QueryTimeout:      120 * time.Second,
-	},
-}
+	"github.com/couchbase/gocbcore/v10"
+)
 
 // ClusterConfigProfile represents a named profile that can be applied to ClusterOptions.
 // VOLATILE: This API is subject to change at any time.
@@ -25,16 +17,313 @@ const (
 	// overwriting any properties that exist on the profile.
 	// VOLATILE: This API is subject to change at any time.
 	ClusterConfigProfileWanDevelopment ClusterConfigProfile = "wan-development"
+
+	// ClusterConfigProfileLowLatency represents a profile for latency sensitive workloads, it uses short timeouts,
+	// a tight retry backoff and aggressive circuit breakers so that requests fail fast rather than queue up.
+	// VOLATILE: This API is subject to change at any time.
+	ClusterConfigProfileLowLatency ClusterConfigProfile = "low-latency"
+
+	// ClusterConfigProfileBatch represents a profile for batch and analytics workloads, it uses long timeouts,
+	// a relaxed retry backoff, lenient circuit breakers and compression for large documents.
+	// VOLATILE: This API is subject to change at any time.
+	ClusterConfigProfileBatch ClusterConfigProfile = "batch"
+
+	// ClusterConfigProfileCloud represents a profile for connecting to Capella or any other cluster accessed over
+	// a wide area network.
+	// VOLATILE: This API is subject to change at any time.
+	ClusterConfigProfileCloud ClusterConfigProfile = "cloud"
 )
 
+// ClusterConfigProfileFunc is applied to ClusterOptions when the profile that it is registered against is applied.
+// It should overwrite any properties that the profile is responsible for.
+// VOLATILE: This API is subject to change at any time.
+type ClusterConfigProfileFunc func(opts *ClusterOptions)
+
+var (
+	clusterConfigProfilesLock sync.RWMutex
+	clusterConfigProfiles     = map[ClusterConfigProfile]ClusterConfigProfileFunc{
+		ClusterConfigProfileWanDevelopment: applyWanDevelopmentProfile,
+		ClusterConfigProfileLowLatency:     applyLowLatencyProfile,
+		ClusterConfigProfileBatch:          applyBatchProfile,
+		ClusterConfigProfileCloud:          applyCloudProfile,
+	}
+)
+
+// RegisterClusterConfigProfile registers a named profile which can then be applied using ClusterOptions.ApplyProfile
+// or the config_profile connection string option. Registering a profile with the same name as an existing profile,
+// including the built-in profiles, will replace it. When applied from the connection string, any options set
+// explicitly on the ClusterOptions passed to Connect take precedence over those set by the profile. As options are
+// plain values, a zero value, such as a Disabled field set to false, cannot be told apart from an unset option and so
+// timeouts, orphan reporter and circuit breaker fields left at zero are always taken from the profile. Other config
+// structs, such as CompressionConfig, are taken from ClusterOptions as a whole if any of their fields are set.
+// To override a profile with zero values apply it using ClusterOptions.ApplyProfile and then set the options.
+// VOLATILE: This API is subject to change at any time.
+func RegisterClusterConfigProfile(name ClusterConfigProfile, profile ClusterConfigProfileFunc) error {
+	if name == "" {
+		return makeInvalidArgumentsError("profile name cannot be empty")
+	}
+	if profile == nil {
+		return makeInvalidArgumentsError("profile cannot be nil")
+	}
+
+	clusterConfigProfilesLock.Lock()
+	clusterConfigProfiles[name] = profile
+	clusterConfigProfilesLock.Unlock()
+
+	return nil
+}
+
 // ApplyProfile will apply a named profile to the ClusterOptions overwriting any properties that
 // exist on the profile.
+// A profile selected with the config_profile connection string option is instead applied beneath the ClusterOptions
+// passed to Connect, where options left at their zero value are taken from the profile. This means that the
+// connection string can't be used to set OrphanReporterConfig.Disabled or CircuitBreakerConfig.Disabled back to
+// false, or a timeout back to zero, over a profile which changes them. To do so call ApplyProfile and then set the
+// options before calling Connect.
 // VOLATILE: This API is subject to change at any time.
 func (opts *ClusterOptions) ApplyProfile(profile ClusterConfigProfile) error {
-	if profile == ClusterConfigProfileWanDevelopment {
-		opts.TimeoutsConfig = developmentProfile.TimeoutsConfig
-		return nil
+	clusterConfigProfilesLock.RLock()
+	apply, ok := clusterConfigProfiles[profile]
+	clusterConfigProfilesLock.RUnlock()
+	if !ok {
+		return makeInvalidArgumentsError("unknown configuration profile")
 	}
 
-	return makeInvalidArgumentsError("unknown configuration profile")
+	apply(opts)
+	return nil
+}
+
+func applyWanDevelopmentProfile(opts *ClusterOptions) {
+	opts.TimeoutsConfig = TimeoutsConfig{
+		KVTimeout:         20 * time.Second,
+		ConnectTimeout:    20 * time.Second,
+		KVDurableTimeout:  20 * time.Second,
+		KVScanTimeout:     20 * time.Second,
+		ViewTimeout:       120 * time.Second,
+		AnalyticsTimeout:  120 * time.Second,
+		SearchTimeout:     120 * time.Second,
+		ManagementTimeout: 120 * time.Second,
+		QueryTimeout:      120 * time.Second,
+	}
+}
+
+func applyLowLatencyProfile(opts *ClusterOptions) {
+	opts.TimeoutsConfig = TimeoutsConfig{
+		KVTimeout:         500 * time.Millisecond,
+		ConnectTimeout:    5 * time.Second,
+		KVDurableTimeout:  2 * time.Second,
+		KVScanTimeout:     5 * time.Second,
+		ViewTimeout:       10 * time.Second,
+		AnalyticsTimeout:  30 * time.Second,
+		SearchTimeout:     10 * time.Second,
+		ManagementTimeout: 30 * time.Second,
+		QueryTimeout:      10 * time.Second,
+	}
+	opts.RetryStrategy = NewBestEffortRetryStrategy(
+		BackoffCalculator(gocbcore.ExponentialBackoff(1*time.Millisecond, 50*time.Millisecond, 2)),
+	)
+	opts.CircuitBreakerConfig = CircuitBreakerConfig{
+		VolumeThreshold:          10,
+		ErrorThresholdPercentage: 25,
+		SleepWindow:              2 * time.Second,
+		RollingWindow:            30 * time.Second,
+		CanaryTimeout:            2 * time.Second,
+	}
+	opts.CompressionConfig = CompressionConfig{
+		Disabled: true,
+	}
+	opts.OrphanReporterConfig = OrphanReporterConfig{
+		ReportInterval: 10 * time.Second,
+		SampleSize:     10,
+	}
+}
+
+func applyBatchProfile(opts *ClusterOptions) {
+	opts.TimeoutsConfig = TimeoutsConfig{
+		KVTimeout:         10 * time.Second,
+		ConnectTimeout:    30 * time.Second,
+		KVDurableTimeout:  30 * time.Second,
+		KVScanTimeout:     120 * time.Second,
+		ViewTimeout:       10 * time.Minute,
+		AnalyticsTimeout:  30 * time.Minute,
+		SearchTimeout:     5 * time.Minute,
+		ManagementTimeout: 5 * time.Minute,
+		QueryTimeout:      10 * time.Minute,
+	}
+	opts.RetryStrategy = NewBestEffortRetryStrategy(
+		BackoffCalculator(gocbcore.ExponentialBackoff(10*time.Millisecond, 2*time.Second, 2)),
+	)
+	opts.CircuitBreakerConfig = CircuitBreakerConfig{
+		VolumeThreshold:          100,
+		ErrorThresholdPercentage: 75,
+		SleepWindow:              10 * time.Second,
+		RollingWindow:            2 * time.Minute,
+		CanaryTimeout:            10 * time.Second,
+	}
+	opts.CompressionConfig = CompressionConfig{
+		MinSize:  32,
+		MinRatio: 0.83,
+	}
+	opts.OrphanReporterConfig = OrphanReporterConfig{
+		ReportInterval: 60 * time.Second,
+		SampleSize:     10,
+	}
+}
+
+func applyCloudProfile(opts *ClusterOptions) {
+	opts.TimeoutsConfig = TimeoutsConfig{
+		KVTimeout:         10 * time.Second,
+		ConnectTimeout:    20 * time.Second,
+		KVDurableTimeout:  20 * time.Second,
+		KVScanTimeout:     20 * time.Second,
+		ViewTimeout:       120 * time.Second,
+		AnalyticsTimeout:  120 * time.Second,
+		SearchTimeout:     120 * time.Second,
+		ManagementTimeout: 120 * time.Second,
+		QueryTimeout:      120 * time.Second,
+	}
+	opts.RetryStrategy = NewBestEffortRetryStrategy(
+		BackoffCalculator(gocbcore.ExponentialBackoff(5*time.Millisecond, 1*time.Second, 2)),
+	)
+	opts.CircuitBreakerConfig = CircuitBreakerConfig{
+		VolumeThreshold:          20,
+		ErrorThresholdPercentage: 50,
+		SleepWindow:              5 * time.Second,
+		RollingWindow:            time.Minute,
+		CanaryTimeout:            10 * time.Second,
+	}
+	opts.CompressionConfig = CompressionConfig{
+		MinSize:  32,
+		MinRatio: 0.83,
+	}
+	opts.OrphanReporterConfig = OrphanReporterConfig{
+		ReportInterval: 10 * time.Second,
+		SampleSize:     10,
+	}
+}
+
+// applyConnStrProfile applies a profile named in the connection string. Unlike ApplyProfile, options explicitly set
+// on opts take precedence over the profile, so the profile is applied to empty options first and the options from
+// opts are then merged on top of it by mergeProfiledOptions.
+func applyConnStrProfile(profile ClusterConfigProfile, opts ClusterOptions) (ClusterOptions, error) {
+	var profiled ClusterOptions
+	err := profiled.ApplyProfile(profile)
+	if err != nil {
+		return ClusterOptions{}, err
+	}
+
+	mergeProfiledOptions(&profiled, opts)
+	return profiled, nil
+}
+
+// mergeProfiledOptions merges the options set on opts into the profiled options.
+// The timeouts, orphan reporter and circuit breaker options are merged per field as a zero value for any of these
+// means "use the default", so setting only KVTimeout keeps the other timeouts from the profile. Note that this means
+// that a Disabled field set to false cannot re-enable something that the profile disables.
+// All other config structs are replaced as a whole when any of their fields are set. This is what allows
+// compression to be turned back on, as setting any field of CompressionConfig replaces the profile's CompressionConfig,
+// Disabled included.
+func mergeProfiledOptions(profiled *ClusterOptions, opts ClusterOptions) {
+	if opts.Authenticator != nil {
+		profiled.Authenticator = opts.Authenticator
+	}
+	if opts.Username != "" {
+		profiled.Username = opts.Username
+	}
+	if opts.Password != "" {
+		profiled.Password = opts.Password
+	}
+	if opts.Transcoder != nil {
+		profiled.Transcoder = opts.Transcoder
+	}
+	if opts.RetryStrategy != nil {
+		profiled.RetryStrategy = opts.RetryStrategy
+	}
+	if opts.Tracer != nil {
+		profiled.Tracer = opts.Tracer
+	}
+	if opts.Meter != nil {
+		profiled.Meter = opts.Meter
+	}
+	if opts.Interceptors != nil {
+		profiled.Interceptors = opts.Interceptors
+	}
+	if opts.CollectionInterceptors != nil {
+		profiled.CollectionInterceptors = opts.CollectionInterceptors
+	}
+
+	mergeTimeouts(&profiled.TimeoutsConfig, opts.TimeoutsConfig)
+	mergeOrphanReporterConfig(&profiled.OrphanReporterConfig, opts.OrphanReporterConfig)
+	mergeCircuitBreakerConfig(&profiled.CircuitBreakerConfig, opts.CircuitBreakerConfig)
+
+	if !reflect.ValueOf(opts.ServiceCircuitBreakerConfig).IsZero() {
+		profiled.ServiceCircuitBreakerConfig = opts.ServiceCircuitBreakerConfig
+	}
+	if !reflect.ValueOf(opts.AdmissionConfig).IsZero() {
+		profiled.AdmissionConfig = opts.AdmissionConfig
+	}
+	if !reflect.ValueOf(opts.IoConfig).IsZero() {
+		profiled.IoConfig = opts.IoConfig
+	}
+	if !reflect.ValueOf(opts.SecurityConfig).IsZero() {
+		profiled.SecurityConfig = opts.SecurityConfig
+	}
+	if !reflect.ValueOf(opts.TransactionsConfig).IsZero() {
+		profiled.TransactionsConfig = opts.TransactionsConfig
+	}
+	if opts.CompressionConfig != (CompressionConfig{}) {
+		profiled.CompressionConfig = opts.CompressionConfig
+	}
+	if !reflect.ValueOf(opts.ProtostellarConfig).IsZero() {
+		profiled.ProtostellarConfig = opts.ProtostellarConfig
+	}
+	if !reflect.ValueOf(opts.InternalConfig).IsZero() {
+		profiled.InternalConfig = opts.InternalConfig
+	}
+}
+
+func mergeTimeouts(profiled *TimeoutsConfig, opts TimeoutsConfig) {
+	mergeDuration(&profiled.ConnectTimeout, opts.ConnectTimeout)
+	mergeDuration(&profiled.KVTimeout, opts.KVTimeout)
+	mergeDuration(&profiled.KVDurableTimeout, opts.KVDurableTimeout)
+	mergeDuration(&profiled.KVScanTimeout, opts.KVScanTimeout)
+	mergeDuration(&profiled.ViewTimeout, opts.ViewTimeout)
+	mergeDuration(&profiled.QueryTimeout, opts.QueryTimeout)
+	mergeDuration(&profiled.AnalyticsTimeout, opts.AnalyticsTimeout)
+	mergeDuration(&profiled.SearchTimeout, opts.SearchTimeout)
+	mergeDuration(&profiled.ManagementTimeout, opts.ManagementTimeout)
+}
+
+func mergeOrphanReporterConfig(profiled *OrphanReporterConfig, opts OrphanReporterConfig) {
+	if opts.Disabled {
+		profiled.Disabled = true
+	}
+	mergeDuration(&profiled.ReportInterval, opts.ReportInterval)
+	if opts.SampleSize > 0 {
+		profiled.SampleSize = opts.SampleSize
+	}
+}
+
+func mergeCircuitBreakerConfig(profiled *CircuitBreakerConfig, opts CircuitBreakerConfig) {
+	if opts.Disabled {
+		profiled.Disabled = true
+	}
+	if opts.VolumeThreshold > 0 {
+		profiled.VolumeThreshold = opts.VolumeThreshold
+	}
+	if opts.ErrorThresholdPercentage > 0 {
+		profiled.ErrorThresholdPercentage = opts.ErrorThresholdPercentage
+	}
+	mergeDuration(&profiled.SleepWindow, opts.SleepWindow)
+	mergeDuration(&profiled.RollingWindow, opts.RollingWindow)
+	if opts.CompletionCallback != nil {
+		profiled.CompletionCallback = opts.CompletionCallback
+	}
+	mergeDuration(&profiled.CanaryTimeout, opts.CanaryTimeout)
+}
+
+func mergeDuration(profiled *time.Duration, opts time.Duration) {
+	if opts > 0 {
+		*profiled = opts
+	}
 }
@@ -1,6 +1,10 @@
 package gocb
 
-import "time"
+import (
+	"reflect"
+	"strings"
+	"time"
+)
 
 var defaultConfig = ClusterOptions{
 	TimeoutsConfig: TimeoutsConfig{
@@ -53,3 +57,240 @@ func (suite *UnitTestSuite) TestUnknownConfigProfile() {
 	err := options.ApplyProfile("unknown")
 	suite.Require().ErrorIs(err, ErrInvalidArgument)
 }
+
+func (suite *UnitTestSuite) TestBuiltInConfigProfiles() {
+	profiles := []ClusterConfigProfile{
+		ClusterConfigProfileLowLatency,
+		ClusterConfigProfileBatch,
+		ClusterConfigProfileCloud,
+	}
+
+	for _, profile := range profiles {
+		suite.Run(string(profile), func() {
+			options := defaultConfig
+			err := options.ApplyProfile(profile)
+			suite.Require().Nil(err)
+
+			suite.Assert().NotEqual(defaultConfig.TimeoutsConfig, options.TimeoutsConfig)
+			suite.Assert().NotZero(options.CircuitBreakerConfig.VolumeThreshold)
+			suite.Assert().NotZero(options.OrphanReporterConfig.ReportInterval)
+			suite.Assert().IsType(&BestEffortRetryStrategy{}, options.RetryStrategy)
+		})
+	}
+}
+
+func (suite *UnitTestSuite) TestRegisterConfigProfile() {
+	var profile ClusterConfigProfile = "unit-test-profile"
+	err := RegisterClusterConfigProfile(profile, func(opts *ClusterOptions) {
+		opts.TimeoutsConfig.KVTimeout = 123 * time.Millisecond
+		opts.CompressionConfig.Disabled = true
+	})
+	suite.Require().Nil(err)
+
+	options := defaultConfig
+	err = options.ApplyProfile(profile)
+	suite.Require().Nil(err)
+
+	suite.Assert().Equal(123*time.Millisecond, options.TimeoutsConfig.KVTimeout)
+	suite.Assert().Equal(defaultConfig.TimeoutsConfig.QueryTimeout, options.TimeoutsConfig.QueryTimeout)
+	suite.Assert().True(options.CompressionConfig.Disabled)
+}
+
+func (suite *UnitTestSuite) TestRegisterConfigProfileInvalid() {
+	err := RegisterClusterConfigProfile("", func(opts *ClusterOptions) {})
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	err = RegisterClusterConfigProfile("unit-test-nil-profile", nil)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestConfigProfileConnStr() {
+	c, err := Connect("couchbase://10.10.10.10?config_profile=batch&query_timeout=1000", ClusterOptions{
+		Username: "Administrator",
+		Password: "password",
+	})
+	suite.Require().Nil(err, err)
+	defer c.Close(nil)
+
+	suite.Assert().Equal(10*time.Second, c.timeoutsConfig.KVTimeout)
+	suite.Assert().Equal(30*time.Minute, c.timeoutsConfig.AnalyticsTimeout)
+	// Explicit connection string options take precedence over the profile.
+	suite.Assert().Equal(1*time.Second, c.timeoutsConfig.QueryTimeout)
+	suite.Assert().Equal(int64(100), c.circuitBreakerConfig.VolumeThreshold)
+}
+
+func (suite *UnitTestSuite) TestUnknownConfigProfileConnStr() {
+	_, err := Connect("couchbase://10.10.10.10?config_profile=unknown", ClusterOptions{
+		Username: "Administrator",
+		Password: "password",
+	})
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestConfigProfileConnStrExplicitOptions() {
+	strategy := NewFailFastRetryStrategy()
+	c, err := Connect("couchbase://10.10.10.10?config_profile=batch", ClusterOptions{
+		Username: "Administrator",
+		Password: "password",
+		TimeoutsConfig: TimeoutsConfig{
+			KVTimeout: 1 * time.Second,
+		},
+		CircuitBreakerConfig: CircuitBreakerConfig{
+			VolumeThreshold: 5,
+		},
+		RetryStrategy: strategy,
+	})
+	suite.Require().Nil(err, err)
+	defer c.Close(nil)
+
+	// Options set explicitly take precedence over the profile, the profile fills in the rest.
+	suite.Assert().Equal(1*time.Second, c.timeoutsConfig.KVTimeout)
+	suite.Assert().Equal(30*time.Minute, c.timeoutsConfig.AnalyticsTimeout)
+	suite.Assert().Equal(int64(5), c.circuitBreakerConfig.VolumeThreshold)
+	suite.Assert().Equal(float64(75), c.circuitBreakerConfig.ErrorThresholdPercentage)
+	suite.Assert().Equal(strategy, c.retryStrategyWrapper.wrapped)
+}
+
+func (suite *UnitTestSuite) TestConfigProfileConnStrCompressionOverride() {
+	c, err := Connect("couchbase://10.10.10.10?config_profile=low-latency", ClusterOptions{
+		Username: "Administrator",
+		Password: "password",
+	})
+	suite.Require().Nil(err, err)
+	defer c.Close(nil)
+
+	suite.Assert().True(c.compressionConfig.Disabled)
+
+	// Setting any compression option replaces the profile's compression config, turning compression back on.
+	c, err = Connect("couchbase://10.10.10.10?config_profile=low-latency", ClusterOptions{
+		Username: "Administrator",
+		Password: "password",
+		CompressionConfig: CompressionConfig{
+			MinSize: 64,
+		},
+	})
+	suite.Require().Nil(err, err)
+	defer c.Close(nil)
+
+	suite.Assert().False(c.compressionConfig.Disabled)
+	suite.Assert().Equal(uint32(64), c.compressionConfig.MinSize)
+	suite.Assert().Equal(500*time.Millisecond, c.timeoutsConfig.KVTimeout)
+}
+
+func (suite *UnitTestSuite) TestConfigProfileApplyThenOverrideCompression() {
+	options := ClusterOptions{}
+	err := options.ApplyProfile(ClusterConfigProfileLowLatency)
+	suite.Require().Nil(err, err)
+
+	options.CompressionConfig.Disabled = false
+
+	suite.Assert().False(options.CompressionConfig.Disabled)
+	suite.Assert().Equal(500*time.Millisecond, options.TimeoutsConfig.KVTimeout)
+}
+
+// TestConfigProfileMergesAllOptions fails when ClusterOptions gains an option which mergeProfiledOptions doesn't take
+// from the options passed to Connect, which would otherwise be silently dropped when config_profile is used. Each
+// option is set on its own, so options within structs which are merged field by field are checked individually.
+func (suite *UnitTestSuite) TestConfigProfileMergesAllOptions() {
+	interfaces := map[reflect.Type]interface{}{
+		reflect.TypeOf((*Authenticator)(nil)).Elem(): PasswordAuthenticator{Username: "u"},
+		reflect.TypeOf((*Transcoder)(nil)).Elem():    NewJSONTranscoder(),
+		reflect.TypeOf((*RetryStrategy)(nil)).Elem(): NewBestEffortRetryStrategy(nil),
+		reflect.TypeOf((*RequestTracer)(nil)).Elem(): &NoopTracer{},
+		reflect.TypeOf((*Meter)(nil)).Elem():         &NoopMeter{},
+		reflect.TypeOf((*TransactionHooks)(nil)).Elem(): struct {
+			TransactionHooks
+		}{},
+		reflect.TypeOf((*TransactionCleanupHooks)(nil)).Elem(): struct {
+			TransactionCleanupHooks
+		}{},
+		reflect.TypeOf((*TransactionClientRecordHooks)(nil)).Elem(): struct {
+			TransactionClientRecordHooks
+		}{},
+	}
+	pkgPath := reflect.TypeOf(ClusterOptions{}).PkgPath()
+
+	var setNonZero func(v reflect.Value) bool
+	setNonZero = func(v reflect.Value) bool {
+		switch v.Kind() {
+		case reflect.Bool:
+			v.SetBool(true)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(1)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v.SetUint(1)
+		case reflect.Float32, reflect.Float64:
+			v.SetFloat(1)
+		case reflect.String:
+			v.SetString("x")
+		case reflect.Ptr:
+			v.Set(reflect.New(v.Type().Elem()))
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		case reflect.Map:
+			v.Set(reflect.MakeMap(v.Type()))
+		case reflect.Chan:
+			v.Set(reflect.MakeChan(v.Type(), 0))
+		case reflect.Func:
+			v.Set(reflect.MakeFunc(v.Type(), func(args []reflect.Value) []reflect.Value {
+				results := make([]reflect.Value, v.Type().NumOut())
+				for i := range results {
+					results[i] = reflect.Zero(v.Type().Out(i))
+				}
+				return results
+			}))
+		case reflect.Interface:
+			impl, ok := interfaces[v.Type()]
+			if !ok {
+				return false
+			}
+			v.Set(reflect.ValueOf(impl))
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).IsExported() && setNonZero(v.Field(i)) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+
+		return true
+	}
+
+	var checked int
+	var walk func(index []int, names []string, typ reflect.Type)
+	walk = func(index []int, names []string, typ reflect.Type) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldIndex := append(append([]int{}, index...), i)
+			fieldNames := append(append([]string{}, names...), field.Name)
+			name := strings.Join(fieldNames, ".")
+			if field.Type.Kind() == reflect.Struct && (field.Type.PkgPath() == pkgPath || field.Type.Name() == "") {
+				walk(fieldIndex, fieldNames, field.Type)
+				continue
+			}
+
+			var opts ClusterOptions
+			if !setNonZero(reflect.ValueOf(&opts).Elem().FieldByIndex(fieldIndex)) {
+				suite.Failf("Unable to set option", "%s has an unsupported type %s, add a value for it", name,
+					field.Type)
+				continue
+			}
+
+			var profiled ClusterOptions
+			mergeProfiledOptions(&profiled, opts)
+			suite.Assert().False(reflect.ValueOf(profiled).FieldByIndex(fieldIndex).IsZero(),
+				"%s is not merged over the profile", name)
+			checked++
+		}
+	}
+	walk(nil, nil, reflect.TypeOf(ClusterOptions{}))
+
+	suite.Assert().Greater(checked, reflect.TypeOf(ClusterOptions{}).NumField())
+}
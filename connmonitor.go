@@ -0,0 +1,58 @@
+package gocb
+
+import (
+	"sync"
+	"time"
+)
+
+const connectionMonitorInterval = time.Second
+
+// connectionMonitor periodically polls the state of the connections held by a connection manager. Neither gocbcore
+// nor gocbcoreps notify us when a connection is reestablished so polling is the only way to observe it.
+type connectionMonitor struct {
+	interval time.Duration
+	poll     func()
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newConnectionMonitor(interval time.Duration, poll func()) *connectionMonitor {
+	return &connectionMonitor{
+		interval: interval,
+		poll:     poll,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+func (m *connectionMonitor) start() {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				m.poll()
+			}
+		}
+	}()
+}
+
+// stop stops the monitor and waits for any poll in progress to complete, it is safe to call more than once.
+func (m *connectionMonitor) stop() {
+	if m == nil {
+		return
+	}
+
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+	m.wg.Wait()
+}
@@ -0,0 +1,213 @@
+package gocb
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/couchbase/gocbcore/v10"
+)
+
+type coreDiagnosticsProvider interface {
+	Diagnostics(opts gocbcore.DiagnosticsOptions) (*gocbcore.DiagnosticInfo, error)
+}
+
+type kvConnectionState struct {
+	localAddr  string
+	remoteAddr string
+	bucket     string
+	connected  bool
+}
+
+// kvConnectionTracker watches the KV connections held by gocbcore, counting reconnects and raising topology events.
+// gocbcore keeps the same pipeline client across reconnects, so a reconnect is seen as a connected client whose
+// local address has changed.
+// The diagnostics of all buckets together only report the highest config revision of any bucket, so the revision of
+// each bucket is read from the diagnostics of that bucket.
+type kvConnectionTracker struct {
+	provider       coreDiagnosticsProvider
+	bucketProvider func(bucket string) coreDiagnosticsProvider
+	meter          *meterWrapper
+	events         *eventBus
+
+	// configRevs maps the name of each bucket to the config revision last seen for it.
+	configRevs map[string]int64
+	// conns maps the ID of each client to its state when it was last seen.
+	conns map[string]kvConnectionState
+}
+
+func newKvConnectionTracker(provider coreDiagnosticsProvider, bucketProvider func(bucket string) coreDiagnosticsProvider,
+	meter *meterWrapper, events *eventBus) *kvConnectionTracker {
+	return &kvConnectionTracker{
+		provider:       provider,
+		bucketProvider: bucketProvider,
+		meter:          meter,
+		events:         events,
+		configRevs:     make(map[string]int64),
+		conns:          make(map[string]kvConnectionState),
+	}
+}
+
+func (t *kvConnectionTracker) poll() {
+	countReconnects := t.meter.countsReconnects()
+	if !countReconnects && !t.events.hasSubscribers() {
+		// Nothing is interested, forget what we've seen so that new subscribers don't receive stale events.
+		t.configRevs = make(map[string]int64)
+		t.conns = make(map[string]kvConnectionState)
+		return
+	}
+
+	diag, err := t.provider.Diagnostics(gocbcore.DiagnosticsOptions{})
+	if err != nil {
+		// This is expected until a bucket has been opened.
+		return
+	}
+
+	var events []Event
+	seen := make(map[string]struct{}, len(diag.MemdConns))
+	reconnects := make(map[string]uint64)
+	for _, conn := range diag.MemdConns {
+		// Clients which are currently disconnected are kept track of so that we can see when they reconnect.
+		seen[conn.ID] = struct{}{}
+		prev, tracked := t.conns[conn.ID]
+		bucket := unredactMetaData(conn.Scope)
+
+		if conn.State != gocbcore.EndpointStateConnected {
+			if prev.connected {
+				events = append(events, NodeDisconnected{Node: prev.remoteAddr, Bucket: prev.bucket})
+				prev.connected = false
+				t.conns[conn.ID] = prev
+			}
+			continue
+		}
+
+		if tracked && prev.localAddr != conn.LocalAddr {
+			reconnects[bucket]++
+			if prev.connected {
+				// The disconnect happened between polls.
+				events = append(events, NodeDisconnected{Node: prev.remoteAddr, Bucket: prev.bucket})
+			}
+		}
+		if !prev.connected || prev.localAddr != conn.LocalAddr {
+			events = append(events, NodeConnected{Node: conn.RemoteAddr, Bucket: bucket})
+		}
+
+		t.conns[conn.ID] = kvConnectionState{
+			localAddr:  conn.LocalAddr,
+			remoteAddr: conn.RemoteAddr,
+			bucket:     bucket,
+			connected:  true,
+		}
+	}
+
+	for id, prev := range t.conns {
+		if _, ok := seen[id]; !ok {
+			if prev.connected {
+				events = append(events, NodeDisconnected{Node: prev.remoteAddr, Bucket: prev.bucket})
+			}
+			delete(t.conns, id)
+		}
+	}
+
+	events = append(t.configChanges(diag.MemdConns), events...)
+
+	if countReconnects {
+		for bucket, count := range reconnects {
+			logDebugfWithFields([]LogField{
+				{Key: LogFieldComponent, Value: "kv"},
+				{Key: LogFieldBucket, Value: bucket},
+			}, "Observed %d KV reconnects for bucket %s", count, bucket)
+			t.meter.IncrementReconnects(meterValueServiceKV, bucket, count)
+		}
+	}
+
+	for _, event := range events {
+		switch e := event.(type) {
+		case NodeConnected:
+			logDebugfWithFields(kvConnectionLogFields(e.Node, e.Bucket), "Observed KV connection to %s",
+				redactSystemData(e.Node))
+		case NodeDisconnected:
+			logDebugfWithFields(kvConnectionLogFields(e.Node, e.Bucket), "Observed KV disconnection from %s",
+				redactSystemData(e.Node))
+		}
+		t.events.publish(event)
+	}
+}
+
+// configChanges returns a ConfigChanged event for each bucket whose config revision has changed since the last poll.
+func (t *kvConnectionTracker) configChanges(conns []gocbcore.MemdConnInfo) []Event {
+	buckets := make(map[string]struct{})
+	for _, conn := range conns {
+		buckets[unredactMetaData(conn.Scope)] = struct{}{}
+	}
+	for bucket := range t.configRevs {
+		if _, ok := buckets[bucket]; !ok {
+			delete(t.configRevs, bucket)
+		}
+	}
+	if t.bucketProvider == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(buckets))
+	for bucket := range buckets {
+		names = append(names, bucket)
+	}
+	sort.Strings(names)
+
+	var events []Event
+	for _, bucket := range names {
+		provider := t.bucketProvider(bucket)
+		if provider == nil {
+			continue
+		}
+
+		diag, err := provider.Diagnostics(gocbcore.DiagnosticsOptions{})
+		if err != nil || diag.ConfigRev <= 0 || diag.ConfigRev == t.configRevs[bucket] {
+			continue
+		}
+
+		t.configRevs[bucket] = diag.ConfigRev
+		logDebugfWithFields([]LogField{
+			{Key: LogFieldComponent, Value: "kv"},
+			{Key: LogFieldBucket, Value: bucket},
+		}, "Observed config revision %d for bucket %s", diag.ConfigRev, bucket)
+		events = append(events, ConfigChanged{Bucket: bucket, Rev: diag.ConfigRev, Nodes: kvNodes(diag.MemdConns)})
+	}
+
+	return events
+}
+
+func kvConnectionLogFields(endpoint, bucket string) []LogField {
+	return []LogField{
+		{Key: LogFieldComponent, Value: "kv"},
+		{Key: LogFieldEndpoint, Value: redactSystemData(endpoint)},
+		{Key: LogFieldBucket, Value: bucket},
+	}
+}
+
+func kvNodes(conns []gocbcore.MemdConnInfo) []string {
+	seen := make(map[string]struct{}, len(conns))
+	var nodes []string
+	for _, conn := range conns {
+		if conn.RemoteAddr == "" {
+			continue
+		}
+		if _, ok := seen[conn.RemoteAddr]; ok {
+			continue
+		}
+		seen[conn.RemoteAddr] = struct{}{}
+		nodes = append(nodes, conn.RemoteAddr)
+	}
+	sort.Strings(nodes)
+
+	return nodes
+}
+
+// unredactMetaData removes the metadata tags which gocbcore always wraps bucket names in within diagnostics reports.
+func unredactMetaData(v string) string {
+	if strings.HasPrefix(v, "<md>") && strings.HasSuffix(v, "</md>") {
+		return v[len("<md>") : len(v)-len("</md>")]
+	}
+
+	return v
+}
@@ -0,0 +1,95 @@
+package gocb
+
+import (
+	"context"
+	"time"
+
+	"github.com/couchbase/gocbcoreps"
+	"github.com/couchbase/goprotostellar/genproto/kv_v1"
+)
+
+type psConnectionStateProvider interface {
+	ConnectionState() gocbcoreps.ConnState
+}
+
+// psReconnectTracker detects the connection to a protostellar endpoint being lost and coming back online.
+type psReconnectTracker struct {
+	provider psConnectionStateProvider
+	meter    *meterWrapper
+	events   *eventBus
+	host     string
+
+	wasOnline bool
+	lost      bool
+}
+
+func newPsReconnectTracker(provider psConnectionStateProvider, meter *meterWrapper, events *eventBus,
+	host string) *psReconnectTracker {
+	return &psReconnectTracker{
+		provider: provider,
+		meter:    meter,
+		events:   events,
+		host:     host,
+	}
+}
+
+func (t *psReconnectTracker) poll() {
+	state := t.provider.ConnectionState()
+	if state != gocbcoreps.ConnStateOnline {
+		if t.wasOnline && !t.lost {
+			t.lost = true
+			t.events.publish(NodeDisconnected{Node: t.host})
+		}
+		return
+	}
+
+	if t.lost {
+		logDebugf("Observed protostellar reconnect")
+		if t.meter.countsReconnects() {
+			t.meter.IncrementReconnects("", "", 1)
+		}
+	}
+	if t.lost || !t.wasOnline {
+		t.events.publish(NodeConnected{Node: t.host})
+	}
+	t.lost = false
+	t.wasOnline = true
+}
+
+const psKeepAliveDefaultTimeout = 10 * time.Second
+
+// psKeepAlive periodically sends a lightweight request over every connection in the pool so that load balancers and
+// proxies between us and the cluster do not consider them idle. The routing client dials its connections itself so we
+// are unable to configure grpc keepalive pings on them, any response from the cluster is enough to keep the
+// connection active. The request is a key-value exists without a bucket, which the cluster rejects without needing
+// any permissions or touching the data service.
+type psKeepAlive struct {
+	client   kv_v1.KvServiceClient
+	poolSize uint32
+	timeout  time.Duration
+}
+
+func newPsKeepAlive(client kv_v1.KvServiceClient, poolSize uint32, timeout time.Duration) *psKeepAlive {
+	if poolSize == 0 {
+		poolSize = 1
+	}
+
+	return &psKeepAlive{
+		client:   client,
+		poolSize: poolSize,
+		timeout:  timeout,
+	}
+}
+
+func (k *psKeepAlive) poll() {
+	// Requests which are not routed to a specific node are sent round robin across the pool, so sending one request
+	// per connection reaches each of them.
+	for i := uint32(0); i < k.poolSize; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), k.timeout)
+		_, err := k.client.Exists(ctx, &kv_v1.ExistsRequest{Key: "gocb-keepalive"})
+		cancel()
+		if err != nil && !psPingResponded(err) {
+			logDebugf("Protostellar keepalive request failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package gocb
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/couchbase/gocbcoreps"
+)
+
+type mockCoreDiagnosticsProvider struct {
+	info *gocbcore.DiagnosticInfo
+	err  error
+}
+
+func (p *mockCoreDiagnosticsProvider) Diagnostics(_ gocbcore.DiagnosticsOptions) (*gocbcore.DiagnosticInfo, error) {
+	return p.info, p.err
+}
+
+func (p *mockCoreDiagnosticsProvider) forBucket(_ string) coreDiagnosticsProvider {
+	return p
+}
+
+type mockPsConnectionStateProvider struct {
+	state gocbcoreps.ConnState
+}
+
+func (p *mockPsConnectionStateProvider) ConnectionState() gocbcoreps.ConnState {
+	return p.state
+}
+
+func (suite *UnitTestSuite) reconnectCount(meter *LoggingMeter) uint64 {
+	var count uint64
+	for _, counter := range meter.counterGroup.Counters() {
+		if counter.name == meterNameCBReconnects {
+			count += counter.Value()
+		}
+	}
+
+	return count
+}
+
+func (suite *UnitTestSuite) TestKvConnectionTrackerReconnects() {
+	meter := newAggregatingMeter(nil)
+	provider := &mockCoreDiagnosticsProvider{err: ErrNoResult}
+	tracker := newKvConnectionTracker(provider, nil, newMeterWrapper(meter), nil)
+
+	// No buckets have been opened yet.
+	tracker.poll()
+
+	conn := func(id, localAddr string, state gocbcore.EndpointState) gocbcore.MemdConnInfo {
+		return gocbcore.MemdConnInfo{ID: id, LocalAddr: localAddr, RemoteAddr: "10.0.0.1:11210", Scope: "<md>default</md>",
+			State: state}
+	}
+
+	provider.err = nil
+	provider.info = &gocbcore.DiagnosticInfo{MemdConns: []gocbcore.MemdConnInfo{
+		conn("a", "10.0.0.2:50000", gocbcore.EndpointStateConnected),
+		conn("b", "10.0.0.2:50001", gocbcore.EndpointStateConnected),
+	}}
+	tracker.poll()
+	tracker.poll()
+	suite.Assert().Zero(suite.reconnectCount(meter))
+
+	// A dropped connection should be counted once it has connected again, on a new local port.
+	provider.info.MemdConns[0] = conn("a", "", gocbcore.EndpointStateDisconnected)
+	tracker.poll()
+	suite.Assert().Zero(suite.reconnectCount(meter))
+
+	provider.info.MemdConns[0] = conn("a", "10.0.0.2:50002", gocbcore.EndpointStateConnected)
+	provider.info.MemdConns[1] = conn("b", "10.0.0.2:50003", gocbcore.EndpointStateConnected)
+	tracker.poll()
+	suite.Assert().Equal(uint64(2), suite.reconnectCount(meter))
+}
+
+func (suite *UnitTestSuite) TestPsReconnectTracker() {
+	meter := newAggregatingMeter(nil)
+	provider := &mockPsConnectionStateProvider{state: gocbcoreps.ConnStateOffline}
+	tracker := newPsReconnectTracker(provider, newMeterWrapper(meter), nil, "localhost:18098")
+
+	// Connecting for the first time isn't a reconnect.
+	tracker.poll()
+	provider.state = gocbcoreps.ConnStateOnline
+	tracker.poll()
+	suite.Assert().Zero(suite.reconnectCount(meter))
+
+	provider.state = gocbcoreps.ConnStateDegraded
+	tracker.poll()
+	provider.state = gocbcoreps.ConnStateOnline
+	tracker.poll()
+	tracker.poll()
+	suite.Assert().Equal(uint64(1), suite.reconnectCount(meter))
+}
+
+func (suite *UnitTestSuite) TestKvConnectionTrackerReconnectBucket() {
+	meter := newAggregatingMeter(nil)
+	provider := &mockCoreDiagnosticsProvider{info: &gocbcore.DiagnosticInfo{MemdConns: []gocbcore.MemdConnInfo{
+		{ID: "a", LocalAddr: "10.0.0.2:50000", Scope: "<md>travel</md>", State: gocbcore.EndpointStateConnected},
+	}}}
+	tracker := newKvConnectionTracker(provider, nil, newMeterWrapper(meter), nil)
+	tracker.poll()
+
+	provider.info.MemdConns[0].LocalAddr = "10.0.0.2:50001"
+	tracker.poll()
+
+	var buckets []string
+	for _, counter := range meter.counterGroup.Counters() {
+		if counter.name == meterNameCBReconnects {
+			buckets = append(buckets, counter.tags[meterAttribBucketNameKey])
+		}
+	}
+	suite.Assert().Equal([]string{"travel"}, buckets)
+}
+
+func (suite *UnitTestSuite) TestKvConnectionTrackerEvents() {
+	bus := newEventBus()
+	defer bus.close()
+	provider := &mockCoreDiagnosticsProvider{}
+	tracker := newKvConnectionTracker(provider, provider.forBucket, newMeterWrapper(&NoopMeter{}), bus)
+
+	conn := func(id, localAddr, remoteAddr string, state gocbcore.EndpointState) gocbcore.MemdConnInfo {
+		return gocbcore.MemdConnInfo{ID: id, LocalAddr: localAddr, RemoteAddr: remoteAddr, Scope: "<md>default</md>",
+			State: state}
+	}
+	provider.info = &gocbcore.DiagnosticInfo{ConfigRev: 10, MemdConns: []gocbcore.MemdConnInfo{
+		conn("a", "10.0.0.9:50000", "10.0.0.2:11210", gocbcore.EndpointStateConnected),
+		conn("b", "10.0.0.9:50001", "10.0.0.1:11210", gocbcore.EndpointStateConnected),
+	}}
+
+	// Without any subscribers there's nothing to do.
+	tracker.poll()
+	suite.Assert().Empty(tracker.conns)
+
+	handler := newTestEventHandler()
+	unsubscribe := bus.subscribe(handler.handle)
+
+	tracker.poll()
+	suite.Assert().Equal([]Event{
+		ConfigChanged{Bucket: "default", Rev: 10, Nodes: []string{"10.0.0.1:11210", "10.0.0.2:11210"}},
+		NodeConnected{Node: "10.0.0.2:11210", Bucket: "default"},
+		NodeConnected{Node: "10.0.0.1:11210", Bucket: "default"},
+	}, handler.take(suite, 3))
+
+	tracker.poll()
+	handler.assertNone(suite)
+
+	provider.info.MemdConns[0] = conn("a", "", "10.0.0.2:11210", gocbcore.EndpointStateDisconnected)
+	tracker.poll()
+	suite.Assert().Equal([]Event{NodeDisconnected{Node: "10.0.0.2:11210", Bucket: "default"}}, handler.take(suite, 1))
+
+	provider.info.MemdConns[0] = conn("a", "10.0.0.9:50002", "10.0.0.2:11210", gocbcore.EndpointStateConnected)
+	tracker.poll()
+	suite.Assert().Equal([]Event{NodeConnected{Node: "10.0.0.2:11210", Bucket: "default"}}, handler.take(suite, 1))
+
+	// A reconnect which happened entirely between polls, alongside a node being removed by a rebalance.
+	provider.info = &gocbcore.DiagnosticInfo{ConfigRev: 12, MemdConns: []gocbcore.MemdConnInfo{
+		conn("a", "10.0.0.9:50003", "10.0.0.2:11210", gocbcore.EndpointStateConnected),
+	}}
+	tracker.poll()
+	suite.Assert().Equal([]Event{
+		ConfigChanged{Bucket: "default", Rev: 12, Nodes: []string{"10.0.0.2:11210"}},
+		NodeDisconnected{Node: "10.0.0.2:11210", Bucket: "default"},
+		NodeConnected{Node: "10.0.0.2:11210", Bucket: "default"},
+		NodeDisconnected{Node: "10.0.0.1:11210", Bucket: "default"},
+	}, handler.take(suite, 4))
+
+	unsubscribe()
+	provider.info.MemdConns = nil
+	tracker.poll()
+	handler.assertNone(suite)
+}
+
+func (suite *UnitTestSuite) TestKvConnectionTrackerConfigChangedPerBucket() {
+	bus := newEventBus()
+	defer bus.close()
+	handler := newTestEventHandler()
+	bus.subscribe(handler.handle)
+
+	conn := func(id, remoteAddr, bucket string) gocbcore.MemdConnInfo {
+		return gocbcore.MemdConnInfo{ID: id, LocalAddr: "10.0.0.9:5000" + id, RemoteAddr: remoteAddr,
+			Scope: "<md>" + bucket + "</md>", State: gocbcore.EndpointStateConnected}
+	}
+	buckets := map[string]*mockCoreDiagnosticsProvider{
+		"default": {info: &gocbcore.DiagnosticInfo{ConfigRev: 20, MemdConns: []gocbcore.MemdConnInfo{
+			conn("1", "10.0.0.1:11210", "default"),
+		}}},
+		"travel": {info: &gocbcore.DiagnosticInfo{ConfigRev: 5, MemdConns: []gocbcore.MemdConnInfo{
+			conn("2", "10.0.0.2:11210", "travel"),
+		}}},
+	}
+	provider := &mockCoreDiagnosticsProvider{info: &gocbcore.DiagnosticInfo{ConfigRev: 20, MemdConns: []gocbcore.MemdConnInfo{
+		conn("1", "10.0.0.1:11210", "default"),
+		conn("2", "10.0.0.2:11210", "travel"),
+	}}}
+	tracker := newKvConnectionTracker(provider, func(bucket string) coreDiagnosticsProvider {
+		return buckets[bucket]
+	}, newMeterWrapper(&NoopMeter{}), bus)
+
+	tracker.poll()
+	suite.Assert().Equal([]Event{
+		ConfigChanged{Bucket: "default", Rev: 20, Nodes: []string{"10.0.0.1:11210"}},
+		ConfigChanged{Bucket: "travel", Rev: 5, Nodes: []string{"10.0.0.2:11210"}},
+		NodeConnected{Node: "10.0.0.1:11210", Bucket: "default"},
+		NodeConnected{Node: "10.0.0.2:11210", Bucket: "travel"},
+	}, handler.take(suite, 4))
+
+	// The highest revision across the buckets hasn't changed, but the config of the travel bucket has.
+	buckets["travel"].info.ConfigRev = 6
+	tracker.poll()
+	suite.Assert().Equal([]Event{
+		ConfigChanged{Bucket: "travel", Rev: 6, Nodes: []string{"10.0.0.2:11210"}},
+	}, handler.take(suite, 1))
+	handler.assertNone(suite)
+}
+
+func (suite *UnitTestSuite) TestPsReconnectTrackerEvents() {
+	bus := newEventBus()
+	defer bus.close()
+	handler := newTestEventHandler()
+	bus.subscribe(handler.handle)
+
+	provider := &mockPsConnectionStateProvider{state: gocbcoreps.ConnStateOffline}
+	tracker := newPsReconnectTracker(provider, nil, bus, "localhost:18098")
+
+	tracker.poll()
+	handler.assertNone(suite)
+
+	provider.state = gocbcoreps.ConnStateOnline
+	tracker.poll()
+	tracker.poll()
+	provider.state = gocbcoreps.ConnStateDegraded
+	tracker.poll()
+	tracker.poll()
+	provider.state = gocbcoreps.ConnStateOnline
+	tracker.poll()
+
+	suite.Assert().Equal([]Event{
+		NodeConnected{Node: "localhost:18098"},
+		NodeDisconnected{Node: "localhost:18098"},
+		NodeConnected{Node: "localhost:18098"},
+	}, handler.take(suite, 3))
+}
+
+func (suite *UnitTestSuite) TestConnectionMonitorStopTwice() {
+	var polls uint32
+	monitor := newConnectionMonitor(time.Millisecond, func() {
+		atomic.AddUint32(&polls, 1)
+	})
+	monitor.start()
+
+	suite.Require().Eventually(func() bool {
+		return atomic.LoadUint32(&polls) > 0
+	}, time.Second, time.Millisecond)
+
+	// Closing a cluster more than once stops its monitor more than once, which must not panic.
+	monitor.stop()
+	suite.Assert().NotPanics(monitor.stop)
+}
+
+func (suite *UnitTestSuite) TestKvConnectionTrackerDefaultMeter() {
+	provider := &mockCoreDiagnosticsProvider{info: &gocbcore.DiagnosticInfo{MemdConns: []gocbcore.MemdConnInfo{
+		{ID: "a", LocalAddr: "10.0.0.9:50000", RemoteAddr: "10.0.0.2:11210", State: gocbcore.EndpointStateConnected},
+	}}}
+	meter := newMeterWrapper(newAggregatingMeter(nil))
+	meter.isDefaultMeter = true
+	bus := newEventBus()
+	tracker := newKvConnectionTracker(provider, nil, meter, bus)
+
+	// The default meter doesn't count reconnects, so without subscribers the connections aren't polled.
+	tracker.poll()
+	suite.Assert().Empty(tracker.conns)
+
+	unsubscribe := bus.subscribe(func(Event) {})
+	defer unsubscribe()
+	tracker.poll()
+	suite.Assert().Len(tracker.conns, 1)
+}
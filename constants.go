@@ -232,6 +232,7 @@ func durabilityLevelFromManagementAPI(level string) DurabilityLevel {
 }
 
 // MutationMacro can be supplied to MutateIn operations to perform ExpandMacros operations.
+// Macro expansion is not supported when connected using the couchbase2 protocol.
 type MutationMacro string
 
 const (
@@ -327,6 +328,25 @@ const (
 	CapabilityStatusUnsupported CapabilityStatus = CapabilityStatus(gocbcore.BucketCapabilityStatusUnsupported)
 )
 
+// The names of the metrics recorded by the SDK and the keys of the tags that they are recorded with, for use by
+// Meter implementations.
+// VOLATILE: This API is subject to change at any time.
+const (
+	MeterNameOperations               = "db.couchbase.operations"
+	MeterNameRetries                  = "db.couchbase.operations.retries"
+	MeterNameTimeouts                 = "db.couchbase.operations.timeouts"
+	MeterNameCircuitBreakerRejections = "db.couchbase.circuit_breaker.rejections"
+	MeterNameReconnects               = "db.couchbase.connections.reconnects"
+
+	MeterAttribServiceKey        = "db.couchbase.service"
+	MeterAttribOperationKey      = "db.operation"
+	MeterAttribOutcomeKey        = "outcome"
+	MeterAttribBucketNameKey     = "db.name"
+	MeterAttribScopeNameKey      = "db.couchbase.scope"
+	MeterAttribCollectionNameKey = "db.couchbase.collection"
+	MeterAttribRetryReasonKey    = "db.couchbase.retry_reason"
+)
+
 const (
 	spanNameDispatchToServer      = "dispatch_to_server"
 	spanNameRequestEncoding       = "request_encoding"
@@ -343,20 +363,30 @@ const (
 	spanAttribServerDurationKey   = "db.couchbase.server_duration"
 	spanAttribServiceKey          = "db.couchbase.service"
 	spanAttribDBNameKey           = "db.name"
+	spanAttribDBStatementKey      = "db.statement"
 	spanAttribDBCollectionNameKey = "db.couchbase.collection"
 	spanAttribDBScopeNameKey      = "db.couchbase.scope"
 	spanAttribDBDurability        = "db.couchbase.durability"
 	spanAttribNumRetries          = "db.couchbase.retries"
 
-	meterNameCBOperations       = "db.couchbase.operations"
-	meterAttribServiceKey       = "db.couchbase.service"
-	meterAttribOperationKey     = "db.operation"
-	meterValueServiceKV         = "kv"
-	meterValueServiceQuery      = "query"
-	meterValueServiceAnalytics  = "analytics"
-	meterValueServiceSearch     = "search"
-	meterValueServiceViews      = "views"
-	meterValueServiceManagement = "management"
+	meterNameCBOperations               = MeterNameOperations
+	meterNameCBRetries                  = MeterNameRetries
+	meterNameCBTimeouts                 = MeterNameTimeouts
+	meterNameCBCircuitBreakerRejections = MeterNameCircuitBreakerRejections
+	meterNameCBReconnects               = MeterNameReconnects
+	meterAttribServiceKey               = MeterAttribServiceKey
+	meterAttribOperationKey             = MeterAttribOperationKey
+	meterAttribOutcomeKey               = MeterAttribOutcomeKey
+	meterAttribBucketNameKey            = MeterAttribBucketNameKey
+	meterAttribScopeNameKey             = MeterAttribScopeNameKey
+	meterAttribCollectionNameKey        = MeterAttribCollectionNameKey
+	meterAttribRetryReasonKey           = MeterAttribRetryReasonKey
+	meterValueServiceKV                 = "kv"
+	meterValueServiceQuery              = "query"
+	meterValueServiceAnalytics          = "analytics"
+	meterValueServiceSearch             = "search"
+	meterValueServiceViews              = "views"
+	meterValueServiceManagement         = "management"
 )
 
 type AnalyticsLinkType string
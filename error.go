@@ -341,4 +341,9 @@ var (
 	// This error occurs when couchbase2 scheme is in use and is equivalent to
 	// ErrPathTooDeep when other schemes are used.
 	ErrDocumentTooDeep = errors.New("document too deep")
+
+	// ErrAdmissionRejected occurs when an operation was rejected by client side admission control because the
+	// configured limits for the service had been reached.
+	// # VOLATILE: This API is subject to change at any time.
+	ErrAdmissionRejected = errors.New("operation rejected by admission control")
 )
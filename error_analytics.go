@@ -1,7 +1,6 @@
 package gocb
 
 import (
-	"encoding/json"
 	gocbcore "github.com/couchbase/gocbcore/v10"
 )
 
@@ -22,6 +21,21 @@ func translateCoreAnalyticsErrorDesc(descs []gocbcore.AnalyticsErrorDesc) []Anal
 	return descsOut
 }
 
+// redactAnalyticsErrorDescs tags the messages of the descriptors as user data when log redaction is enabled, the messages
+// returned by the analytics service can include parts of the statement.
+func redactAnalyticsErrorDescs(descs []AnalyticsErrorDesc) []AnalyticsErrorDesc {
+	if len(descs) == 0 || globalLogRedactionLevel == RedactNone {
+		return descs
+	}
+
+	redacted := make([]AnalyticsErrorDesc, len(descs))
+	for i, desc := range descs {
+		desc.Message = redactUserData(desc.Message)
+		redacted[i] = desc
+	}
+	return redacted
+}
+
 // AnalyticsError is the error type of all analytics query errors.
 // UNCOMMITTED: This API may change in the future.
 type AnalyticsError struct {
@@ -43,7 +57,7 @@ func (e AnalyticsError) MarshalJSON() ([]byte, error) {
 	if e.InnerError != nil {
 		innerError = e.InnerError.Error()
 	}
-	return json.Marshal(struct {
+	return marshalErrorJSON(struct {
 		InnerError      string               `json:"msg,omitempty"`
 		Statement       string               `json:"statement,omitempty"`
 		ClientContextID string               `json:"client_context_id,omitempty"`
@@ -54,10 +68,10 @@ func (e AnalyticsError) MarshalJSON() ([]byte, error) {
 		HTTPStatusCode  int                  `json:"http_status_code,omitempty"`
 	}{
 		InnerError:      innerError,
-		Statement:       e.Statement,
-		ClientContextID: e.ClientContextID,
-		Errors:          e.Errors,
-		Endpoint:        e.Endpoint,
+		Statement:       redactUserData(e.Statement),
+		ClientContextID: redactUserData(e.ClientContextID),
+		Errors:          redactAnalyticsErrorDescs(e.Errors),
+		Endpoint:        redactSystemData(e.Endpoint),
 		RetryReasons:    e.RetryReasons,
 		RetryAttempts:   e.RetryAttempts,
 		HTTPStatusCode:  e.HTTPStatusCode,
@@ -66,7 +80,7 @@ func (e AnalyticsError) MarshalJSON() ([]byte, error) {
 
 // Error returns the string representation of this error.
 func (e AnalyticsError) Error() string {
-	errBytes, serErr := json.Marshal(struct {
+	errBytes, serErr := marshalErrorJSON(struct {
 		InnerError      error                `json:"-"`
 		Statement       string               `json:"statement,omitempty"`
 		ClientContextID string               `json:"client_context_id,omitempty"`
@@ -78,13 +92,13 @@ func (e AnalyticsError) Error() string {
 		HTTPStatusCode  int                  `json:"http_status_code,omitempty"`
 	}{
 		InnerError:      e.InnerError,
-		Statement:       e.Statement,
-		ClientContextID: e.ClientContextID,
-		Errors:          e.Errors,
-		Endpoint:        e.Endpoint,
+		Statement:       redactUserData(e.Statement),
+		ClientContextID: redactUserData(e.ClientContextID),
+		Errors:          redactAnalyticsErrorDescs(e.Errors),
+		Endpoint:        redactSystemData(e.Endpoint),
 		RetryReasons:    e.RetryReasons,
 		RetryAttempts:   e.RetryAttempts,
-		ErrorText:       e.ErrorText,
+		ErrorText:       redactUserData(e.ErrorText),
 		HTTPStatusCode:  e.HTTPStatusCode,
 	})
 	if serErr != nil {
@@ -32,7 +32,7 @@ func (e *GenericError) MarshalJSON() ([]byte, error) {
 
 // Error returns the string representation of a kv error.
 func (e *GenericError) Error() string {
-	errBytes, serErr := json.Marshal(struct {
+	errBytes, serErr := marshalErrorJSON(struct {
 		InnerError error                  `json:"-"`
 		Context    map[string]interface{} `json:"context,omitempty"`
 	}{
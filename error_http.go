@@ -1,7 +1,6 @@
 package gocb
 
 import (
-	"encoding/json"
 	"errors"
 	"github.com/couchbase/gocbcore/v10"
 	"io"
@@ -25,7 +24,7 @@ func (e HTTPError) MarshalJSON() ([]byte, error) {
 	if e.InnerError != nil {
 		innerError = e.InnerError.Error()
 	}
-	return json.Marshal(struct {
+	return marshalErrorJSON(struct {
 		InnerError    string        `json:"msg,omitempty"`
 		UniqueID      string        `json:"unique_id,omitempty"`
 		Endpoint      string        `json:"endpoint,omitempty"`
@@ -36,17 +35,17 @@ func (e HTTPError) MarshalJSON() ([]byte, error) {
 	}{
 		InnerError:    innerError,
 		UniqueID:      e.UniqueID,
-		Endpoint:      e.Endpoint,
+		Endpoint:      redactSystemData(e.Endpoint),
 		RetryReasons:  e.RetryReasons,
 		RetryAttempts: e.RetryAttempts,
-		ErrorText:     e.ErrorText,
+		ErrorText:     redactUserData(e.ErrorText),
 		StatusCode:    e.StatusCode,
 	})
 }
 
 // Error returns the string representation of this error.
 func (e HTTPError) Error() string {
-	errBytes, serErr := json.Marshal(struct {
+	errBytes, serErr := marshalErrorJSON(struct {
 		InnerError    error         `json:"-"`
 		UniqueID      string        `json:"unique_id,omitempty"`
 		Endpoint      string        `json:"endpoint,omitempty"`
@@ -57,10 +56,10 @@ func (e HTTPError) Error() string {
 	}{
 		InnerError:    e.InnerError,
 		UniqueID:      e.UniqueID,
-		Endpoint:      e.Endpoint,
+		Endpoint:      redactSystemData(e.Endpoint),
 		RetryReasons:  e.RetryReasons,
 		RetryAttempts: e.RetryAttempts,
-		ErrorText:     e.ErrorText,
+		ErrorText:     redactUserData(e.ErrorText),
 		StatusCode:    e.StatusCode,
 	})
 	if serErr != nil {
@@ -1,7 +1,6 @@
 package gocb
 
 import (
-	"encoding/json"
 	"github.com/couchbase/gocbcore/v10/memd"
 )
 
@@ -33,7 +32,7 @@ func (e KeyValueError) MarshalJSON() ([]byte, error) {
 	if e.InnerError != nil {
 		innerError = e.InnerError.Error()
 	}
-	return json.Marshal(struct {
+	return marshalErrorJSON(struct {
 		InnerError         string          `json:"msg,omitempty"`
 		StatusCode         memd.StatusCode `json:"status_code,omitempty"`
 		DocumentID         string          `json:"document_id,omitempty"`
@@ -54,7 +53,7 @@ func (e KeyValueError) MarshalJSON() ([]byte, error) {
 	}{
 		InnerError:         innerError,
 		StatusCode:         e.StatusCode,
-		DocumentID:         e.DocumentID,
+		DocumentID:         redactUserData(e.DocumentID),
 		BucketName:         e.BucketName,
 		ScopeName:          e.ScopeName,
 		CollectionName:     e.CollectionName,
@@ -66,15 +65,15 @@ func (e KeyValueError) MarshalJSON() ([]byte, error) {
 		Ref:                e.Ref,
 		RetryReasons:       e.RetryReasons,
 		RetryAttempts:      e.RetryAttempts,
-		LastDispatchedTo:   e.LastDispatchedTo,
-		LastDispatchedFrom: e.LastDispatchedFrom,
+		LastDispatchedTo:   redactSystemData(e.LastDispatchedTo),
+		LastDispatchedFrom: redactSystemData(e.LastDispatchedFrom),
 		LastConnectionID:   e.LastConnectionID,
 	})
 }
 
 // Error returns the string representation of a kv error.
 func (e KeyValueError) Error() string {
-	errBytes, serErr := json.Marshal(struct {
+	errBytes, serErr := marshalErrorJSON(struct {
 		InnerError         error           `json:"-"`
 		StatusCode         memd.StatusCode `json:"status_code,omitempty"`
 		DocumentID         string          `json:"document_id,omitempty"`
@@ -95,7 +94,7 @@ func (e KeyValueError) Error() string {
 	}{
 		InnerError:         e.InnerError,
 		StatusCode:         e.StatusCode,
-		DocumentID:         e.DocumentID,
+		DocumentID:         redactUserData(e.DocumentID),
 		BucketName:         e.BucketName,
 		ScopeName:          e.ScopeName,
 		CollectionName:     e.CollectionName,
@@ -107,8 +106,8 @@ func (e KeyValueError) Error() string {
 		Ref:                e.Ref,
 		RetryReasons:       e.RetryReasons,
 		RetryAttempts:      e.RetryAttempts,
-		LastDispatchedTo:   e.LastDispatchedTo,
-		LastDispatchedFrom: e.LastDispatchedFrom,
+		LastDispatchedTo:   redactSystemData(e.LastDispatchedTo),
+		LastDispatchedFrom: redactSystemData(e.LastDispatchedFrom),
 		LastConnectionID:   e.LastConnectionID,
 	})
 	if serErr != nil {
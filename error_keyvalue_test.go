@@ -1,6 +1,7 @@
 package gocb
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/couchbase/gocbcore/v10/memd"
 )
@@ -36,3 +37,35 @@ func (suite *UnitTestSuite) TestKeyValueError() {
 		aErr.Error(),
 	)
 }
+
+func (suite *UnitTestSuite) TestKeyValueErrorRedaction() {
+	aErr := KeyValueError{
+		InnerError:         ErrDocumentNotFound,
+		StatusCode:         memd.StatusKeyNotFound,
+		DocumentID:         "key",
+		BucketName:         "bucket",
+		LastDispatchedTo:   "10.112.210.101",
+		LastDispatchedFrom: "10.112.210.1",
+	}
+
+	SetLogRedactionLevel(RedactPartial)
+	defer SetLogRedactionLevel(RedactNone)
+
+	// The redaction tags are written as-is, as they are by Error, so that they can be found in the JSON.
+	expected := "{\"msg\":\"document not found\",\"status_code\":1,\"document_id\":\"<ud>key</ud>\",\"bucket\":\"bucket\",\"last_dispatched_to\":\"10.112.210.101\",\"last_dispatched_from\":\"10.112.210.1\"}"
+	b, err := aErr.MarshalJSON()
+	suite.Require().Nil(err)
+	suite.Assert().Equal(expected, string(b))
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	suite.Require().Nil(enc.Encode(aErr))
+	suite.Assert().Equal(expected+"\n", buf.String())
+
+	SetLogRedactionLevel(RedactFull)
+	suite.Assert().Equal(
+		"document not found | {\"status_code\":1,\"document_id\":\"<ud>key</ud>\",\"bucket\":\"bucket\",\"last_dispatched_to\":\"<sd>10.112.210.101</sd>\",\"last_dispatched_from\":\"<sd>10.112.210.1</sd>\"}",
+		aErr.Error(),
+	)
+}
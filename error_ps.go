@@ -1,6 +1,8 @@
 package gocb
 
 import (
+	"fmt"
+
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -154,3 +156,9 @@ func mapPsErrorStatusToGocbError(st *status.Status, readOnly bool) *GenericError
 
 	return makeGenericError(baseErr, context)
 }
+
+// makePsNotSupportedError creates the error returned by operations which have no equivalent on the couchbase2
+// protocol.
+func makePsNotSupportedError(operation string) error {
+	return wrapError(ErrFeatureNotAvailable, fmt.Sprintf("%s is not supported by the couchbase2 protocol", operation))
+}
@@ -1,7 +1,6 @@
 package gocb
 
 import (
-	"encoding/json"
 	gocbcore "github.com/couchbase/gocbcore/v10"
 )
 
@@ -15,7 +14,7 @@ type QueryErrorDesc struct {
 
 // MarshalJSON implements the Marshaler interface.
 func (e QueryErrorDesc) MarshalJSON() ([]byte, error) {
-	return json.Marshal(struct {
+	return marshalErrorJSON(struct {
 		Code    uint32                 `json:"code"`
 		Message string                 `json:"message"`
 		Retry   bool                   `json:"retry,omitempty"`
@@ -41,6 +40,21 @@ func translateCoreQueryErrorDesc(descs []gocbcore.N1QLErrorDesc) []QueryErrorDes
 	return descsOut
 }
 
+// redactQueryErrorDescs tags the messages of the descriptors as user data when log redaction is enabled, the messages
+// returned by the query service can include parts of the statement.
+func redactQueryErrorDescs(descs []QueryErrorDesc) []QueryErrorDesc {
+	if len(descs) == 0 || globalLogRedactionLevel == RedactNone {
+		return descs
+	}
+
+	redacted := make([]QueryErrorDesc, len(descs))
+	for i, desc := range descs {
+		desc.Message = redactUserData(desc.Message)
+		redacted[i] = desc
+	}
+	return redacted
+}
+
 // QueryError is the error type of all query errors.
 // UNCOMMITTED: This API may change in the future.
 type QueryError struct {
@@ -62,7 +76,7 @@ func (e QueryError) MarshalJSON() ([]byte, error) {
 	if e.InnerError != nil {
 		innerError = e.InnerError.Error()
 	}
-	return json.Marshal(struct {
+	return marshalErrorJSON(struct {
 		InnerError      string           `json:"msg,omitempty"`
 		Statement       string           `json:"statement,omitempty"`
 		ClientContextID string           `json:"client_context_id,omitempty"`
@@ -73,10 +87,10 @@ func (e QueryError) MarshalJSON() ([]byte, error) {
 		HTTPStatusCode  int              `json:"http_status_code,omitempty"`
 	}{
 		InnerError:      innerError,
-		Statement:       e.Statement,
-		ClientContextID: e.ClientContextID,
-		Errors:          e.Errors,
-		Endpoint:        e.Endpoint,
+		Statement:       redactUserData(e.Statement),
+		ClientContextID: redactUserData(e.ClientContextID),
+		Errors:          redactQueryErrorDescs(e.Errors),
+		Endpoint:        redactSystemData(e.Endpoint),
 		RetryReasons:    e.RetryReasons,
 		RetryAttempts:   e.RetryAttempts,
 		HTTPStatusCode:  e.HTTPStatusCode,
@@ -85,7 +99,7 @@ func (e QueryError) MarshalJSON() ([]byte, error) {
 
 // Error returns the string representation of this error.
 func (e QueryError) Error() string {
-	errBytes, serErr := json.Marshal(struct {
+	errBytes, serErr := marshalErrorJSON(struct {
 		InnerError      error            `json:"-"`
 		Statement       string           `json:"statement,omitempty"`
 		ClientContextID string           `json:"client_context_id,omitempty"`
@@ -97,13 +111,13 @@ func (e QueryError) Error() string {
 		HTTPStatusCode  int              `json:"http_status_code,omitempty"`
 	}{
 		InnerError:      e.InnerError,
-		Statement:       e.Statement,
-		ClientContextID: e.ClientContextID,
-		Errors:          e.Errors,
-		Endpoint:        e.Endpoint,
+		Statement:       redactUserData(e.Statement),
+		ClientContextID: redactUserData(e.ClientContextID),
+		Errors:          redactQueryErrorDescs(e.Errors),
+		Endpoint:        redactSystemData(e.Endpoint),
 		RetryReasons:    e.RetryReasons,
 		RetryAttempts:   e.RetryAttempts,
-		ErrorText:       e.ErrorText,
+		ErrorText:       redactUserData(e.ErrorText),
 		HTTPStatusCode:  e.HTTPStatusCode,
 	})
 	if serErr != nil {
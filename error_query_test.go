@@ -59,3 +59,52 @@ func (suite *UnitTestSuite) TestQueryErrorImproved() {
 		aErr.Error(),
 	)
 }
+
+func (suite *UnitTestSuite) TestQueryErrorRedaction() {
+	aErr := QueryError{
+		InnerError:      ErrParsingFailure,
+		Statement:       "select * from dataset where email = 'a@b.com'",
+		ClientContextID: "12345",
+		Errors:          []QueryErrorDesc{{Code: 3000, Message: "syntax error near 'a@b.com'"}},
+		Endpoint:        "http://127.0.0.1:8093",
+		ErrorText:       "syntax error near 'a@b.com'",
+	}
+
+	SetLogRedactionLevel(RedactFull)
+	defer SetLogRedactionLevel(RedactNone)
+
+	var out map[string]interface{}
+	b, err := json.Marshal(aErr)
+	suite.Require().Nil(err)
+	suite.Require().Nil(json.Unmarshal(b, &out))
+	suite.Assert().Equal("<ud>select * from dataset where email = 'a@b.com'</ud>", out["statement"])
+	suite.Assert().Equal("<ud>12345</ud>", out["client_context_id"])
+	suite.Assert().Equal("<sd>http://127.0.0.1:8093</sd>", out["endpoint"])
+	suite.Assert().Equal("<ud>syntax error near 'a@b.com'</ud>", out["errors"].([]interface{})[0].(map[string]interface{})["message"])
+	// The descriptors of the error itself must not be modified.
+	suite.Assert().Equal("syntax error near 'a@b.com'", aErr.Errors[0].Message)
+
+	suite.Assert().Contains(aErr.Error(), "\"error_text\":\"<ud>syntax error near 'a@b.com'</ud>\"")
+}
+
+func (suite *UnitTestSuite) TestAnalyticsErrorRedaction() {
+	aErr := AnalyticsError{
+		InnerError:      ErrParsingFailure,
+		Statement:       "select * from dataset where email = 'a@b.com'",
+		ClientContextID: "12345",
+		Errors:          []AnalyticsErrorDesc{{Code: 24000, Message: "syntax error near 'a@b.com'"}},
+	}
+
+	SetLogRedactionLevel(RedactPartial)
+	defer SetLogRedactionLevel(RedactNone)
+
+	var out map[string]interface{}
+	b, err := json.Marshal(aErr)
+	suite.Require().Nil(err)
+	suite.Require().Nil(json.Unmarshal(b, &out))
+	suite.Assert().Equal("<ud>12345</ud>", out["client_context_id"])
+	suite.Assert().Equal("<ud>syntax error near 'a@b.com'</ud>", out["errors"].([]interface{})[0].(map[string]interface{})["Message"])
+
+	suite.Assert().Contains(aErr.Error(), "<ud>syntax error near 'a@b.com'</ud>")
+	suite.Assert().NotContains(aErr.Error(), "\"12345\"")
+}
@@ -1,9 +1,5 @@
 package gocb
 
-import (
-	"encoding/json"
-)
-
 // SearchError is the error type of all search query errors.
 // UNCOMMITTED: This API may change in the future.
 type SearchError struct {
@@ -24,7 +20,7 @@ func (e SearchError) MarshalJSON() ([]byte, error) {
 	if e.InnerError != nil {
 		innerError = e.InnerError.Error()
 	}
-	return json.Marshal(struct {
+	return marshalErrorJSON(struct {
 		InnerError     string        `json:"msg,omitempty"`
 		IndexName      string        `json:"index_name,omitempty"`
 		Query          interface{}   `json:"query,omitempty"`
@@ -36,9 +32,9 @@ func (e SearchError) MarshalJSON() ([]byte, error) {
 	}{
 		InnerError:     innerError,
 		IndexName:      e.IndexName,
-		Query:          e.Query,
-		ErrorText:      e.ErrorText,
-		Endpoint:       e.Endpoint,
+		Query:          redactUserDataValue(e.Query),
+		ErrorText:      redactUserData(e.ErrorText),
+		Endpoint:       redactSystemData(e.Endpoint),
 		RetryReasons:   e.RetryReasons,
 		RetryAttempts:  e.RetryAttempts,
 		HTTPStatusCode: e.HTTPStatusCode,
@@ -47,7 +43,7 @@ func (e SearchError) MarshalJSON() ([]byte, error) {
 
 // Error returns the string representation of this error.
 func (e SearchError) Error() string {
-	errBytes, serErr := json.Marshal(struct {
+	errBytes, serErr := marshalErrorJSON(struct {
 		InnerError       error         `json:"-"`
 		IndexName        string        `json:"index_name,omitempty"`
 		Query            interface{}   `json:"query,omitempty"`
@@ -60,9 +56,9 @@ func (e SearchError) Error() string {
 	}{
 		InnerError:     e.InnerError,
 		IndexName:      e.IndexName,
-		Query:          e.Query,
-		ErrorText:      e.ErrorText,
-		Endpoint:       e.Endpoint,
+		Query:          redactUserDataValue(e.Query),
+		ErrorText:      redactUserData(e.ErrorText),
+		Endpoint:       redactSystemData(e.Endpoint),
 		RetryReasons:   e.RetryReasons,
 		RetryAttempts:  e.RetryAttempts,
 		HTTPStatusCode: e.HTTPStatusCode,
@@ -28,3 +28,23 @@ func (suite *UnitTestSuite) TestSearchError() {
 		aErr.Error(),
 	)
 }
+
+func (suite *UnitTestSuite) TestSearchErrorRedaction() {
+	aErr := SearchError{
+		InnerError: ErrIndexFailure,
+		Query:      search.NewTermQuery("barry"),
+		Endpoint:   "http://127.0.0.1:8094",
+		IndexName:  "barry",
+	}
+
+	SetLogRedactionLevel(RedactPartial)
+	defer SetLogRedactionLevel(RedactNone)
+
+	var out map[string]interface{}
+	b, err := json.Marshal(aErr)
+	suite.Require().Nil(err)
+	suite.Require().Nil(json.Unmarshal(b, &out))
+	suite.Assert().Equal("<ud>{\"term\":\"barry\"}</ud>", out["query"])
+	suite.Assert().Equal("http://127.0.0.1:8094", out["endpoint"])
+	suite.Assert().Equal("barry", out["index_name"])
+}
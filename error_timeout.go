@@ -45,8 +45,8 @@ func (err *TimeoutError) MarshalJSON() ([]byte, error) {
 		TimeObserved:       uint64(err.TimeObserved / time.Microsecond),
 		RetryReasons:       retries,
 		RetryAttempts:      err.RetryAttempts,
-		LastDispatchedTo:   err.LastDispatchedTo,
-		LastDispatchedFrom: err.LastDispatchedFrom,
+		LastDispatchedTo:   redactSystemData(err.LastDispatchedTo),
+		LastDispatchedFrom: redactSystemData(err.LastDispatchedFrom),
 		LastConnectionID:   err.LastConnectionID,
 	}
 
@@ -75,10 +75,14 @@ func (err *TimeoutError) UnmarshalJSON(data []byte) error {
 }
 
 func (err TimeoutError) Error() string {
+	redacted := err
+	redacted.LastDispatchedTo = redactSystemData(err.LastDispatchedTo)
+	redacted.LastDispatchedFrom = redactSystemData(err.LastDispatchedFrom)
+
 	if err.InnerError == nil {
-		return serializeWrappedError(err)
+		return serializeWrappedError(redacted)
 	}
-	return err.InnerError.Error() + " | " + serializeWrappedError(err)
+	return err.InnerError.Error() + " | " + serializeWrappedError(redacted)
 }
 
 // Unwrap returns the underlying reason for the error
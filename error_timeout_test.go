@@ -125,3 +125,25 @@ func (suite *IntegrationTestSuite) TestTimeoutError_Retries() {
 		suite.T().Fatalf("Error couldn't be asserted to TimeoutError: %v", err)
 	}
 }
+
+func (suite *UnitTestSuite) TestTimeoutErrorRedaction() {
+	tErr := TimeoutError{
+		InnerError:         ErrUnambiguousTimeout,
+		OperationID:        "Get",
+		LastDispatchedTo:   "127.0.0.1:56830",
+		LastDispatchedFrom: "127.0.0.1:56839",
+	}
+
+	SetLogRedactionLevel(RedactFull)
+	defer SetLogRedactionLevel(RedactNone)
+
+	suite.Assert().Contains(tErr.Error(), `"LastDispatchedTo":"<sd>127.0.0.1:56830</sd>","LastDispatchedFrom":"<sd>127.0.0.1:56839</sd>"`)
+
+	b, err := json.Marshal(&tErr)
+	suite.Require().Nil(err)
+
+	var out map[string]interface{}
+	suite.Require().Nil(json.Unmarshal(b, &out))
+	suite.Assert().Equal("<sd>127.0.0.1:56830</sd>", out["r"])
+	suite.Assert().Equal("<sd>127.0.0.1:56839</sd>", out["l"])
+}
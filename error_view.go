@@ -1,7 +1,6 @@
 package gocb
 
 import (
-	"encoding/json"
 	gocbcore "github.com/couchbase/gocbcore/v10"
 )
 
@@ -38,7 +37,7 @@ type ViewError struct {
 
 // MarshalJSON implements the Marshaler interface.
 func (e ViewError) MarshalJSON() ([]byte, error) {
-	return json.Marshal(struct {
+	return marshalErrorJSON(struct {
 		InnerError         string          `json:"msg,omitempty"`
 		DesignDocumentName string          `json:"design_document_name,omitempty"`
 		ViewName           string          `json:"view_name,omitempty"`
@@ -52,7 +51,7 @@ func (e ViewError) MarshalJSON() ([]byte, error) {
 		DesignDocumentName: e.DesignDocumentName,
 		ViewName:           e.ViewName,
 		Errors:             e.Errors,
-		Endpoint:           e.Endpoint,
+		Endpoint:           redactSystemData(e.Endpoint),
 		RetryReasons:       e.RetryReasons,
 		RetryAttempts:      e.RetryAttempts,
 		HTTPStatusCode:     e.HTTPStatusCode,
@@ -61,7 +60,7 @@ func (e ViewError) MarshalJSON() ([]byte, error) {
 
 // Error returns the string representation of this error.
 func (e ViewError) Error() string {
-	errBytes, serErr := json.Marshal(struct {
+	errBytes, serErr := marshalErrorJSON(struct {
 		InnerError         error           `json:"-"`
 		DesignDocumentName string          `json:"design_document_name,omitempty"`
 		ViewName           string          `json:"view_name,omitempty"`
@@ -76,10 +75,10 @@ func (e ViewError) Error() string {
 		DesignDocumentName: e.DesignDocumentName,
 		ViewName:           e.ViewName,
 		Errors:             e.Errors,
-		Endpoint:           e.Endpoint,
+		Endpoint:           redactSystemData(e.Endpoint),
 		RetryReasons:       e.RetryReasons,
 		RetryAttempts:      e.RetryAttempts,
-		ErrorText:          e.ErrorText,
+		ErrorText:          redactUserData(e.ErrorText),
 		HTTPStatusCode:     e.HTTPStatusCode,
 	})
 	if serErr != nil {
@@ -1,14 +1,28 @@
 package gocb
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 
 	gocbcore "github.com/couchbase/gocbcore/v10"
 )
 
+// marshalErrorJSON marshals the details of an error for use in its string representation, or a report for logging.
+// HTML escaping is disabled so that redaction tags are written as-is, rather than as unicode escapes.
+func marshalErrorJSON(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
 func serializeWrappedError(err error) string {
-	errBytes, serErr := json.Marshal(err)
+	errBytes, serErr := marshalErrorJSON(err)
 	if serErr != nil {
 		logErrorf("failed to serialize error to json: %s", serErr.Error())
 	}
@@ -151,3 +165,29 @@ func maybeEnhanceAnalyticsError(err error) error {
 func maybeEnhanceSearchError(err error) error {
 	return maybeEnhanceCoreErr(err)
 }
+
+// retryReasonsFromError returns the reasons that the operation which failed with err was retried for, if known.
+func retryReasonsFromError(err error) []RetryReason {
+	if err == nil {
+		return nil
+	}
+
+	var kvErrPtr *KeyValueError
+	if errors.As(err, &kvErrPtr) {
+		return kvErrPtr.RetryReasons
+	}
+	var kvErr KeyValueError
+	if errors.As(err, &kvErr) {
+		return kvErr.RetryReasons
+	}
+	var timeoutErrPtr *TimeoutError
+	if errors.As(err, &timeoutErrPtr) {
+		return timeoutErrPtr.RetryReasons
+	}
+	var timeoutErr TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return timeoutErr.RetryReasons
+	}
+
+	return nil
+}
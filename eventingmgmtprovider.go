@@ -0,0 +1,13 @@
+package gocb
+
+type eventingManagementProvider interface {
+	UpsertFunction(function EventingFunction, opts *UpsertEventingFunctionOptions) error
+	DropFunction(name string, opts *DropEventingFunctionOptions) error
+	DeployFunction(name string, opts *DeployEventingFunctionOptions) error
+	UndeployFunction(name string, opts *UndeployEventingFunctionOptions) error
+	GetAllFunctions(opts *GetAllEventingFunctionsOptions) ([]EventingFunction, error)
+	GetFunction(name string, opts *GetEventingFunctionOptions) (*EventingFunction, error)
+	PauseFunction(name string, opts *PauseEventingFunctionOptions) error
+	ResumeFunction(name string, opts *ResumeEventingFunctionOptions) error
+	FunctionsStatus(opts *EventingFunctionsStatusOptions) (*EventingStatus, error)
+}
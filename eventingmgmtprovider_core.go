@@ -0,0 +1,228 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type eventingManagementProviderCore struct {
+	mgmtProvider mgmtProvider
+	tracer       RequestTracer
+	meter        *meterWrapper
+}
+
+type eventingRequestOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+	Context       context.Context
+}
+
+func (efm *eventingManagementProviderCore) doMgmtRequest(ctx context.Context, req mgmtRequest) (*mgmtResponse, error) {
+	resp, err := efm.mgmtProvider.executeMgmtRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (efm *eventingManagementProviderCore) tryParseErrorMessage(req *mgmtRequest, resp *mgmtResponse) error {
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logDebugf("Failed to read eventing function response body: %s", err)
+		return nil
+	}
+
+	var baseErr error
+	strBody := string(b)
+	if strings.Contains(strBody, "ERR_APP_NOT_FOUND_TS") {
+		baseErr = ErrEventingFunctionNotFound
+	} else if strings.Contains(strBody, "ERR_APP_NOT_DEPLOYED") {
+		baseErr = ErrEventingFunctionNotDeployed
+	} else if strings.Contains(strBody, "ERR_HANDLER_COMPILATION") {
+		baseErr = ErrEventingFunctionCompilationFailure
+	} else if strings.Contains(strBody, "ERR_SRC_MB_SAME") {
+		baseErr = ErrEventingFunctionIdenticalKeyspace
+	} else if strings.Contains(strBody, "ERR_APP_NOT_BOOTSTRAPPED") {
+		baseErr = ErrEventingFunctionNotBootstrapped
+	} else if strings.Contains(strBody, "ERR_APP_NOT_UNDEPLOYED") {
+		baseErr = ErrEventingFunctionDeployed
+	} else if strings.Contains(strBody, "ERR_COLLECTION_MISSING") {
+		baseErr = ErrCollectionNotFound
+	} else if strings.Contains(strBody, "ERR_BUCKET_MISSING") {
+		baseErr = ErrBucketNotFound
+	} else {
+		baseErr = errors.New(string(b))
+	}
+
+	return makeGenericMgmtError(baseErr, req, resp, strBody)
+}
+
+func (efm *eventingManagementProviderCore) doRequest(path string, method string, opName string, function *EventingFunction,
+	target interface{}, opts eventingRequestOptions) error {
+	start := time.Now()
+	defer efm.meter.ValueRecord(meterValueServiceManagement, opName, start)
+
+	op := "manager_eventing_" + opName
+	span := createSpan(efm.tracer, opts.ParentSpan, op, "management")
+	span.SetAttribute("db.operation", method+" "+path)
+	defer span.End()
+
+	var b []byte
+	if function != nil {
+		var err error
+		b, err = json.Marshal(function)
+		if err != nil {
+			return err
+		}
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeEventing,
+		Method:        method,
+		Path:          path,
+		RetryStrategy: opts.RetryStrategy,
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+		Body:          b,
+	}
+	resp, err := efm.doMgmtRequest(opts.Context, req)
+	if err != nil {
+		return err
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		idxErr := efm.tryParseErrorMessage(&req, resp)
+		if idxErr != nil {
+			return idxErr
+		}
+
+		return makeMgmtBadStatusError("failed eventing "+opName, &req, resp)
+	}
+
+	if target != nil {
+		jsonDec := json.NewDecoder(resp.Body)
+		err = jsonDec.Decode(target)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (efm *eventingManagementProviderCore) UpsertFunction(function EventingFunction, opts *UpsertEventingFunctionOptions) error {
+	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s", url.PathEscape(function.Name)), "POST",
+		"upsert_function", &function, nil, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+}
+
+func (efm *eventingManagementProviderCore) DropFunction(name string, opts *DropEventingFunctionOptions) error {
+	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s", url.PathEscape(name)), "DELETE",
+		"drop_function", nil, nil, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+}
+
+func (efm *eventingManagementProviderCore) DeployFunction(name string, opts *DeployEventingFunctionOptions) error {
+	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s/deploy", url.PathEscape(name)), "POST",
+		"deploy_function", nil, nil, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+}
+
+func (efm *eventingManagementProviderCore) UndeployFunction(name string, opts *UndeployEventingFunctionOptions) error {
+	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s/undeploy", url.PathEscape(name)), "POST",
+		"undeploy_function", nil, nil, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+}
+
+func (efm *eventingManagementProviderCore) GetAllFunctions(opts *GetAllEventingFunctionsOptions) ([]EventingFunction, error) {
+	var functions []EventingFunction
+	err := efm.doRequest("/api/v1/functions", "GET",
+		"get_all_functions", nil, &functions, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return functions, nil
+}
+
+func (efm *eventingManagementProviderCore) GetFunction(name string, opts *GetEventingFunctionOptions) (*EventingFunction, error) {
+	var function *EventingFunction
+	err := efm.doRequest(fmt.Sprintf("/api/v1/functions/%s", url.PathEscape(name)), "GET",
+		"get_function", nil, &function, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return function, nil
+}
+
+func (efm *eventingManagementProviderCore) PauseFunction(name string, opts *PauseEventingFunctionOptions) error {
+	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s/pause", url.PathEscape(name)), "POST",
+		"pause_function", nil, nil, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+}
+
+func (efm *eventingManagementProviderCore) ResumeFunction(name string, opts *ResumeEventingFunctionOptions) error {
+	return efm.doRequest(fmt.Sprintf("/api/v1/functions/%s/resume", url.PathEscape(name)), "POST",
+		"resume_function", nil, nil, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+}
+
+func (efm *eventingManagementProviderCore) FunctionsStatus(opts *EventingFunctionsStatusOptions) (*EventingStatus, error) {
+	var functions *EventingStatus
+	err := efm.doRequest("/api/v1/status", "GET",
+		"functions_status", nil, &functions, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return functions, nil
+}
@@ -0,0 +1,43 @@
+package gocb
+
+// eventingManagementProviderPs is used when connected with couchbase2, which has no eventing management service.
+type eventingManagementProviderPs struct {
+}
+
+var _ eventingManagementProvider = &eventingManagementProviderPs{}
+
+func (efm *eventingManagementProviderPs) UpsertFunction(EventingFunction, *UpsertEventingFunctionOptions) error {
+	return makePsNotSupportedError("UpsertFunction")
+}
+
+func (efm *eventingManagementProviderPs) DropFunction(string, *DropEventingFunctionOptions) error {
+	return makePsNotSupportedError("DropFunction")
+}
+
+func (efm *eventingManagementProviderPs) DeployFunction(string, *DeployEventingFunctionOptions) error {
+	return makePsNotSupportedError("DeployFunction")
+}
+
+func (efm *eventingManagementProviderPs) UndeployFunction(string, *UndeployEventingFunctionOptions) error {
+	return makePsNotSupportedError("UndeployFunction")
+}
+
+func (efm *eventingManagementProviderPs) GetAllFunctions(*GetAllEventingFunctionsOptions) ([]EventingFunction, error) {
+	return nil, makePsNotSupportedError("GetAllFunctions")
+}
+
+func (efm *eventingManagementProviderPs) GetFunction(string, *GetEventingFunctionOptions) (*EventingFunction, error) {
+	return nil, makePsNotSupportedError("GetFunction")
+}
+
+func (efm *eventingManagementProviderPs) PauseFunction(string, *PauseEventingFunctionOptions) error {
+	return makePsNotSupportedError("PauseFunction")
+}
+
+func (efm *eventingManagementProviderPs) ResumeFunction(string, *ResumeEventingFunctionOptions) error {
+	return makePsNotSupportedError("ResumeFunction")
+}
+
+func (efm *eventingManagementProviderPs) FunctionsStatus(*EventingFunctionsStatusOptions) (*EventingStatus, error) {
+	return nil, makePsNotSupportedError("FunctionsStatus")
+}
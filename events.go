@@ -0,0 +1,283 @@
+package gocb
+
+import (
+	"errors"
+	"sync"
+)
+
+// Event is a notification about a change in the state of the cluster, or of the connections held to it, which can be
+// received by calling Cluster.Subscribe. The concrete type of an Event is one of ConfigChanged, NodeConnected,
+// NodeDisconnected, BucketOpened or AuthFailed.
+// Config and connection changes are observed by polling the state of the connections once a second, so changes
+// which are undone between polls are missed and several changes within a poll are delivered together.
+// VOLATILE: This API is subject to change at any time.
+type Event interface {
+	isEvent()
+}
+
+// ConfigChanged is delivered when the SDK observes a new config revision for a bucket, such as during a rebalance,
+// failover or when a node is added or removed.
+// Config changes are observed once a bucket has been opened, each open bucket has its own config revision and so a
+// change to the cluster is delivered once for each bucket.
+// VOLATILE: This API is subject to change at any time.
+type ConfigChanged struct {
+	// Bucket is the name of the bucket whose config changed.
+	Bucket string
+	// Rev is the revision of the new config.
+	Rev int64
+	// Nodes is the sorted list of key-value nodes the SDK held connections to for the bucket when the change was
+	// observed.
+	Nodes []string
+}
+
+// NodeConnected is delivered when a key-value connection to a node is established, or reestablished.
+// VOLATILE: This API is subject to change at any time.
+type NodeConnected struct {
+	Node   string
+	Bucket string
+}
+
+// NodeDisconnected is delivered when a key-value connection to a node is lost.
+// VOLATILE: This API is subject to change at any time.
+type NodeDisconnected struct {
+	Node   string
+	Bucket string
+}
+
+// BucketOpened is delivered when the SDK has started connecting to a bucket.
+// Buckets are not connected to when using couchbase2:// connection strings, in which case it is not delivered.
+// VOLATILE: This API is subject to change at any time.
+type BucketOpened struct {
+	Bucket string
+}
+
+// AuthFailed is delivered when opening a bucket with Cluster.Bucket, or waiting with Cluster.WaitUntilReady or
+// Bucket.WaitUntilReady, fails because the SDK could not authenticate. Bucket is empty for Cluster.WaitUntilReady.
+// It is not delivered for individual operations which fail with ErrAuthenticationFailure, such as after the
+// credentials have been rotated, nor when the SDK fails to authenticate in the background and WaitUntilReady isn't
+// called, check the errors returned by operations for those.
+// VOLATILE: This API is subject to change at any time.
+type AuthFailed struct {
+	Bucket string
+	Err    error
+}
+
+func (ConfigChanged) isEvent()    {}
+func (NodeConnected) isEvent()    {}
+func (NodeDisconnected) isEvent() {}
+func (BucketOpened) isEvent()     {}
+func (AuthFailed) isEvent()       {}
+
+// Subscribe registers handler to be called with each Event raised by this cluster, returning a function which
+// removes the subscription.
+// Each subscription has its own goroutine from which the handler is called with events in the order that they were
+// raised, a slow handler delays its own subscription but not the SDK or other subscriptions. Events which have not
+// been handled when the subscription is removed, or the cluster closed, are discarded.
+// A ConfigChanged event which has not been handled yet is replaced by a newer ConfigChanged event for the same bucket
+// raised directly after it. At most 1024 events are queued for each subscription, when a handler falls that far behind the oldest events
+// are discarded.
+// VOLATILE: This API is subject to change at any time.
+func (c *Cluster) Subscribe(handler func(Event)) (unsubscribe func()) {
+	return c.events.subscribe(handler)
+}
+
+type eventBus struct {
+	lock        sync.RWMutex
+	nextID      uint64
+	subscribers map[uint64]*eventSubscriber
+	closed      bool
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscribers: make(map[uint64]*eventSubscriber),
+	}
+}
+
+func (b *eventBus) subscribe(handler func(Event)) func() {
+	if b == nil || handler == nil {
+		return func() {}
+	}
+
+	sub := newEventSubscriber(handler)
+
+	b.lock.Lock()
+	if b.closed {
+		b.lock.Unlock()
+		return func() {}
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.lock.Unlock()
+
+	go sub.run()
+
+	return func() {
+		b.lock.Lock()
+		delete(b.subscribers, id)
+		b.lock.Unlock()
+
+		sub.stop()
+	}
+}
+
+// close removes all subscriptions, stopping their goroutines.
+func (b *eventBus) close() {
+	if b == nil {
+		return
+	}
+
+	b.lock.Lock()
+	subs := b.subscribers
+	b.subscribers = make(map[uint64]*eventSubscriber)
+	b.closed = true
+	b.lock.Unlock()
+
+	for _, sub := range subs {
+		sub.stop()
+	}
+}
+
+func (b *eventBus) hasSubscribers() bool {
+	if b == nil {
+		return false
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return len(b.subscribers) > 0
+}
+
+func (b *eventBus) publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.lock.RLock()
+	for _, sub := range b.subscribers {
+		sub.enqueue(event)
+	}
+	b.lock.RUnlock()
+}
+
+// publishAuthFailure raises an AuthFailed event if err was caused by an authentication failure.
+func (b *eventBus) publishAuthFailure(bucket string, err error) {
+	if err == nil || !errors.Is(err, ErrAuthenticationFailure) {
+		return
+	}
+
+	b.publish(AuthFailed{
+		Bucket: bucket,
+		Err:    err,
+	})
+}
+
+// eventSubscriberMaxQueued is the number of events which can be queued for a subscriber before the oldest are dropped.
+const eventSubscriberMaxQueued = 1024
+
+// eventSubscriber delivers events to a handler from its own goroutine, so that handlers can't block the goroutine
+// which observed the event.
+type eventSubscriber struct {
+	handler   func(Event)
+	maxQueued int
+
+	lock    sync.Mutex
+	queue   []Event
+	stopped bool
+	signal  chan struct{}
+	stopCh  chan struct{}
+	once    sync.Once
+}
+
+func newEventSubscriber(handler func(Event)) *eventSubscriber {
+	return &eventSubscriber{
+		handler:   handler,
+		maxQueued: eventSubscriberMaxQueued,
+		signal:    make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+func (s *eventSubscriber) enqueue(event Event) {
+	s.lock.Lock()
+	if s.stopped {
+		s.lock.Unlock()
+		return
+	}
+
+	// Only the latest config of a bucket matters to a handler which hasn't caught up yet, so replace rather than queue
+	// it.
+	if changed, ok := event.(ConfigChanged); ok && len(s.queue) > 0 {
+		if last, ok := s.queue[len(s.queue)-1].(ConfigChanged); ok && last.Bucket == changed.Bucket {
+			s.queue[len(s.queue)-1] = event
+			s.lock.Unlock()
+			return
+		}
+	}
+
+	var dropped Event
+	if len(s.queue) >= s.maxQueued {
+		dropped = s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+	}
+	s.queue = append(s.queue, event)
+	s.lock.Unlock()
+
+	if dropped != nil {
+		logDebugf("Event subscriber has fallen behind, dropped %T event", dropped)
+	}
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *eventSubscriber) run() {
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-s.signal:
+		}
+
+		for {
+			event, ok := s.next()
+			if !ok {
+				break
+			}
+
+			s.handler(event)
+		}
+	}
+}
+
+func (s *eventSubscriber) next() (Event, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped || len(s.queue) == 0 {
+		return nil, false
+	}
+
+	event := s.queue[0]
+	s.queue[0] = nil
+	s.queue = s.queue[1:]
+	return event, true
+}
+
+// stop discards any events which haven't been handled and stops the goroutine, it does not wait for a handler which
+// is in progress to return.
+func (s *eventSubscriber) stop() {
+	s.once.Do(func() {
+		s.lock.Lock()
+		s.stopped = true
+		s.queue = nil
+		s.lock.Unlock()
+
+		close(s.stopCh)
+	})
+}
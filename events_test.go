@@ -0,0 +1,149 @@
+package gocb
+
+import (
+	"errors"
+	"time"
+)
+
+// testEventHandler signals each event that it handles on a channel, so that tests can wait for events to have been
+// handled.
+type testEventHandler struct {
+	handled chan Event
+}
+
+func newTestEventHandler() *testEventHandler {
+	return &testEventHandler{
+		handled: make(chan Event, eventSubscriberMaxQueued),
+	}
+}
+
+func (h *testEventHandler) handle(event Event) {
+	h.handled <- event
+}
+
+// take waits for the next n events to be handled, failing the test if they aren't handled within a second.
+func (h *testEventHandler) take(suite *UnitTestSuite, n int) []Event {
+	var events []Event
+	for len(events) < n {
+		select {
+		case event := <-h.handled:
+			events = append(events, event)
+		case <-time.After(time.Second):
+			suite.T().Fatalf("only %d of %d events were handled", len(events), n)
+		}
+	}
+
+	return events
+}
+
+// assertNone checks that no more events are handled within a short time.
+func (h *testEventHandler) assertNone(suite *UnitTestSuite) {
+	select {
+	case event := <-h.handled:
+		suite.T().Errorf("unexpected event handled: %#v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (suite *UnitTestSuite) TestEventBus() {
+	bus := newEventBus()
+	defer bus.close()
+
+	first := newTestEventHandler()
+	unsubscribe := bus.subscribe(first.handle)
+	second := newTestEventHandler()
+	bus.subscribe(second.handle)
+	suite.Assert().True(bus.hasSubscribers())
+
+	bus.publish(BucketOpened{Bucket: "default"})
+	bus.publishAuthFailure("default", errors.New("connection refused"))
+
+	authErr := makeGenericError(ErrAuthenticationFailure, nil)
+	bus.publishAuthFailure("default", authErr)
+
+	expected := []Event{
+		BucketOpened{Bucket: "default"},
+		AuthFailed{Bucket: "default", Err: authErr},
+	}
+	suite.Assert().Equal(expected, first.take(suite, 2))
+	suite.Assert().Equal(expected, second.take(suite, 2))
+
+	unsubscribe()
+	unsubscribe()
+	bus.publish(BucketOpened{Bucket: "travel"})
+	suite.Assert().Equal([]Event{BucketOpened{Bucket: "travel"}}, second.take(suite, 1))
+	first.assertNone(suite)
+
+	// Clusters which weren't created through Connect don't have a bus.
+	var nilBus *eventBus
+	nilBus.subscribe(func(Event) {})()
+	nilBus.publish(BucketOpened{})
+	suite.Assert().False(nilBus.hasSubscribers())
+}
+
+func (suite *UnitTestSuite) TestEventBusSlowHandler() {
+	bus := newEventBus()
+
+	release := make(chan struct{})
+	slow := newTestEventHandler()
+	bus.subscribe(func(event Event) {
+		<-release
+		slow.handle(event)
+	})
+	fast := newTestEventHandler()
+	bus.subscribe(fast.handle)
+
+	// Publishing must not wait for the blocked handler, nor must other subscribers.
+	published := make(chan struct{})
+	go func() {
+		bus.publish(BucketOpened{Bucket: "default"})
+		bus.publish(BucketOpened{Bucket: "travel"})
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		suite.T().Fatal("publish was blocked by a handler")
+	}
+
+	expected := []Event{BucketOpened{Bucket: "default"}, BucketOpened{Bucket: "travel"}}
+	suite.Assert().Equal(expected, fast.take(suite, 2))
+	close(release)
+	suite.Assert().Equal(expected, slow.take(suite, 2))
+
+	// Once closed events are no longer delivered.
+	bus.close()
+	bus.publish(BucketOpened{Bucket: "beer-sample"})
+	bus.subscribe(fast.handle)
+	bus.publish(BucketOpened{Bucket: "beer-sample"})
+	fast.assertNone(suite)
+	suite.Assert().False(bus.hasSubscribers())
+}
+
+func (suite *UnitTestSuite) TestEventSubscriberQueueLimit() {
+	handler := newTestEventHandler()
+	sub := newEventSubscriber(handler.handle)
+	sub.maxQueued = 3
+
+	// The subscriber isn't running yet, which is the same as its handler being stuck.
+	sub.enqueue(NodeConnected{Node: "a"})
+	sub.enqueue(ConfigChanged{Rev: 1})
+	sub.enqueue(ConfigChanged{Rev: 2})
+	sub.enqueue(NodeConnected{Node: "b"})
+	sub.enqueue(NodeConnected{Node: "c"})
+	sub.enqueue(ConfigChanged{Bucket: "travel", Rev: 3})
+	sub.enqueue(ConfigChanged{Bucket: "default", Rev: 4})
+	sub.enqueue(ConfigChanged{Bucket: "default", Rev: 5})
+
+	go sub.run()
+	defer sub.stop()
+
+	// Consecutive config changes for the same bucket are coalesced and the oldest events dropped once the queue is
+	// full.
+	suite.Assert().Equal([]Event{
+		NodeConnected{Node: "c"},
+		ConfigChanged{Bucket: "travel", Rev: 3},
+		ConfigChanged{Bucket: "default", Rev: 5},
+	}, handler.take(suite, 3))
+	handler.assertNone(suite)
+}
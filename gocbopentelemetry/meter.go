@@ -0,0 +1,118 @@
+package gocbopentelemetry
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lissteron/gocb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const operationsMeterName = gocb.MeterNameOperations
+
+// OpenTelemetryMeter is an implementation of the gocb Meter interface that records metrics using an OpenTelemetry
+// MeterProvider. Operation latencies are recorded to a histogram, in microseconds, with the tags provided by the
+// SDK (such as the service, operation and outcome) as attributes.
+type OpenTelemetryMeter struct {
+	meter metric.Meter
+
+	lock       sync.RWMutex
+	histograms map[string]metric.Int64Histogram
+	counters   map[string]metric.Int64Counter
+}
+
+var _ gocb.Meter = (*OpenTelemetryMeter)(nil)
+
+// NewOpenTelemetryMeter creates a new OpenTelemetryMeter.
+func NewOpenTelemetryMeter(provider metric.MeterProvider) *OpenTelemetryMeter {
+	return &OpenTelemetryMeter{
+		meter:      provider.Meter(instrumentationName, metric.WithInstrumentationVersion(gocb.Version())),
+		histograms: make(map[string]metric.Int64Histogram),
+		counters:   make(map[string]metric.Int64Counter),
+	}
+}
+
+// Counter returns a Counter for the metric with the provided name and tags.
+func (meter *OpenTelemetryMeter) Counter(name string, tags map[string]string) (gocb.Counter, error) {
+	meter.lock.RLock()
+	counter, ok := meter.counters[name]
+	meter.lock.RUnlock()
+	if !ok {
+		meter.lock.Lock()
+		counter, ok = meter.counters[name]
+		if !ok {
+			var err error
+			counter, err = meter.meter.Int64Counter(name)
+			if err != nil {
+				meter.lock.Unlock()
+				return nil, err
+			}
+			meter.counters[name] = counter
+		}
+		meter.lock.Unlock()
+	}
+
+	return &openTelemetryCounter{
+		counter: counter,
+		attribs: metric.WithAttributeSet(attributeSetFromTags(tags)),
+	}, nil
+}
+
+// ValueRecorder returns a ValueRecorder for the metric with the provided name and tags.
+func (meter *OpenTelemetryMeter) ValueRecorder(name string, tags map[string]string) (gocb.ValueRecorder, error) {
+	meter.lock.RLock()
+	histogram, ok := meter.histograms[name]
+	meter.lock.RUnlock()
+	if !ok {
+		meter.lock.Lock()
+		histogram, ok = meter.histograms[name]
+		if !ok {
+			opts := []metric.Int64HistogramOption{metric.WithUnit("us")}
+			if name == operationsMeterName {
+				opts = append(opts, metric.WithDescription("Duration of SDK operations."))
+			}
+
+			var err error
+			histogram, err = meter.meter.Int64Histogram(name, opts...)
+			if err != nil {
+				meter.lock.Unlock()
+				return nil, err
+			}
+			meter.histograms[name] = histogram
+		}
+		meter.lock.Unlock()
+	}
+
+	return &openTelemetryValueRecorder{
+		histogram: histogram,
+		attribs:   metric.WithAttributeSet(attributeSetFromTags(tags)),
+	}, nil
+}
+
+func attributeSetFromTags(tags map[string]string) attribute.Set {
+	attribs := make([]attribute.KeyValue, 0, len(tags))
+	for k, v := range tags {
+		attribs = append(attribs, attribute.String(k, v))
+	}
+
+	return attribute.NewSet(attribs...)
+}
+
+type openTelemetryCounter struct {
+	counter metric.Int64Counter
+	attribs metric.MeasurementOption
+}
+
+func (c *openTelemetryCounter) IncrementBy(num uint64) {
+	c.counter.Add(context.Background(), int64(num), c.attribs)
+}
+
+type openTelemetryValueRecorder struct {
+	histogram metric.Int64Histogram
+	attribs   metric.MeasurementOption
+}
+
+func (r *openTelemetryValueRecorder) RecordValue(val uint64) {
+	r.histogram.Record(context.Background(), int64(val), r.attribs)
+}
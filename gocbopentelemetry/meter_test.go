@@ -0,0 +1,98 @@
+package gocbopentelemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collectMetrics(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		assert.Equal(t, instrumentationName, sm.Scope.Name)
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+
+	return metrics
+}
+
+func TestMeterOperationsHistogram(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := NewOpenTelemetryMeter(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	tags := map[string]string{
+		"db.couchbase.service": "kv",
+		"db.operation":         "get",
+		"outcome":              "Success",
+	}
+	recorder, err := meter.ValueRecorder("db.couchbase.operations", tags)
+	require.NoError(t, err)
+	recorder.RecordValue(100)
+	recorder.RecordValue(300)
+
+	recorder, err = meter.ValueRecorder("db.couchbase.operations", map[string]string{
+		"db.couchbase.service": "query",
+		"db.operation":         "query",
+		"outcome":              "Timeout",
+	})
+	require.NoError(t, err)
+	recorder.RecordValue(5000)
+
+	metrics := collectMetrics(t, reader)
+	ops, ok := metrics["db.couchbase.operations"]
+	require.True(t, ok)
+	assert.Equal(t, "us", ops.Unit)
+
+	histogram, ok := ops.Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 2)
+
+	for _, dp := range histogram.DataPoints {
+		service, _ := dp.Attributes.Value(attribute.Key("db.couchbase.service"))
+		switch service.AsString() {
+		case "kv":
+			assert.Equal(t, uint64(2), dp.Count)
+			assert.Equal(t, int64(400), dp.Sum)
+			outcome, _ := dp.Attributes.Value(attribute.Key("outcome"))
+			assert.Equal(t, "Success", outcome.AsString())
+		case "query":
+			assert.Equal(t, uint64(1), dp.Count)
+			assert.Equal(t, int64(5000), dp.Sum)
+		default:
+			t.Fatalf("unexpected service %s", service.AsString())
+		}
+	}
+}
+
+func TestMeterCounter(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := NewOpenTelemetryMeter(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	tags := map[string]string{"db.couchbase.service": "kv"}
+	counter, err := meter.Counter("db.couchbase.operations.retries", tags)
+	require.NoError(t, err)
+	counter.IncrementBy(2)
+
+	counter, err = meter.Counter("db.couchbase.operations.retries", tags)
+	require.NoError(t, err)
+	counter.IncrementBy(3)
+
+	metrics := collectMetrics(t, reader)
+	retries, ok := metrics["db.couchbase.operations.retries"]
+	require.True(t, ok)
+
+	sum, ok := retries.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(5), sum.DataPoints[0].Value)
+}
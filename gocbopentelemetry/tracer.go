@@ -0,0 +1,147 @@
+// Package gocbopentelemetry provides OpenTelemetry implementations of the gocb tracing and metrics abstractions.
+// It is a separate module so that applications which don't use OpenTelemetry don't depend on it through gocb.
+package gocbopentelemetry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lissteron/gocb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "com.couchbase.client/go"
+
+	dispatchSpanName = "dispatch_to_server"
+)
+
+// OpenTelemetryRequestTracer is an implementation of the gocb RequestTracer interface that creates spans using an
+// OpenTelemetry TracerProvider.
+type OpenTelemetryRequestTracer struct {
+	tracer trace.Tracer
+}
+
+var _ gocb.RequestTracer = (*OpenTelemetryRequestTracer)(nil)
+
+// NewOpenTelemetryRequestTracer creates a new OpenTelemetryRequestTracer.
+func NewOpenTelemetryRequestTracer(provider trace.TracerProvider) *OpenTelemetryRequestTracer {
+	return &OpenTelemetryRequestTracer{
+		tracer: provider.Tracer(instrumentationName, trace.WithInstrumentationVersion(gocb.Version())),
+	}
+}
+
+// RequestSpan creates a new RequestSpan. The parent context can be the Context of a span created by this tracer, a
+// context.Context or a trace.SpanContext.
+func (tracer *OpenTelemetryRequestTracer) RequestSpan(parentContext gocb.RequestSpanContext, operationName string) gocb.RequestSpan {
+	ctx := context.Background()
+	switch parent := parentContext.(type) {
+	case context.Context:
+		ctx = parent
+	case trace.SpanContext:
+		ctx = trace.ContextWithSpanContext(ctx, parent)
+	}
+
+	kind := trace.SpanKindInternal
+	if operationName == dispatchSpanName {
+		kind = trace.SpanKindClient
+	}
+
+	ctx, span := tracer.tracer.Start(ctx, operationName, trace.WithSpanKind(kind))
+	return NewOpenTelemetryRequestSpan(ctx, span)
+}
+
+// OpenTelemetryRequestSpan is an implementation of the gocb RequestSpan interface that wraps an OpenTelemetry span.
+type OpenTelemetryRequestSpan struct {
+	ctx  context.Context
+	span trace.Span
+}
+
+var _ gocb.RequestSpan = (*OpenTelemetryRequestSpan)(nil)
+
+// NewOpenTelemetryRequestSpan creates a new OpenTelemetryRequestSpan from an OpenTelemetry span and the context
+// that contains it. This can be used to pass an existing OpenTelemetry span to the SDK as a ParentSpan.
+func NewOpenTelemetryRequestSpan(ctx context.Context, span trace.Span) *OpenTelemetryRequestSpan {
+	return &OpenTelemetryRequestSpan{
+		ctx:  ctx,
+		span: span,
+	}
+}
+
+// ContextParentSpan returns the span contained in ctx as a RequestSpan which can be used as the ParentSpan in the
+// SDK options blocks, e.g.
+//
+//	opts := &gocb.GetOptions{Context: ctx, ParentSpan: gocbopentelemetry.ContextParentSpan(ctx)}
+func ContextParentSpan(ctx context.Context) *OpenTelemetryRequestSpan {
+	return NewOpenTelemetryRequestSpan(ctx, trace.SpanFromContext(ctx))
+}
+
+// End completes the span.
+func (span *OpenTelemetryRequestSpan) End() {
+	span.span.End()
+}
+
+// Context returns the RequestSpanContext for this span, which is a context.Context containing the span.
+func (span *OpenTelemetryRequestSpan) Context() gocb.RequestSpanContext {
+	return span.ctx
+}
+
+// Span returns the underlying OpenTelemetry span.
+func (span *OpenTelemetryRequestSpan) Span() trace.Span {
+	return span.span
+}
+
+// SetAttribute adds an attribute to this span.
+func (span *OpenTelemetryRequestSpan) SetAttribute(key string, value interface{}) {
+	span.span.SetAttributes(attributeFromValue(key, value))
+}
+
+// AddEvent adds an event to this span.
+func (span *OpenTelemetryRequestSpan) AddEvent(key string, timestamp time.Time) {
+	span.span.AddEvent(key, trace.WithTimestamp(timestamp))
+}
+
+func attributeFromValue(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int8:
+		return attribute.Int64(key, int64(v))
+	case int16:
+		return attribute.Int64(key, int64(v))
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint:
+		return attribute.Int64(key, int64(v))
+	case uint8:
+		return attribute.Int64(key, int64(v))
+	case uint16:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case uint64:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case time.Duration:
+		return attribute.Int64(key, v.Microseconds())
+	case time.Time:
+		return attribute.String(key, v.Format(time.RFC3339Nano))
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	}
+
+	return attribute.String(key, fmt.Sprintf("%v", value))
+}
@@ -0,0 +1,148 @@
+package gocbopentelemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lissteron/gocb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestTracer() (*OpenTelemetryRequestTracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	return NewOpenTelemetryRequestTracer(provider), exporter
+}
+
+func TestRequestTracerAttributes(t *testing.T) {
+	tracer, exporter := newTestTracer()
+
+	span := tracer.RequestSpan(nil, "get")
+	span.SetAttribute("db.system", "couchbase")
+	span.SetAttribute("db.name", "default")
+	span.SetAttribute("db.couchbase.scope", "_default")
+	span.SetAttribute("db.couchbase.collection", "_default")
+	span.SetAttribute("db.operation", "get")
+	span.SetAttribute("db.couchbase.retries", uint32(2))
+	span.SetAttribute("db.couchbase.server_duration", 150*time.Microsecond)
+	span.AddEvent("retry", time.Now())
+
+	dispatch := tracer.RequestSpan(span.Context(), "dispatch_to_server")
+	dispatch.SetAttribute("net.peer.name", "10.112.0.1")
+	dispatch.SetAttribute("net.peer.port", "11210")
+	dispatch.End()
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	dispatchStub := spans[0]
+	opStub := spans[1]
+
+	assert.Equal(t, "get", opStub.Name)
+	assert.Equal(t, trace.SpanKindInternal, opStub.SpanKind)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("db.system", "couchbase"),
+		attribute.String("db.name", "default"),
+		attribute.String("db.couchbase.scope", "_default"),
+		attribute.String("db.couchbase.collection", "_default"),
+		attribute.String("db.operation", "get"),
+		attribute.Int64("db.couchbase.retries", 2),
+		attribute.Int64("db.couchbase.server_duration", 150),
+	}, opStub.Attributes)
+	require.Len(t, opStub.Events, 1)
+	assert.Equal(t, "retry", opStub.Events[0].Name)
+
+	assert.Equal(t, "dispatch_to_server", dispatchStub.Name)
+	assert.Equal(t, trace.SpanKindClient, dispatchStub.SpanKind)
+	assert.Equal(t, opStub.SpanContext.SpanID(), dispatchStub.Parent.SpanID())
+	assert.Equal(t, opStub.SpanContext.TraceID(), dispatchStub.SpanContext.TraceID())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("net.peer.name", "10.112.0.1"),
+		attribute.String("net.peer.port", "11210"),
+	}, dispatchStub.Attributes)
+}
+
+func TestRequestTracerParentFromContext(t *testing.T) {
+	tracer, exporter := newTestTracer()
+
+	ctx, parent := tracer.tracer.Start(context.Background(), "http_handler")
+
+	span := tracer.RequestSpan(ContextParentSpan(ctx).Context(), "query")
+	span.End()
+
+	ctxSpan := tracer.RequestSpan(ctx, "upsert")
+	ctxSpan.End()
+
+	scSpan := tracer.RequestSpan(parent.SpanContext(), "remove")
+	scSpan.End()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+	for _, stub := range spans[:3] {
+		assert.Equal(t, parent.SpanContext().SpanID(), stub.Parent.SpanID(), stub.Name)
+		assert.Equal(t, parent.SpanContext().TraceID(), stub.SpanContext.TraceID(), stub.Name)
+	}
+}
+
+func TestRequestTracerNoParent(t *testing.T) {
+	tracer, exporter := newTestTracer()
+
+	span := tracer.RequestSpan(nil, "get")
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.False(t, spans[0].Parent.IsValid())
+}
+
+func TestRequestTracerSDKOperation(t *testing.T) {
+	tracer, exporter := newTestTracer()
+
+	// Nothing is listening on this address so the operation times out, its span is created and ended regardless.
+	cluster, err := gocb.Connect("couchbase://127.0.0.1:1", gocb.ClusterOptions{
+		Username: "Administrator",
+		Password: "password",
+		Tracer:   tracer,
+	})
+	require.NoError(t, err)
+	defer cluster.Close(nil)
+
+	collection := cluster.Bucket("travel-sample").Scope("inventory").Collection("airline")
+
+	ctx, parent := tracer.tracer.Start(context.Background(), "http_handler")
+	_, err = collection.Get("airline_10", &gocb.GetOptions{
+		Timeout:    50 * time.Millisecond,
+		Context:    ctx,
+		ParentSpan: ContextParentSpan(ctx),
+	})
+	require.Error(t, err)
+	parent.End()
+
+	var opStub *tracetest.SpanStub
+	spans := exporter.GetSpans()
+	for i := range spans {
+		if spans[i].Name == "get" {
+			opStub = &spans[i]
+		}
+	}
+	require.NotNil(t, opStub, "get span was not exported")
+
+	assert.Equal(t, parent.SpanContext().SpanID(), opStub.Parent.SpanID())
+	assert.Equal(t, parent.SpanContext().TraceID(), opStub.SpanContext.TraceID())
+	assert.Subset(t, opStub.Attributes, []attribute.KeyValue{
+		attribute.String("db.system", "couchbase"),
+		attribute.String("db.name", "travel-sample"),
+		attribute.String("db.couchbase.scope", "inventory"),
+		attribute.String("db.couchbase.collection", "airline"),
+		attribute.String("db.operation", "get"),
+	})
+}
@@ -0,0 +1,301 @@
+// Package gocbprometheus provides a Prometheus implementation of the gocb metrics abstraction. It is a separate module
+// so that applications which don't use Prometheus don't depend on it through gocb. For the same reason the meter is
+// created using gocbprometheus.NewPrometheusMeter(registerer) rather than gocb.NewPrometheusMeter(registry).
+package gocbprometheus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lissteron/gocb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The metric names and tags emitted by the SDK.
+const (
+	meterNameCBOperations               = gocb.MeterNameOperations
+	meterNameCBRetries                  = gocb.MeterNameRetries
+	meterNameCBTimeouts                 = gocb.MeterNameTimeouts
+	meterNameCBCircuitBreakerRejections = gocb.MeterNameCircuitBreakerRejections
+	meterNameCBReconnects               = gocb.MeterNameReconnects
+	meterAttribServiceKey               = gocb.MeterAttribServiceKey
+	meterAttribOperationKey             = gocb.MeterAttribOperationKey
+	meterAttribOutcomeKey               = gocb.MeterAttribOutcomeKey
+	meterAttribBucketNameKey            = gocb.MeterAttribBucketNameKey
+	meterAttribScopeNameKey             = gocb.MeterAttribScopeNameKey
+	meterAttribCollectionNameKey        = gocb.MeterAttribCollectionNameKey
+	meterAttribRetryReasonKey           = gocb.MeterAttribRetryReasonKey
+)
+
+// PrometheusMeterOptions is the set of options available when creating a PrometheusMeter.
+type PrometheusMeterOptions struct {
+	// Namespace is prefixed to the name of every metric, defaults to "couchbase".
+	Namespace string
+
+	// LatencyBuckets are the histogram buckets, in seconds, used for operation latencies.
+	LatencyBuckets []float64
+}
+
+var prometheusDefaultLatencyBuckets = []float64{
+	0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 75,
+}
+
+type prometheusLabel struct {
+	tag   string
+	label string
+}
+
+// prometheusLabels maps the tags emitted by the SDK to Prometheus labels. Prometheus requires every metric with the
+// same name to have the same set of labels, so tags that the SDK does not provide are exposed as empty labels.
+var prometheusLabels = []prometheusLabel{
+	{tag: meterAttribServiceKey, label: "service"},
+	{tag: meterAttribOperationKey, label: "operation"},
+	{tag: meterAttribOutcomeKey, label: "outcome"},
+	{tag: meterAttribBucketNameKey, label: "bucket"},
+	{tag: meterAttribScopeNameKey, label: "scope"},
+	{tag: meterAttribCollectionNameKey, label: "collection"},
+}
+
+// prometheusRetryLabels are the labels of the retries counter, which is the only metric labelled with a retry reason.
+var prometheusRetryLabels = append(prometheusLabels[:len(prometheusLabels):len(prometheusLabels)],
+	prometheusLabel{tag: meterAttribRetryReasonKey, label: "reason"})
+
+func prometheusLabelsFor(name string) []prometheusLabel {
+	if name == meterNameCBRetries {
+		return prometheusRetryLabels
+	}
+
+	return prometheusLabels
+}
+
+func prometheusLabelNames(labels []prometheusLabel) []string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.label
+	}
+
+	return names
+}
+
+// PrometheusMeter is an implementation of the gocb Meter interface which exposes SDK metrics to a Prometheus registry. Operation latencies
+// are exposed as a histogram, in seconds, alongside counters for retries, timeouts, circuit breaker rejections
+// and reconnects.
+type PrometheusMeter struct {
+	registerer prometheus.Registerer
+	namespace  string
+	buckets    []float64
+
+	lock       sync.RWMutex
+	histograms map[string]*prometheus.HistogramVec
+	counters   map[string]*prometheus.CounterVec
+}
+
+var _ gocb.Meter = (*PrometheusMeter)(nil)
+
+// NewPrometheusMeter creates a new PrometheusMeter, registering its metrics with the registerer.
+func NewPrometheusMeter(registerer prometheus.Registerer) (*PrometheusMeter, error) {
+	return NewPrometheusMeterWithOptions(registerer, nil)
+}
+
+// NewPrometheusMeterWithOptions creates a new PrometheusMeter with the provided options, registering its metrics with
+// the registerer.
+func NewPrometheusMeterWithOptions(registerer prometheus.Registerer, opts *PrometheusMeterOptions) (*PrometheusMeter, error) {
+	if registerer == nil {
+		return nil, fmt.Errorf("registerer cannot be nil: %w", gocb.ErrInvalidArgument)
+	}
+	if opts == nil {
+		opts = &PrometheusMeterOptions{}
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "couchbase"
+	}
+
+	buckets := opts.LatencyBuckets
+	if len(buckets) == 0 {
+		buckets = prometheusDefaultLatencyBuckets
+	}
+
+	pm := &PrometheusMeter{
+		registerer: registerer,
+		namespace:  namespace,
+		buckets:    buckets,
+		histograms: make(map[string]*prometheus.HistogramVec),
+		counters:   make(map[string]*prometheus.CounterVec),
+	}
+
+	if _, err := pm.histogram(meterNameCBOperations); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{meterNameCBRetries, meterNameCBTimeouts, meterNameCBCircuitBreakerRejections,
+		meterNameCBReconnects} {
+		if _, err := pm.counter(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return pm, nil
+}
+
+// Counter returns a Counter for the metric with the provided name and tags.
+func (pm *PrometheusMeter) Counter(name string, tags map[string]string) (gocb.Counter, error) {
+	vec, err := pm.counter(name)
+	if err != nil {
+		return nil, err
+	}
+
+	counter, err := vec.GetMetricWithLabelValues(prometheusLabelValues(name, tags)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &prometheusCounter{counter: counter}, nil
+}
+
+// ValueRecorder returns a ValueRecorder for the metric with the provided name and tags.
+func (pm *PrometheusMeter) ValueRecorder(name string, tags map[string]string) (gocb.ValueRecorder, error) {
+	vec, err := pm.histogram(name)
+	if err != nil {
+		return nil, err
+	}
+
+	observer, err := vec.GetMetricWithLabelValues(prometheusLabelValues(name, tags)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &prometheusValueRecorder{observer: observer}, nil
+}
+
+func prometheusLabelValues(name string, tags map[string]string) []string {
+	labels := prometheusLabelsFor(name)
+	values := make([]string, len(labels))
+	for i, l := range labels {
+		values[i] = tags[l.tag]
+	}
+
+	return values
+}
+
+func (pm *PrometheusMeter) histogram(name string) (*prometheus.HistogramVec, error) {
+	pm.lock.RLock()
+	vec, ok := pm.histograms[name]
+	pm.lock.RUnlock()
+	if ok {
+		return vec, nil
+	}
+
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+
+	if vec, ok := pm.histograms[name]; ok {
+		return vec, nil
+	}
+
+	metricName, help := prometheusMetricName(name, "_duration_seconds")
+	vec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: pm.namespace,
+		Name:      metricName,
+		Help:      help,
+		Buckets:   pm.buckets,
+	}, prometheusLabelNames(prometheusLabelsFor(name)))
+	if err := pm.registerer.Register(vec); err != nil {
+		// Allow multiple meters, e.g. for multiple clusters, to share the same registry.
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			return nil, err
+		}
+
+		existing, ok := are.ExistingCollector.(*prometheus.HistogramVec)
+		if !ok {
+			return nil, err
+		}
+		vec = existing
+	}
+
+	pm.histograms[name] = vec
+	return vec, nil
+}
+
+func (pm *PrometheusMeter) counter(name string) (*prometheus.CounterVec, error) {
+	pm.lock.RLock()
+	vec, ok := pm.counters[name]
+	pm.lock.RUnlock()
+	if ok {
+		return vec, nil
+	}
+
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+
+	if vec, ok := pm.counters[name]; ok {
+		return vec, nil
+	}
+
+	metricName, help := prometheusMetricName(name, "_total")
+	vec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: pm.namespace,
+		Name:      metricName,
+		Help:      help,
+	}, prometheusLabelNames(prometheusLabelsFor(name)))
+	if err := pm.registerer.Register(vec); err != nil {
+		// Allow multiple meters, e.g. for multiple clusters, to share the same registry.
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			return nil, err
+		}
+
+		existing, ok := are.ExistingCollector.(*prometheus.CounterVec)
+		if !ok {
+			return nil, err
+		}
+		vec = existing
+	}
+
+	pm.counters[name] = vec
+	return vec, nil
+}
+
+func prometheusMetricName(name, suffix string) (string, string) {
+	switch name {
+	case meterNameCBOperations:
+		return "operation_duration_seconds", "Duration of SDK operations."
+	case meterNameCBRetries:
+		return "operation_retries_total", "Number of times that SDK operations have been retried."
+	case meterNameCBTimeouts:
+		return "operation_timeouts_total", "Number of SDK operations that have timed out."
+	case meterNameCBCircuitBreakerRejections:
+		return "circuit_breaker_rejections_total", "Number of SDK operations rejected by a circuit breaker."
+	case meterNameCBReconnects:
+		return "connection_reconnects_total", "Number of connections which have been reestablished."
+	}
+
+	sanitized := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.TrimPrefix(name, "db.couchbase."))
+
+	return sanitized + suffix, "SDK metric " + name + "."
+}
+
+type prometheusCounter struct {
+	counter prometheus.Counter
+}
+
+func (pc *prometheusCounter) IncrementBy(num uint64) {
+	pc.counter.Add(float64(num))
+}
+
+type prometheusValueRecorder struct {
+	observer prometheus.Observer
+}
+
+// RecordValue records a value, which the SDK provides in microseconds.
+func (pvr *prometheusValueRecorder) RecordValue(val uint64) {
+	pvr.observer.Observe(float64(val) / 1e6)
+}
@@ -0,0 +1,110 @@
+package gocbprometheus
+
+import (
+	"testing"
+
+	"github.com/lissteron/gocb"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gatherPrometheus(t *testing.T, registry *prometheus.Registry) map[string]*dto.MetricFamily {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	out := make(map[string]*dto.MetricFamily)
+	for _, family := range families {
+		out[family.GetName()] = family
+	}
+
+	return out
+}
+
+func TestPrometheusMeterOperations(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	meter, err := NewPrometheusMeter(registry)
+	require.NoError(t, err)
+
+	recorder, err := meter.ValueRecorder(meterNameCBOperations, map[string]string{
+		meterAttribServiceKey:   "kv",
+		meterAttribOperationKey: "get",
+	})
+	require.NoError(t, err)
+	recorder.RecordValue(1500)
+	recorder.RecordValue(500)
+
+	families := gatherPrometheus(t, registry)
+	family, ok := families["couchbase_operation_duration_seconds"]
+	require.True(t, ok)
+	require.Len(t, family.GetMetric(), 1)
+
+	metric := family.GetMetric()[0]
+	labels := make(map[string]string)
+	for _, label := range metric.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	assert.Equal(t, map[string]string{
+		"service":    "kv",
+		"operation":  "get",
+		"outcome":    "",
+		"bucket":     "",
+		"scope":      "",
+		"collection": "",
+	}, labels)
+	assert.Equal(t, uint64(2), metric.GetHistogram().GetSampleCount())
+	assert.InDelta(t, 0.002, metric.GetHistogram().GetSampleSum(), 0.0000001)
+}
+
+func TestPrometheusMeterCounters(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	meter, err := NewPrometheusMeterWithOptions(registry, &PrometheusMeterOptions{Namespace: "app"})
+	require.NoError(t, err)
+
+	tags := map[string]string{meterAttribServiceKey: "query"}
+	for _, name := range []string{meterNameCBRetries, meterNameCBTimeouts, meterNameCBCircuitBreakerRejections,
+		meterNameCBReconnects} {
+		counter, err := meter.Counter(name, tags)
+		require.NoError(t, err)
+		counter.IncrementBy(2)
+	}
+
+	counter, err := meter.Counter("db.couchbase.custom", tags)
+	require.NoError(t, err)
+	counter.IncrementBy(1)
+
+	families := gatherPrometheus(t, registry)
+	for name, expected := range map[string]float64{
+		"app_operation_retries_total":          2,
+		"app_operation_timeouts_total":         2,
+		"app_circuit_breaker_rejections_total": 2,
+		"app_connection_reconnects_total":      2,
+		"app_custom_total":                     1,
+	} {
+		family, ok := families[name]
+		if assert.True(t, ok, name) {
+			assert.Equal(t, expected, family.GetMetric()[0].GetCounter().GetValue(), name)
+		}
+	}
+
+	// A second meter against the same registry should share the already registered metrics.
+	second, err := NewPrometheusMeterWithOptions(registry, &PrometheusMeterOptions{Namespace: "app"})
+	require.NoError(t, err)
+	counter, err = second.Counter(meterNameCBRetries, tags)
+	require.NoError(t, err)
+	counter.IncrementBy(1)
+
+	families = gatherPrometheus(t, registry)
+	for name, family := range families {
+		hasReason := false
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			hasReason = hasReason || label.GetName() == "reason"
+		}
+		assert.Equal(t, name == "app_operation_retries_total", hasReason, name)
+	}
+	assert.Equal(t, float64(3), families["app_operation_retries_total"].GetMetric()[0].GetCounter().GetValue())
+
+	_, err = NewPrometheusMeter(nil)
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+}
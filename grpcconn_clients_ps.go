@@ -0,0 +1,405 @@
+package gocb
+
+import (
+	"context"
+
+	"github.com/couchbase/goprotostellar/genproto/admin_bucket_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_collection_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_query_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_search_v1"
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
+	"github.com/couchbase/goprotostellar/genproto/kv_v1"
+	"github.com/couchbase/goprotostellar/genproto/query_v1"
+	"github.com/couchbase/goprotostellar/genproto/search_v1"
+	"google.golang.org/grpc"
+)
+
+// The service clients of psClientConn pass every request through psInvoke or psNewStream, with the method name
+// that grpc would use for it.
+
+type psKvClient struct {
+	conn   *psClientConn
+	client kv_v1.KvServiceClient
+}
+
+func (c *psClientConn) KvV1() kv_v1.KvServiceClient {
+	return &psKvClient{conn: c, client: c.clients.KvV1()}
+}
+
+func (c *psKvClient) Get(ctx context.Context, in *kv_v1.GetRequest,
+	opts ...grpc.CallOption) (*kv_v1.GetResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Get", in, c.client.Get, opts)
+}
+
+func (c *psKvClient) GetAndTouch(ctx context.Context, in *kv_v1.GetAndTouchRequest,
+	opts ...grpc.CallOption) (*kv_v1.GetAndTouchResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/GetAndTouch", in, c.client.GetAndTouch, opts)
+}
+
+func (c *psKvClient) GetAndLock(ctx context.Context, in *kv_v1.GetAndLockRequest,
+	opts ...grpc.CallOption) (*kv_v1.GetAndLockResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/GetAndLock", in, c.client.GetAndLock, opts)
+}
+
+func (c *psKvClient) Unlock(ctx context.Context, in *kv_v1.UnlockRequest,
+	opts ...grpc.CallOption) (*kv_v1.UnlockResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Unlock", in, c.client.Unlock, opts)
+}
+
+func (c *psKvClient) Touch(ctx context.Context, in *kv_v1.TouchRequest,
+	opts ...grpc.CallOption) (*kv_v1.TouchResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Touch", in, c.client.Touch, opts)
+}
+
+func (c *psKvClient) Exists(ctx context.Context, in *kv_v1.ExistsRequest,
+	opts ...grpc.CallOption) (*kv_v1.ExistsResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Exists", in, c.client.Exists, opts)
+}
+
+func (c *psKvClient) Insert(ctx context.Context, in *kv_v1.InsertRequest,
+	opts ...grpc.CallOption) (*kv_v1.InsertResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Insert", in, c.client.Insert, opts)
+}
+
+func (c *psKvClient) Upsert(ctx context.Context, in *kv_v1.UpsertRequest,
+	opts ...grpc.CallOption) (*kv_v1.UpsertResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Upsert", in, c.client.Upsert, opts)
+}
+
+func (c *psKvClient) Replace(ctx context.Context, in *kv_v1.ReplaceRequest,
+	opts ...grpc.CallOption) (*kv_v1.ReplaceResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Replace", in, c.client.Replace, opts)
+}
+
+func (c *psKvClient) Remove(ctx context.Context, in *kv_v1.RemoveRequest,
+	opts ...grpc.CallOption) (*kv_v1.RemoveResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Remove", in, c.client.Remove, opts)
+}
+
+func (c *psKvClient) Increment(ctx context.Context, in *kv_v1.IncrementRequest,
+	opts ...grpc.CallOption) (*kv_v1.IncrementResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Increment", in, c.client.Increment, opts)
+}
+
+func (c *psKvClient) Decrement(ctx context.Context, in *kv_v1.DecrementRequest,
+	opts ...grpc.CallOption) (*kv_v1.DecrementResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Decrement", in, c.client.Decrement, opts)
+}
+
+func (c *psKvClient) Append(ctx context.Context, in *kv_v1.AppendRequest,
+	opts ...grpc.CallOption) (*kv_v1.AppendResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Append", in, c.client.Append, opts)
+}
+
+func (c *psKvClient) Prepend(ctx context.Context, in *kv_v1.PrependRequest,
+	opts ...grpc.CallOption) (*kv_v1.PrependResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/Prepend", in, c.client.Prepend, opts)
+}
+
+func (c *psKvClient) LookupIn(ctx context.Context, in *kv_v1.LookupInRequest,
+	opts ...grpc.CallOption) (*kv_v1.LookupInResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/LookupIn", in, c.client.LookupIn, opts)
+}
+
+func (c *psKvClient) MutateIn(ctx context.Context, in *kv_v1.MutateInRequest,
+	opts ...grpc.CallOption) (*kv_v1.MutateInResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.kv.v1.KvService/MutateIn", in, c.client.MutateIn, opts)
+}
+
+func (c *psKvClient) GetAllReplicas(ctx context.Context, in *kv_v1.GetAllReplicasRequest,
+	opts ...grpc.CallOption) (kv_v1.KvService_GetAllReplicasClient, error) {
+	return psNewStream(ctx, c.conn, &kv_v1.KvService_ServiceDesc.Streams[0], "/couchbase.kv.v1.KvService/GetAllReplicas", in, c.client.GetAllReplicas,
+		func(stream grpc.ClientStream) kv_v1.KvService_GetAllReplicasClient {
+			return &psKvGetAllReplicasClient{ClientStream: stream}
+		}, opts)
+}
+
+type psKvGetAllReplicasClient struct {
+	grpc.ClientStream
+}
+
+func (x *psKvGetAllReplicasClient) Recv() (*kv_v1.GetAllReplicasResponse, error) {
+	m := new(kv_v1.GetAllReplicasResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type psQueryClient struct {
+	conn   *psClientConn
+	client query_v1.QueryServiceClient
+}
+
+func (c *psClientConn) QueryV1() query_v1.QueryServiceClient {
+	return &psQueryClient{conn: c, client: c.clients.QueryV1()}
+}
+
+func (c *psQueryClient) Query(ctx context.Context, in *query_v1.QueryRequest,
+	opts ...grpc.CallOption) (query_v1.QueryService_QueryClient, error) {
+	return psNewStream(ctx, c.conn, &query_v1.QueryService_ServiceDesc.Streams[0], "/couchbase.query.v1.QueryService/Query", in, c.client.Query,
+		func(stream grpc.ClientStream) query_v1.QueryService_QueryClient {
+			return &psQueryQueryClient{ClientStream: stream}
+		}, opts)
+}
+
+type psQueryQueryClient struct {
+	grpc.ClientStream
+}
+
+func (x *psQueryQueryClient) Recv() (*query_v1.QueryResponse, error) {
+	m := new(query_v1.QueryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type psQueryAdminClient struct {
+	conn   *psClientConn
+	client admin_query_v1.QueryAdminServiceClient
+}
+
+func (c *psClientConn) QueryAdminV1() admin_query_v1.QueryAdminServiceClient {
+	return &psQueryAdminClient{conn: c, client: c.clients.QueryAdminV1()}
+}
+
+func (c *psQueryAdminClient) GetAllIndexes(ctx context.Context, in *admin_query_v1.GetAllIndexesRequest,
+	opts ...grpc.CallOption) (*admin_query_v1.GetAllIndexesResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.query.v1.QueryAdminService/GetAllIndexes", in, c.client.GetAllIndexes, opts)
+}
+
+func (c *psQueryAdminClient) CreatePrimaryIndex(ctx context.Context, in *admin_query_v1.CreatePrimaryIndexRequest,
+	opts ...grpc.CallOption) (*admin_query_v1.CreatePrimaryIndexResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.query.v1.QueryAdminService/CreatePrimaryIndex", in, c.client.CreatePrimaryIndex, opts)
+}
+
+func (c *psQueryAdminClient) CreateIndex(ctx context.Context, in *admin_query_v1.CreateIndexRequest,
+	opts ...grpc.CallOption) (*admin_query_v1.CreateIndexResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.query.v1.QueryAdminService/CreateIndex", in, c.client.CreateIndex, opts)
+}
+
+func (c *psQueryAdminClient) DropPrimaryIndex(ctx context.Context, in *admin_query_v1.DropPrimaryIndexRequest,
+	opts ...grpc.CallOption) (*admin_query_v1.DropPrimaryIndexResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.query.v1.QueryAdminService/DropPrimaryIndex", in, c.client.DropPrimaryIndex, opts)
+}
+
+func (c *psQueryAdminClient) DropIndex(ctx context.Context, in *admin_query_v1.DropIndexRequest,
+	opts ...grpc.CallOption) (*admin_query_v1.DropIndexResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.query.v1.QueryAdminService/DropIndex", in, c.client.DropIndex, opts)
+}
+
+func (c *psQueryAdminClient) BuildDeferredIndexes(ctx context.Context, in *admin_query_v1.BuildDeferredIndexesRequest,
+	opts ...grpc.CallOption) (*admin_query_v1.BuildDeferredIndexesResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.query.v1.QueryAdminService/BuildDeferredIndexes", in, c.client.BuildDeferredIndexes, opts)
+}
+
+func (c *psQueryAdminClient) WaitForIndexOnline(ctx context.Context, in *admin_query_v1.WaitForIndexOnlineRequest,
+	opts ...grpc.CallOption) (*admin_query_v1.WaitForIndexOnlineResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.query.v1.QueryAdminService/WaitForIndexOnline", in, c.client.WaitForIndexOnline, opts)
+}
+
+type psSearchClient struct {
+	conn   *psClientConn
+	client search_v1.SearchServiceClient
+}
+
+func (c *psClientConn) SearchV1() search_v1.SearchServiceClient {
+	return &psSearchClient{conn: c, client: c.clients.SearchV1()}
+}
+
+func (c *psSearchClient) SearchQuery(ctx context.Context, in *search_v1.SearchQueryRequest,
+	opts ...grpc.CallOption) (search_v1.SearchService_SearchQueryClient, error) {
+	return psNewStream(ctx, c.conn, &search_v1.SearchService_ServiceDesc.Streams[0], "/couchbase.search.v1.SearchService/SearchQuery", in, c.client.SearchQuery,
+		func(stream grpc.ClientStream) search_v1.SearchService_SearchQueryClient {
+			return &psSearchQueryClient{ClientStream: stream}
+		}, opts)
+}
+
+type psSearchQueryClient struct {
+	grpc.ClientStream
+}
+
+func (x *psSearchQueryClient) Recv() (*search_v1.SearchQueryResponse, error) {
+	m := new(search_v1.SearchQueryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type psSearchAdminClient struct {
+	conn   *psClientConn
+	client admin_search_v1.SearchAdminServiceClient
+}
+
+func (c *psClientConn) SearchAdminV1() admin_search_v1.SearchAdminServiceClient {
+	return &psSearchAdminClient{conn: c, client: c.clients.SearchAdminV1()}
+}
+
+func (c *psSearchAdminClient) GetIndex(ctx context.Context, in *admin_search_v1.GetIndexRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.GetIndexResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/GetIndex", in, c.client.GetIndex, opts)
+}
+
+func (c *psSearchAdminClient) ListIndexes(ctx context.Context, in *admin_search_v1.ListIndexesRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.ListIndexesResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/ListIndexes", in, c.client.ListIndexes, opts)
+}
+
+func (c *psSearchAdminClient) CreateIndex(ctx context.Context, in *admin_search_v1.CreateIndexRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.CreateIndexResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/CreateIndex", in, c.client.CreateIndex, opts)
+}
+
+func (c *psSearchAdminClient) UpdateIndex(ctx context.Context, in *admin_search_v1.UpdateIndexRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.UpdateIndexResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/UpdateIndex", in, c.client.UpdateIndex, opts)
+}
+
+func (c *psSearchAdminClient) DeleteIndex(ctx context.Context, in *admin_search_v1.DeleteIndexRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.DeleteIndexResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/DeleteIndex", in, c.client.DeleteIndex, opts)
+}
+
+func (c *psSearchAdminClient) AnalyzeDocument(ctx context.Context, in *admin_search_v1.AnalyzeDocumentRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.AnalyzeDocumentResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/AnalyzeDocument", in, c.client.AnalyzeDocument, opts)
+}
+
+func (c *psSearchAdminClient) GetIndexedDocumentsCount(ctx context.Context, in *admin_search_v1.GetIndexedDocumentsCountRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.GetIndexedDocumentsCountResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/GetIndexedDocumentsCount", in, c.client.GetIndexedDocumentsCount, opts)
+}
+
+func (c *psSearchAdminClient) PauseIndexIngest(ctx context.Context, in *admin_search_v1.PauseIndexIngestRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.PauseIndexIngestResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/PauseIndexIngest", in, c.client.PauseIndexIngest, opts)
+}
+
+func (c *psSearchAdminClient) ResumeIndexIngest(ctx context.Context, in *admin_search_v1.ResumeIndexIngestRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.ResumeIndexIngestResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/ResumeIndexIngest", in, c.client.ResumeIndexIngest, opts)
+}
+
+func (c *psSearchAdminClient) AllowIndexQuerying(ctx context.Context, in *admin_search_v1.AllowIndexQueryingRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.AllowIndexQueryingResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/AllowIndexQuerying", in, c.client.AllowIndexQuerying, opts)
+}
+
+func (c *psSearchAdminClient) DisallowIndexQuerying(ctx context.Context, in *admin_search_v1.DisallowIndexQueryingRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.DisallowIndexQueryingResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/DisallowIndexQuerying", in, c.client.DisallowIndexQuerying, opts)
+}
+
+func (c *psSearchAdminClient) FreezeIndexPlan(ctx context.Context, in *admin_search_v1.FreezeIndexPlanRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.FreezeIndexPlanResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/FreezeIndexPlan", in, c.client.FreezeIndexPlan, opts)
+}
+
+func (c *psSearchAdminClient) UnfreezeIndexPlan(ctx context.Context, in *admin_search_v1.UnfreezeIndexPlanRequest,
+	opts ...grpc.CallOption) (*admin_search_v1.UnfreezeIndexPlanResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.search.v1.SearchAdminService/UnfreezeIndexPlan", in, c.client.UnfreezeIndexPlan, opts)
+}
+
+type psAnalyticsClient struct {
+	conn   *psClientConn
+	client analytics_v1.AnalyticsServiceClient
+}
+
+func (c *psClientConn) AnalyticsV1() analytics_v1.AnalyticsServiceClient {
+	return &psAnalyticsClient{conn: c, client: c.clients.AnalyticsV1()}
+}
+
+func (c *psAnalyticsClient) AnalyticsQuery(ctx context.Context, in *analytics_v1.AnalyticsQueryRequest,
+	opts ...grpc.CallOption) (analytics_v1.AnalyticsService_AnalyticsQueryClient, error) {
+	return psNewStream(ctx, c.conn, &analytics_v1.AnalyticsService_ServiceDesc.Streams[0], "/couchbase.analytics.v1.AnalyticsService/AnalyticsQuery", in, c.client.AnalyticsQuery,
+		func(stream grpc.ClientStream) analytics_v1.AnalyticsService_AnalyticsQueryClient {
+			return &psAnalyticsQueryClient{ClientStream: stream}
+		}, opts)
+}
+
+type psAnalyticsQueryClient struct {
+	grpc.ClientStream
+}
+
+func (x *psAnalyticsQueryClient) Recv() (*analytics_v1.AnalyticsQueryResponse, error) {
+	m := new(analytics_v1.AnalyticsQueryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type psCollectionAdminClient struct {
+	conn   *psClientConn
+	client admin_collection_v1.CollectionAdminServiceClient
+}
+
+func (c *psClientConn) CollectionV1() admin_collection_v1.CollectionAdminServiceClient {
+	return &psCollectionAdminClient{conn: c, client: c.clients.CollectionV1()}
+}
+
+func (c *psCollectionAdminClient) ListCollections(ctx context.Context, in *admin_collection_v1.ListCollectionsRequest,
+	opts ...grpc.CallOption) (*admin_collection_v1.ListCollectionsResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.collection.v1.CollectionAdminService/ListCollections", in, c.client.ListCollections, opts)
+}
+
+func (c *psCollectionAdminClient) CreateScope(ctx context.Context, in *admin_collection_v1.CreateScopeRequest,
+	opts ...grpc.CallOption) (*admin_collection_v1.CreateScopeResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.collection.v1.CollectionAdminService/CreateScope", in, c.client.CreateScope, opts)
+}
+
+func (c *psCollectionAdminClient) DeleteScope(ctx context.Context, in *admin_collection_v1.DeleteScopeRequest,
+	opts ...grpc.CallOption) (*admin_collection_v1.DeleteScopeResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.collection.v1.CollectionAdminService/DeleteScope", in, c.client.DeleteScope, opts)
+}
+
+func (c *psCollectionAdminClient) CreateCollection(ctx context.Context, in *admin_collection_v1.CreateCollectionRequest,
+	opts ...grpc.CallOption) (*admin_collection_v1.CreateCollectionResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.collection.v1.CollectionAdminService/CreateCollection", in, c.client.CreateCollection, opts)
+}
+
+func (c *psCollectionAdminClient) UpdateCollection(ctx context.Context, in *admin_collection_v1.UpdateCollectionRequest,
+	opts ...grpc.CallOption) (*admin_collection_v1.UpdateCollectionResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.collection.v1.CollectionAdminService/UpdateCollection", in, c.client.UpdateCollection, opts)
+}
+
+func (c *psCollectionAdminClient) DeleteCollection(ctx context.Context, in *admin_collection_v1.DeleteCollectionRequest,
+	opts ...grpc.CallOption) (*admin_collection_v1.DeleteCollectionResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.collection.v1.CollectionAdminService/DeleteCollection", in, c.client.DeleteCollection, opts)
+}
+
+type psBucketAdminClient struct {
+	conn   *psClientConn
+	client admin_bucket_v1.BucketAdminServiceClient
+}
+
+func (c *psClientConn) BucketV1() admin_bucket_v1.BucketAdminServiceClient {
+	return &psBucketAdminClient{conn: c, client: c.clients.BucketV1()}
+}
+
+func (c *psBucketAdminClient) ListBuckets(ctx context.Context, in *admin_bucket_v1.ListBucketsRequest,
+	opts ...grpc.CallOption) (*admin_bucket_v1.ListBucketsResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.bucket.v1.BucketAdminService/ListBuckets", in, c.client.ListBuckets, opts)
+}
+
+func (c *psBucketAdminClient) CreateBucket(ctx context.Context, in *admin_bucket_v1.CreateBucketRequest,
+	opts ...grpc.CallOption) (*admin_bucket_v1.CreateBucketResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.bucket.v1.BucketAdminService/CreateBucket", in, c.client.CreateBucket, opts)
+}
+
+func (c *psBucketAdminClient) UpdateBucket(ctx context.Context, in *admin_bucket_v1.UpdateBucketRequest,
+	opts ...grpc.CallOption) (*admin_bucket_v1.UpdateBucketResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.bucket.v1.BucketAdminService/UpdateBucket", in, c.client.UpdateBucket, opts)
+}
+
+func (c *psBucketAdminClient) DeleteBucket(ctx context.Context, in *admin_bucket_v1.DeleteBucketRequest,
+	opts ...grpc.CallOption) (*admin_bucket_v1.DeleteBucketResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.bucket.v1.BucketAdminService/DeleteBucket", in, c.client.DeleteBucket, opts)
+}
+
+func (c *psBucketAdminClient) FlushBucket(ctx context.Context, in *admin_bucket_v1.FlushBucketRequest,
+	opts ...grpc.CallOption) (*admin_bucket_v1.FlushBucketResponse, error) {
+	return psInvoke(ctx, c.conn, "/couchbase.admin.bucket.v1.BucketAdminService/FlushBucket", in, c.client.FlushBucket, opts)
+}
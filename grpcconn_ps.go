@@ -0,0 +1,228 @@
+package gocb
+
+import (
+	"context"
+
+	"github.com/couchbase/goprotostellar/genproto/admin_bucket_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_collection_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_query_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_search_v1"
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
+	"github.com/couchbase/goprotostellar/genproto/kv_v1"
+	"github.com/couchbase/goprotostellar/genproto/query_v1"
+	"github.com/couchbase/goprotostellar/genproto/search_v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor for use with ProtostellarConfig
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/protobuf/proto"
+)
+
+// psServiceClients is the set of PS service clients used by the providers, it is satisfied by both the routing client
+// and psClientConn.
+type psServiceClients interface {
+	KvV1() kv_v1.KvServiceClient
+	QueryV1() query_v1.QueryServiceClient
+	QueryAdminV1() admin_query_v1.QueryAdminServiceClient
+	SearchV1() search_v1.SearchServiceClient
+	SearchAdminV1() admin_search_v1.SearchAdminServiceClient
+	AnalyticsV1() analytics_v1.AnalyticsServiceClient
+	CollectionV1() admin_collection_v1.CollectionAdminServiceClient
+	BucketV1() admin_bucket_v1.BucketAdminServiceClient
+}
+
+// psPlaceholderResolverBuilder builds resolvers which never resolve any addresses. grpc only leaves the idle state once
+// the resolver has provided addresses to connect to, so a connection using them stays idle without ever connecting.
+type psPlaceholderResolverBuilder struct{}
+
+func (psPlaceholderResolverBuilder) Build(resolver.Target, resolver.ClientConn,
+	resolver.BuildOptions) (resolver.Resolver, error) {
+	return psPlaceholderResolver{}, nil
+}
+
+// Scheme returns the scheme which grpc uses for targets without one, such as the host and port of the cluster.
+func (psPlaceholderResolverBuilder) Scheme() string {
+	return "passthrough"
+}
+
+type psPlaceholderResolver struct{}
+
+func (psPlaceholderResolver) ResolveNow(resolver.ResolveNowOptions) {}
+func (psPlaceholderResolver) Close()                                {}
+
+// psClientConn layers the call options and interceptors from ProtostellarConfig over the routing client. The routing
+// client dials and owns the underlying grpc connections, so these cannot be applied as dial options. Instead each
+// service client is wrapped so that its requests pass through the interceptors, with the routing client making the
+// request once they have all called their invoker.
+type psClientConn struct {
+	clients  psServiceClients
+	callOpts []grpc.CallOption
+	unary    grpc.UnaryClientInterceptor
+	stream   grpc.StreamClientInterceptor
+
+	// cc is passed to the interceptors, which expect a connection to describe the target of the request. It never
+	// leaves the idle state, requests are always sent over the routing client's connections.
+	cc *grpc.ClientConn
+}
+
+var _ psServiceClients = &psClientConn{}
+
+func newPsClientConn(clients psServiceClients, target string, config ProtostellarConfig) (*psClientConn, error) {
+	var callOpts []grpc.CallOption
+	if config.MaxSendMessageSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(config.MaxSendMessageSize))
+	}
+	if config.MaxRecvMessageSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(config.MaxRecvMessageSize))
+	}
+	if config.Compressor != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(config.Compressor))
+	}
+	callOpts = append(callOpts, config.CallOptions...)
+
+	cc, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithResolvers(psPlaceholderResolverBuilder{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &psClientConn{
+		clients:  clients,
+		callOpts: callOpts,
+		unary:    chainPsUnaryInterceptors(config.UnaryInterceptors),
+		stream:   chainPsStreamInterceptors(config.StreamInterceptors),
+		cc:       cc,
+	}, nil
+}
+
+// psClientConnRequired returns whether any of the options in the config need psClientConn to be applied.
+func psClientConnRequired(config ProtostellarConfig) bool {
+	return config.MaxSendMessageSize > 0 || config.MaxRecvMessageSize > 0 || config.Compressor != "" ||
+		len(config.CallOptions) > 0 || len(config.UnaryInterceptors) > 0 || len(config.StreamInterceptors) > 0
+}
+
+func (c *psClientConn) Close() error {
+	return c.cc.Close()
+}
+
+func (c *psClientConn) withCallOptions(opts []grpc.CallOption) []grpc.CallOption {
+	if len(c.callOpts) == 0 {
+		return opts
+	}
+
+	return append(append(make([]grpc.CallOption, 0, len(c.callOpts)+len(opts)), c.callOpts...), opts...)
+}
+
+// chainPsUnaryInterceptors combines the interceptors into one which calls each of them in order, as grpc does for the
+// interceptors of a connection.
+func chainPsUnaryInterceptors(interceptors []grpc.UnaryClientInterceptor) grpc.UnaryClientInterceptor {
+	switch len(interceptors) {
+	case 0:
+		return nil
+	case 1:
+		return interceptors[0]
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return interceptors[0](ctx, method, req, reply, cc, psChainedUnaryInvoker(interceptors, 0, invoker), opts...)
+	}
+}
+
+func psChainedUnaryInvoker(interceptors []grpc.UnaryClientInterceptor, curr int,
+	invoker grpc.UnaryInvoker) grpc.UnaryInvoker {
+	if curr == len(interceptors)-1 {
+		return invoker
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		opts ...grpc.CallOption) error {
+		return interceptors[curr+1](ctx, method, req, reply, cc, psChainedUnaryInvoker(interceptors, curr+1, invoker),
+			opts...)
+	}
+}
+
+func chainPsStreamInterceptors(interceptors []grpc.StreamClientInterceptor) grpc.StreamClientInterceptor {
+	switch len(interceptors) {
+	case 0:
+		return nil
+	case 1:
+		return interceptors[0]
+	}
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return interceptors[0](ctx, desc, cc, method, psChainedStreamer(interceptors, 0, streamer), opts...)
+	}
+}
+
+func psChainedStreamer(interceptors []grpc.StreamClientInterceptor, curr int, streamer grpc.Streamer) grpc.Streamer {
+	if curr == len(interceptors)-1 {
+		return streamer
+	}
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return interceptors[curr+1](ctx, desc, cc, method, psChainedStreamer(interceptors, curr+1, streamer), opts...)
+	}
+}
+
+// psInvoke sends a unary request using call, the routing client method for it, once the interceptors have run. As with
+// grpc the response is copied into the reply seen by the interceptors, and that reply is what is returned.
+func psInvoke[Req, Res any](ctx context.Context, c *psClientConn, method string, req *Req,
+	call func(context.Context, *Req, ...grpc.CallOption) (*Res, error), opts []grpc.CallOption) (*Res, error) {
+	opts = c.withCallOptions(opts)
+	if c.unary == nil {
+		return call(ctx, req, opts...)
+	}
+
+	reply := new(Res)
+	err := c.unary(ctx, method, req, reply, c.cc, func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		res, err := call(ctx, req.(*Req), opts...)
+		if err != nil {
+			return err
+		}
+
+		proto.Merge(reply.(proto.Message), interface{}(res).(proto.Message))
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// psNewStream opens a server stream using call, the routing client method for it, once the interceptors have run. If
+// an interceptor replaces the stream then wrap is used to read the responses through it.
+func psNewStream[Req any, Stream grpc.ClientStream](ctx context.Context, c *psClientConn, desc *grpc.StreamDesc,
+	method string, req *Req, call func(context.Context, *Req, ...grpc.CallOption) (Stream, error),
+	wrap func(grpc.ClientStream) Stream, opts []grpc.CallOption) (Stream, error) {
+	opts = c.withCallOptions(opts)
+	if c.stream == nil {
+		return call(ctx, req, opts...)
+	}
+
+	stream, err := c.stream(ctx, desc, c.cc, method, func(ctx context.Context, desc *grpc.StreamDesc,
+		cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := call(ctx, req, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return stream, nil
+	}, opts...)
+	if err != nil {
+		var zero Stream
+		return zero, err
+	}
+
+	if typed, ok := stream.(Stream); ok {
+		return typed, nil
+	}
+
+	return wrap(stream), nil
+}
@@ -0,0 +1,248 @@
+package gocb
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/goprotostellar/genproto/admin_bucket_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_collection_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_query_v1"
+	"github.com/couchbase/goprotostellar/genproto/admin_search_v1"
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
+	"github.com/couchbase/goprotostellar/genproto/kv_v1"
+	"github.com/couchbase/goprotostellar/genproto/query_v1"
+	"github.com/couchbase/goprotostellar/genproto/search_v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakePsServiceClients struct {
+	kv        kv_v1.KvServiceClient
+	analytics analytics_v1.AnalyticsServiceClient
+	bucket    admin_bucket_v1.BucketAdminServiceClient
+}
+
+func (c *fakePsServiceClients) KvV1() kv_v1.KvServiceClient {
+	return c.kv
+}
+
+func (c *fakePsServiceClients) QueryV1() query_v1.QueryServiceClient {
+	return nil
+}
+
+func (c *fakePsServiceClients) QueryAdminV1() admin_query_v1.QueryAdminServiceClient {
+	return nil
+}
+
+func (c *fakePsServiceClients) SearchV1() search_v1.SearchServiceClient {
+	return nil
+}
+
+func (c *fakePsServiceClients) SearchAdminV1() admin_search_v1.SearchAdminServiceClient {
+	return nil
+}
+
+func (c *fakePsServiceClients) AnalyticsV1() analytics_v1.AnalyticsServiceClient {
+	return c.analytics
+}
+
+func (c *fakePsServiceClients) CollectionV1() admin_collection_v1.CollectionAdminServiceClient {
+	return nil
+}
+
+func (c *fakePsServiceClients) BucketV1() admin_bucket_v1.BucketAdminServiceClient {
+	return c.bucket
+}
+
+type fakePsInterceptedKvClient struct {
+	kv_v1.KvServiceClient
+
+	md   metadata.MD
+	opts []grpc.CallOption
+}
+
+func (c *fakePsInterceptedKvClient) Get(ctx context.Context, in *kv_v1.GetRequest,
+	opts ...grpc.CallOption) (*kv_v1.GetResponse, error) {
+	c.md, _ = metadata.FromOutgoingContext(ctx)
+	c.opts = opts
+	if in.Key == "missing" {
+		return nil, status.Error(codes.NotFound, "document not found")
+	}
+
+	return &kv_v1.GetResponse{
+		Cas:     5,
+		Content: &kv_v1.GetResponse_ContentUncompressed{ContentUncompressed: []byte(`{"a":1}`)},
+	}, nil
+}
+
+type fakePsKeepAliveKvClient struct {
+	kv_v1.KvServiceClient
+
+	calls uint32
+}
+
+func (c *fakePsKeepAliveKvClient) Exists(_ context.Context, in *kv_v1.ExistsRequest,
+	_ ...grpc.CallOption) (*kv_v1.ExistsResponse, error) {
+	atomic.AddUint32(&c.calls, 1)
+	if in.BucketName == "" {
+		return nil, status.Error(codes.InvalidArgument, "bucket name is required")
+	}
+	return &kv_v1.ExistsResponse{}, nil
+}
+
+func (suite *UnitTestSuite) TestPsClientConnInterceptors() {
+	kv := &fakePsInterceptedKvClient{}
+	analytics := &fakePsAnalyticsClient{
+		stream: &fakePsAnalyticsStream{
+			responses: []*analytics_v1.AnalyticsQueryResponse{{Rows: [][]byte{[]byte(`1`)}}},
+		},
+	}
+
+	var calls []string
+	var targets []string
+	var replyCas []uint64
+	config := ProtostellarConfig{
+		MaxRecvMessageSize: 1024,
+		Compressor:         "gzip",
+		UnaryInterceptors: []grpc.UnaryClientInterceptor{
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+				invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				calls = append(calls, "first "+method)
+				targets = append(targets, cc.Target())
+				err := invoker(metadata.AppendToOutgoingContext(ctx, "x-mesh", "a"), method, req, reply, cc, opts...)
+				replyCas = append(replyCas, reply.(*kv_v1.GetResponse).Cas)
+				return err
+			},
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+				invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				calls = append(calls, "second "+method)
+				if req.(*kv_v1.GetRequest).Key == "cached" {
+					reply.(*kv_v1.GetResponse).Cas = 7
+					return nil
+				}
+				return invoker(ctx, method, req, reply, cc, opts...)
+			},
+		},
+		StreamInterceptors: []grpc.StreamClientInterceptor{
+			func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+				streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				calls = append(calls, "stream "+method)
+				targets = append(targets, cc.Target())
+				stream, err := streamer(ctx, desc, cc, method, opts...)
+				if err != nil {
+					return nil, err
+				}
+				return &countingPsClientStream{ClientStream: stream, calls: &calls}, nil
+			},
+		},
+	}
+	suite.Require().True(psClientConnRequired(config))
+	suite.Assert().False(psClientConnRequired(ProtostellarConfig{PoolSize: 4}))
+
+	conn, err := newPsClientConn(&fakePsServiceClients{kv: kv, analytics: analytics}, "10.0.0.1:18098", config)
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	res, err := conn.KvV1().Get(context.Background(), &kv_v1.GetRequest{Key: "key"})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(uint64(5), res.Cas)
+	suite.Assert().Equal([]byte(`{"a":1}`), res.GetContentUncompressed())
+	suite.Assert().Equal([]string{"a"}, kv.md.Get("x-mesh"))
+	suite.Assert().Len(kv.opts, 2)
+	suite.Assert().Equal([]string{
+		"first /couchbase.kv.v1.KvService/Get",
+		"second /couchbase.kv.v1.KvService/Get",
+	}, calls)
+	suite.Assert().Equal([]string{"10.0.0.1:18098"}, targets)
+	// The response is visible to the interceptors once the invoker has returned.
+	suite.Assert().Equal([]uint64{5}, replyCas)
+
+	_, err = conn.KvV1().Get(context.Background(), &kv_v1.GetRequest{Key: "missing"})
+	suite.Assert().Equal(codes.NotFound, status.Code(err))
+
+	// An interceptor can respond itself by filling in the reply.
+	res, err = conn.KvV1().Get(context.Background(), &kv_v1.GetRequest{Key: "cached"})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(uint64(7), res.Cas)
+	suite.Assert().Equal([]uint64{5, 0, 7}, replyCas)
+
+	calls = nil
+	stream, err := conn.AnalyticsV1().AnalyticsQuery(context.Background(), &analytics_v1.AnalyticsQueryRequest{
+		Statement: "SELECT 1",
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Equal("SELECT 1", analytics.req.Statement)
+
+	// Responses are read through the stream returned by the interceptor.
+	row, err := stream.Recv()
+	suite.Require().NoError(err)
+	suite.Assert().Equal([][]byte{[]byte(`1`)}, row.Rows)
+	suite.Assert().Equal([]string{
+		"stream /couchbase.analytics.v1.AnalyticsService/AnalyticsQuery",
+		"recv",
+	}, calls)
+}
+
+type countingPsClientStream struct {
+	grpc.ClientStream
+
+	calls *[]string
+}
+
+func (s *countingPsClientStream) RecvMsg(m interface{}) error {
+	*s.calls = append(*s.calls, "recv")
+	return s.ClientStream.RecvMsg(m)
+}
+
+func (suite *UnitTestSuite) TestPsClientConnPlaceholderStaysIdle() {
+	conn, err := newPsClientConn(&fakePsServiceClients{}, "localhost:18098", ProtostellarConfig{
+		UnaryInterceptors: []grpc.UnaryClientInterceptor{
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+				invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				return invoker(ctx, method, req, reply, cc, opts...)
+			},
+		},
+	})
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	// The placeholder must never try to connect, which would leave it in the transient failure state.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	suite.Assert().False(conn.cc.WaitForStateChange(ctx, connectivity.Idle))
+	suite.Assert().Equal(connectivity.Idle, conn.cc.GetState())
+	suite.Assert().Equal("localhost:18098", conn.cc.Target())
+}
+
+func (suite *UnitTestSuite) TestPsClientConnWithoutInterceptors() {
+	kv := &fakePsInterceptedKvClient{}
+	conn, err := newPsClientConn(&fakePsServiceClients{kv: kv}, "10.0.0.1:18098", ProtostellarConfig{
+		MaxSendMessageSize: 1024,
+	})
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	res, err := conn.KvV1().Get(context.Background(), &kv_v1.GetRequest{Key: "key"})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(uint64(5), res.Cas)
+	suite.Assert().Len(kv.opts, 1)
+}
+
+func (suite *UnitTestSuite) TestPsKeepAlive() {
+	client := &fakePsKeepAliveKvClient{}
+	keepAlive := newPsKeepAlive(client, 3, time.Second)
+
+	keepAlive.poll()
+	suite.Assert().Equal(uint32(3), atomic.LoadUint32(&client.calls))
+
+	monitor := newConnectionMonitor(time.Millisecond, newPsKeepAlive(client, 0, time.Second).poll)
+	monitor.start()
+	suite.Require().Eventually(func() bool {
+		return atomic.LoadUint32(&client.calls) > 4
+	}, time.Second, time.Millisecond)
+	monitor.stop()
+}
@@ -0,0 +1,517 @@
+package gocb
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultHealthMonitorInterval = 10 * time.Second
+
+// ServiceHealth describes the health of a service, as observed by a HealthMonitor.
+// VOLATILE: This API is subject to change at any time.
+type ServiceHealth uint
+
+const (
+	// ServiceHealthUnknown indicates that the service has not yet been checked.
+	ServiceHealthUnknown ServiceHealth = iota
+
+	// ServiceHealthHealthy indicates that every endpoint for the service responded to the last ping.
+	ServiceHealthHealthy
+
+	// ServiceHealthDegraded indicates that some, but not all, endpoints for the service responded to the last ping.
+	ServiceHealthDegraded
+
+	// ServiceHealthUnavailable indicates that no endpoints for the service responded to the last ping.
+	ServiceHealthUnavailable
+)
+
+// String returns the string representation of the service health.
+func (h ServiceHealth) String() string {
+	switch h {
+	case ServiceHealthHealthy:
+		return "healthy"
+	case ServiceHealthDegraded:
+		return "degraded"
+	case ServiceHealthUnavailable:
+		return "unavailable"
+	}
+	return "unknown"
+}
+
+// HealthEndpointEvent is passed to the HealthMonitor endpoint callbacks when an endpoint changes state.
+// VOLATILE: This API is subject to change at any time.
+type HealthEndpointEvent struct {
+	Service ServiceType
+	// Bucket is the bucket that the endpoint was pinged for, or empty for cluster level pings.
+	Bucket   string
+	Endpoint EndpointPingReport
+}
+
+// HealthServiceEvent is passed to the HealthMonitor service callbacks when a service changes health.
+// VOLATILE: This API is subject to change at any time.
+type HealthServiceEvent struct {
+	Service ServiceType
+	// Bucket is the bucket that the service was pinged for, or empty for cluster level pings.
+	Bucket   string
+	Previous ServiceHealth
+	Current  ServiceHealth
+}
+
+// HealthMonitorOptions is the set of options available when creating a HealthMonitor.
+// VOLATILE: This API is subject to change at any time.
+type HealthMonitorOptions struct {
+	// Interval is how often the monitor pings the cluster, defaults to 10 seconds.
+	Interval time.Duration
+
+	// Timeout is the timeout applied to each ping, defaults to the cluster level timeout for each service.
+	Timeout time.Duration
+
+	// ServiceTypes are the services pinged at the cluster level, defaults to all services.
+	ServiceTypes []ServiceType
+
+	// RequiredServices are the services which must have at least one responsive endpoint for the monitor to report
+	// itself as ready.
+	RequiredServices []ServiceType
+
+	// RequiredBuckets are the buckets whose key-value endpoints are pinged, and which must have at least one
+	// responsive endpoint for the monitor to report itself as ready.
+	RequiredBuckets []string
+
+	// OnEndpointDown is called when an endpoint stops responding to pings.
+	OnEndpointDown func(HealthEndpointEvent)
+
+	// OnEndpointUp is called when an endpoint which was not responding to pings starts responding again.
+	OnEndpointUp func(HealthEndpointEvent)
+
+	// OnServiceDegraded is called when a service which was healthy becomes degraded or unavailable, or when a service
+	// is first observed to not be healthy.
+	OnServiceDegraded func(HealthServiceEvent)
+
+	// OnServiceRecovered is called when a service which was degraded or unavailable becomes healthy.
+	OnServiceRecovered func(HealthServiceEvent)
+}
+
+type healthServiceKey struct {
+	service ServiceType
+	bucket  string
+}
+
+type healthEndpointKey struct {
+	service ServiceType
+	bucket  string
+	remote  string
+}
+
+// HealthMonitor periodically pings the cluster, tracking the state of each service and endpoint, and exposes the
+// result as Kubernetes style liveness and readiness checks.
+// VOLATILE: This API is subject to change at any time.
+type HealthMonitor struct {
+	opts  HealthMonitorOptions
+	ping  func(bucket string, opts *PingOptions) (*PingResult, error)
+	clock func() time.Time
+
+	lock      sync.Mutex
+	services  map[healthServiceKey]ServiceHealth
+	endpoints map[healthEndpointKey]EndpointPingReport
+	pingErrs  map[string]error
+	lastCheck time.Time
+	started   bool
+	closed    bool
+
+	loop *connectionMonitor
+}
+
+// HealthMonitor creates a new HealthMonitor for this cluster. The monitor does not ping the cluster until Start is
+// called.
+// VOLATILE: This API is subject to change at any time.
+func (c *Cluster) HealthMonitor(opts *HealthMonitorOptions) (*HealthMonitor, error) {
+	if opts == nil {
+		opts = &HealthMonitorOptions{}
+	}
+
+	buckets := make(map[string]*Bucket, len(opts.RequiredBuckets))
+	for _, name := range opts.RequiredBuckets {
+		buckets[name] = c.Bucket(name)
+	}
+
+	return newHealthMonitor(opts, func(bucket string, pingOpts *PingOptions) (*PingResult, error) {
+		if bucket == "" {
+			return c.Ping(pingOpts)
+		}
+
+		return buckets[bucket].Ping(pingOpts)
+	})
+}
+
+func newHealthMonitor(opts *HealthMonitorOptions, ping func(string, *PingOptions) (*PingResult, error)) (*HealthMonitor, error) {
+	if opts.Interval < 0 {
+		return nil, makeInvalidArgumentsError("interval cannot be negative")
+	}
+	if opts.Timeout < 0 {
+		return nil, makeInvalidArgumentsError("timeout cannot be negative")
+	}
+
+	hm := &HealthMonitor{
+		opts:      *opts,
+		ping:      ping,
+		clock:     time.Now,
+		services:  make(map[healthServiceKey]ServiceHealth),
+		endpoints: make(map[healthEndpointKey]EndpointPingReport),
+		pingErrs:  make(map[string]error),
+	}
+	if hm.opts.Interval == 0 {
+		hm.opts.Interval = defaultHealthMonitorInterval
+	}
+
+	// Required services must be pinged, even when the user has restricted the pinged services.
+	if len(hm.opts.ServiceTypes) > 0 {
+		serviceTypes := append([]ServiceType{}, hm.opts.ServiceTypes...)
+		for _, required := range hm.opts.RequiredServices {
+			if !containsServiceType(serviceTypes, required) {
+				serviceTypes = append(serviceTypes, required)
+			}
+		}
+		hm.opts.ServiceTypes = serviceTypes
+	}
+
+	return hm, nil
+}
+
+func containsServiceType(services []ServiceType, service ServiceType) bool {
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// Start performs an initial health check, and then continues to check the health of the cluster in the background.
+func (hm *HealthMonitor) Start() {
+	hm.lock.Lock()
+	if hm.started || hm.closed {
+		hm.lock.Unlock()
+		return
+	}
+	hm.started = true
+	hm.loop = newConnectionMonitor(hm.opts.Interval, hm.Check)
+	hm.lock.Unlock()
+
+	hm.Check()
+
+	hm.lock.Lock()
+	defer hm.lock.Unlock()
+	if !hm.closed {
+		hm.loop.start()
+	}
+}
+
+// Close stops the monitor from checking the health of the cluster.
+func (hm *HealthMonitor) Close() {
+	hm.lock.Lock()
+	if hm.closed {
+		hm.lock.Unlock()
+		return
+	}
+	hm.closed = true
+	loop := hm.loop
+	hm.lock.Unlock()
+
+	loop.stop()
+}
+
+// Check pings the cluster, and any required buckets, and updates the state of the monitor.
+func (hm *HealthMonitor) Check() {
+	targets := append([]string{""}, hm.opts.RequiredBuckets...)
+
+	results := make(map[string]*PingResult, len(targets))
+	errs := make(map[string]error, len(targets))
+	for _, bucket := range targets {
+		opts := &PingOptions{
+			ServiceTypes: hm.opts.ServiceTypes,
+			Timeout:      hm.opts.Timeout,
+		}
+		if bucket != "" {
+			opts.ServiceTypes = []ServiceType{ServiceTypeKeyValue}
+		}
+
+		res, err := hm.ping(bucket, opts)
+		if err != nil {
+			logDebugfWithFields([]LogField{
+				{Key: LogFieldComponent, Value: "health"},
+				{Key: LogFieldBucket, Value: bucket},
+				{Key: LogFieldError, Value: err},
+			}, "Health check ping failed: %v", err)
+			errs[bucket] = err
+			continue
+		}
+		results[bucket] = res
+	}
+
+	var endpointEvents []func()
+	var serviceEvents []func()
+
+	hm.lock.Lock()
+	endpoints := make(map[healthEndpointKey]EndpointPingReport)
+	services := make(map[healthServiceKey]ServiceHealth)
+
+	for _, bucket := range targets {
+		res, ok := results[bucket]
+		if !ok {
+			// The ping itself failed so we treat every endpoint and service that we knew about for this target as
+			// unavailable.
+			for key, prev := range hm.endpoints {
+				if key.bucket != bucket {
+					continue
+				}
+				prev.State = PingStateError
+				prev.Error = errs[bucket].Error()
+				endpoints[key] = prev
+			}
+			for key := range hm.services {
+				if key.bucket == bucket {
+					services[key] = ServiceHealthUnavailable
+				}
+			}
+			if bucket == "" {
+				for _, service := range hm.opts.RequiredServices {
+					services[healthServiceKey{service: service}] = ServiceHealthUnavailable
+				}
+			} else {
+				services[healthServiceKey{service: ServiceTypeKeyValue, bucket: bucket}] = ServiceHealthUnavailable
+			}
+			continue
+		}
+
+		for service, reports := range res.Services {
+			okCount := 0
+			for _, report := range reports {
+				endpoints[healthEndpointKey{service: service, bucket: bucket, remote: report.Remote}] = report
+				if report.State == PingStateOk {
+					okCount++
+				}
+			}
+
+			health := ServiceHealthHealthy
+			if okCount == 0 {
+				health = ServiceHealthUnavailable
+			} else if okCount < len(reports) {
+				health = ServiceHealthDegraded
+			}
+			services[healthServiceKey{service: service, bucket: bucket}] = health
+		}
+	}
+
+	for key, report := range endpoints {
+		prev, seen := hm.endpoints[key]
+		wasUp := !seen || prev.State == PingStateOk
+		isUp := report.State == PingStateOk
+		if wasUp == isUp {
+			continue
+		}
+
+		event := HealthEndpointEvent{Service: key.service, Bucket: key.bucket, Endpoint: report}
+		if isUp && hm.opts.OnEndpointUp != nil {
+			cb := hm.opts.OnEndpointUp
+			endpointEvents = append(endpointEvents, func() { cb(event) })
+		} else if !isUp && hm.opts.OnEndpointDown != nil {
+			cb := hm.opts.OnEndpointDown
+			endpointEvents = append(endpointEvents, func() { cb(event) })
+		}
+	}
+
+	for key, health := range services {
+		prev := hm.services[key]
+		if prev == health {
+			continue
+		}
+
+		event := HealthServiceEvent{Service: key.service, Bucket: key.bucket, Previous: prev, Current: health}
+		if health == ServiceHealthHealthy {
+			if prev != ServiceHealthUnknown && hm.opts.OnServiceRecovered != nil {
+				cb := hm.opts.OnServiceRecovered
+				serviceEvents = append(serviceEvents, func() { cb(event) })
+			}
+		} else if (prev == ServiceHealthUnknown || prev == ServiceHealthHealthy) && hm.opts.OnServiceDegraded != nil {
+			cb := hm.opts.OnServiceDegraded
+			serviceEvents = append(serviceEvents, func() { cb(event) })
+		}
+	}
+
+	hm.endpoints = endpoints
+	hm.services = services
+	hm.pingErrs = errs
+	hm.lastCheck = hm.clock()
+	hm.lock.Unlock()
+
+	// Callbacks are invoked outside of the lock so that they can safely call back into the monitor.
+	for _, cb := range endpointEvents {
+		cb()
+	}
+	for _, cb := range serviceEvents {
+		cb()
+	}
+}
+
+// ServiceHealth returns the health of a service as of the last check. The bucket should be empty for services
+// pinged at the cluster level.
+func (hm *HealthMonitor) ServiceHealth(service ServiceType, bucket string) ServiceHealth {
+	hm.lock.Lock()
+	defer hm.lock.Unlock()
+
+	return hm.services[healthServiceKey{service: service, bucket: bucket}]
+}
+
+// Live returns whether the monitor is running and has completed a check recently. A monitor that has not been
+// started is considered live.
+func (hm *HealthMonitor) Live() bool {
+	hm.lock.Lock()
+	defer hm.lock.Unlock()
+
+	if hm.closed {
+		return false
+	}
+	if !hm.started || hm.lastCheck.IsZero() {
+		return true
+	}
+
+	return hm.clock().Sub(hm.lastCheck) < 3*hm.opts.Interval
+}
+
+type healthCheckResult struct {
+	name string
+	err  error
+}
+
+func (hm *HealthMonitor) readinessChecks() []healthCheckResult {
+	hm.lock.Lock()
+	defer hm.lock.Unlock()
+
+	var checks []healthCheckResult
+	check := func(name string, key healthServiceKey) {
+		var err error
+		switch health := hm.services[key]; health {
+		case ServiceHealthHealthy, ServiceHealthDegraded:
+		case ServiceHealthUnknown:
+			err = fmt.Errorf("not yet checked")
+			if pingErr := hm.pingErrs[key.bucket]; pingErr != nil {
+				err = pingErr
+			}
+		default:
+			err = fmt.Errorf("%s", health)
+		}
+		checks = append(checks, healthCheckResult{name: name, err: err})
+	}
+
+	for _, service := range hm.opts.RequiredServices {
+		check("service:"+serviceTypeToString(service), healthServiceKey{service: service})
+	}
+	for _, bucket := range hm.opts.RequiredBuckets {
+		check("bucket:"+bucket, healthServiceKey{service: ServiceTypeKeyValue, bucket: bucket})
+	}
+
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].name < checks[j].name
+	})
+
+	return checks
+}
+
+func (hm *HealthMonitor) monitorReadinessErr() error {
+	hm.lock.Lock()
+	defer hm.lock.Unlock()
+
+	if hm.closed {
+		return fmt.Errorf("health monitor is closed")
+	}
+	if hm.lastCheck.IsZero() {
+		return fmt.Errorf("health monitor has not completed a check")
+	}
+
+	return nil
+}
+
+// Ready returns nil if every required service and bucket had at least one responsive endpoint as of the last check,
+// otherwise an error describing the first failing check.
+func (hm *HealthMonitor) Ready() error {
+	if err := hm.monitorReadinessErr(); err != nil {
+		return makeGenericError(ErrServiceNotAvailable, map[string]interface{}{"reason": err.Error()})
+	}
+
+	for _, check := range hm.readinessChecks() {
+		if check.err != nil {
+			return makeGenericError(ErrServiceNotAvailable, map[string]interface{}{
+				"check":  check.name,
+				"reason": check.err.Error(),
+			})
+		}
+	}
+
+	return nil
+}
+
+// Handler returns an http.Handler which serves /livez and /readyz endpoints. Each responds with a 200 status code when
+// the check passes and a 503 when it fails. Adding the verbose query parameter lists the individual checks.
+func (hm *HealthMonitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		check := healthCheckResult{name: "monitor"}
+		if !hm.Live() {
+			check.err = fmt.Errorf("not running")
+		}
+		hm.writeHealthResponse(w, r, "livez", []healthCheckResult{check})
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		checks := []healthCheckResult{{name: "monitor", err: hm.monitorReadinessErr()}}
+		checks = append(checks, hm.readinessChecks()...)
+		hm.writeHealthResponse(w, r, "readyz", checks)
+	})
+
+	return mux
+}
+
+func allHealthChecksPassed(checks []healthCheckResult) bool {
+	for _, check := range checks {
+		if check.err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (hm *HealthMonitor) writeHealthResponse(w http.ResponseWriter, r *http.Request, name string, checks []healthCheckResult) {
+	passed := allHealthChecksPassed(checks)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if passed {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_, verbose := r.URL.Query()["verbose"]
+	if !verbose && passed {
+		_, _ = fmt.Fprint(w, "ok")
+		return
+	}
+
+	var sb strings.Builder
+	for _, check := range checks {
+		if check.err == nil {
+			sb.WriteString("[+]" + check.name + " ok\n")
+		} else {
+			sb.WriteString("[-]" + check.name + " failed: " + check.err.Error() + "\n")
+		}
+	}
+	if passed {
+		sb.WriteString(name + " check passed\n")
+	} else {
+		sb.WriteString(name + " check failed\n")
+	}
+	_, _ = fmt.Fprint(w, sb.String())
+}
@@ -0,0 +1,188 @@
+package gocb
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+type fakeHealthPinger struct {
+	lock    sync.Mutex
+	results map[string]*PingResult
+	errs    map[string]error
+	opts    map[string]*PingOptions
+}
+
+func (p *fakeHealthPinger) set(bucket string, service ServiceType, states ...PingState) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	reports := make([]EndpointPingReport, len(states))
+	for i, state := range states {
+		reports[i] = EndpointPingReport{
+			ID:     "endpoint" + string(rune('a'+i)),
+			Remote: "10.112.0." + string(rune('1'+i)),
+			State:  state,
+		}
+	}
+
+	res, ok := p.results[bucket]
+	if !ok {
+		res = &PingResult{Services: make(map[ServiceType][]EndpointPingReport)}
+		p.results[bucket] = res
+	}
+	res.Services[service] = reports
+}
+
+func (p *fakeHealthPinger) ping(bucket string, opts *PingOptions) (*PingResult, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.opts[bucket] = opts
+	if err := p.errs[bucket]; err != nil {
+		return nil, err
+	}
+
+	return p.results[bucket], nil
+}
+
+func newFakeHealthPinger() *fakeHealthPinger {
+	return &fakeHealthPinger{
+		results: make(map[string]*PingResult),
+		errs:    make(map[string]error),
+		opts:    make(map[string]*PingOptions),
+	}
+}
+
+func (suite *UnitTestSuite) TestHealthMonitorEvents() {
+	pinger := newFakeHealthPinger()
+	pinger.set("", ServiceTypeQuery, PingStateOk, PingStateOk)
+
+	var down, up []HealthEndpointEvent
+	var degraded, recovered []HealthServiceEvent
+	hm, err := newHealthMonitor(&HealthMonitorOptions{
+		ServiceTypes:       []ServiceType{ServiceTypeSearch},
+		RequiredServices:   []ServiceType{ServiceTypeQuery},
+		OnEndpointDown:     func(e HealthEndpointEvent) { down = append(down, e) },
+		OnEndpointUp:       func(e HealthEndpointEvent) { up = append(up, e) },
+		OnServiceDegraded:  func(e HealthServiceEvent) { degraded = append(degraded, e) },
+		OnServiceRecovered: func(e HealthServiceEvent) { recovered = append(recovered, e) },
+	}, pinger.ping)
+	suite.Require().NoError(err)
+
+	hm.Check()
+	suite.Assert().Equal([]ServiceType{ServiceTypeSearch, ServiceTypeQuery}, pinger.opts[""].ServiceTypes)
+	suite.Assert().Equal(ServiceHealthHealthy, hm.ServiceHealth(ServiceTypeQuery, ""))
+	suite.Assert().Empty(down)
+	suite.Assert().Empty(degraded)
+
+	pinger.set("", ServiceTypeQuery, PingStateOk, PingStateTimeout)
+	hm.Check()
+	suite.Assert().Equal(ServiceHealthDegraded, hm.ServiceHealth(ServiceTypeQuery, ""))
+	suite.Require().Len(down, 1)
+	suite.Assert().Equal(ServiceTypeQuery, down[0].Service)
+	suite.Assert().Equal("10.112.0.2", down[0].Endpoint.Remote)
+	suite.Require().Len(degraded, 1)
+	suite.Assert().Equal(HealthServiceEvent{
+		Service:  ServiceTypeQuery,
+		Previous: ServiceHealthHealthy,
+		Current:  ServiceHealthDegraded,
+	}, degraded[0])
+
+	// Moving from degraded to unavailable does not raise a second degraded event.
+	pinger.set("", ServiceTypeQuery, PingStateError, PingStateTimeout)
+	hm.Check()
+	suite.Assert().Equal(ServiceHealthUnavailable, hm.ServiceHealth(ServiceTypeQuery, ""))
+	suite.Assert().Len(down, 2)
+	suite.Assert().Len(degraded, 1)
+
+	pinger.set("", ServiceTypeQuery, PingStateOk, PingStateOk)
+	hm.Check()
+	suite.Assert().Len(up, 2)
+	suite.Require().Len(recovered, 1)
+	suite.Assert().Equal(ServiceHealthUnavailable, recovered[0].Previous)
+	suite.Assert().Equal(ServiceHealthHealthy, recovered[0].Current)
+}
+
+func (suite *UnitTestSuite) TestHealthMonitorReadiness() {
+	pinger := newFakeHealthPinger()
+	pinger.set("", ServiceTypeQuery, PingStateOk)
+	pinger.set("travel", ServiceTypeKeyValue, PingStateOk, PingStateTimeout)
+	pinger.errs["beer"] = errors.New("bucket not found")
+
+	hm, err := newHealthMonitor(&HealthMonitorOptions{
+		RequiredServices: []ServiceType{ServiceTypeQuery},
+		RequiredBuckets:  []string{"travel", "beer"},
+	}, pinger.ping)
+	suite.Require().NoError(err)
+
+	suite.Assert().ErrorIs(hm.Ready(), ErrServiceNotAvailable)
+
+	hm.Check()
+	suite.Assert().Equal([]ServiceType{ServiceTypeKeyValue}, pinger.opts["travel"].ServiceTypes)
+	suite.Assert().Equal(ServiceHealthDegraded, hm.ServiceHealth(ServiceTypeKeyValue, "travel"))
+	suite.Assert().Equal(ServiceHealthUnavailable, hm.ServiceHealth(ServiceTypeKeyValue, "beer"))
+
+	err = hm.Ready()
+	suite.Require().ErrorIs(err, ErrServiceNotAvailable)
+	var genericErr *GenericError
+	suite.Require().ErrorAs(err, &genericErr)
+	suite.Assert().Equal("bucket:beer", genericErr.Context["check"])
+
+	handler := hm.Handler()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz?verbose", nil))
+	suite.Assert().Equal(http.StatusServiceUnavailable, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+	suite.Assert().Equal("[+]monitor ok\n[-]bucket:beer failed: unavailable\n[+]bucket:travel ok\n[+]service:query ok\n"+
+		"readyz check failed\n", string(body))
+
+	delete(pinger.errs, "beer")
+	pinger.set("beer", ServiceTypeKeyValue, PingStateOk)
+	hm.Check()
+	suite.Assert().NoError(hm.Ready())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	suite.Assert().Equal(http.StatusOK, rec.Code)
+	suite.Assert().Equal("ok", rec.Body.String())
+}
+
+func (suite *UnitTestSuite) TestHealthMonitorLiveness() {
+	pinger := newFakeHealthPinger()
+	pinger.set("", ServiceTypeQuery, PingStateOk)
+
+	hm, err := newHealthMonitor(&HealthMonitorOptions{Interval: time.Hour}, pinger.ping)
+	suite.Require().NoError(err)
+
+	now := time.Now()
+	hm.clock = func() time.Time { return now }
+
+	handler := hm.Handler()
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+		return rec.Code
+	}
+
+	suite.Assert().Equal(http.StatusOK, serve())
+
+	hm.Start()
+	suite.Assert().NoError(hm.Ready())
+	suite.Assert().Equal(http.StatusOK, serve())
+
+	// The monitor is no longer live if checks stop completing.
+	now = now.Add(3 * time.Hour)
+	suite.Assert().False(hm.Live())
+	suite.Assert().Equal(http.StatusServiceUnavailable, serve())
+
+	hm.Close()
+	suite.Assert().False(hm.Live())
+	suite.Assert().ErrorIs(hm.Ready(), ErrServiceNotAvailable)
+
+	_, err = newHealthMonitor(&HealthMonitorOptions{Interval: -1}, pinger.ping)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
@@ -0,0 +1,191 @@
+package gocb
+
+import (
+	"context"
+	"fmt"
+)
+
+// OperationInfo describes an operation being passed through an OperationInterceptor.
+// VOLATILE: This API is subject to change at any time.
+type OperationInfo struct {
+	// Service is the service that the operation will be dispatched to.
+	Service ServiceType
+
+	// Operation is the name of the operation, e.g. "get" or "query", matching the names used for tracing and metrics.
+	Operation string
+
+	BucketName     string
+	ScopeName      string
+	CollectionName string
+
+	// DocumentID is the ID of the document for key-value operations.
+	DocumentID string
+
+	// Statement is the statement for query and analytics operations, the index name for search operations and the
+	// HTTP method and path for management operations.
+	Statement string
+
+	// Options is a pointer to a copy of the options for the operation, e.g. *GetOptions. Interceptors can modify the
+	// options or replace them with a different value of the same type before calling the invoker, without affecting
+	// the options passed in by the caller. The copy is shallow, so maps and slices within the options, such as
+	// NamedParameters, must be replaced rather than modified. Options is nil for management operations.
+	Options interface{}
+}
+
+// OperationInvoker invokes the next interceptor in the chain, or the operation itself.
+// VOLATILE: This API is subject to change at any time.
+type OperationInvoker func(ctx context.Context, info *OperationInfo) (interface{}, error)
+
+// OperationInterceptor wraps the execution of an operation. An interceptor can decorate the context, modify the
+// options, inspect the result or error, or short-circuit the operation by returning without calling the invoker.
+// When short-circuiting an interceptor must return either an error or a result of the same type that the
+// operation returns, e.g. *GetResult. The results of management operations are not exposed to interceptors, so
+// management operations can only be short-circuited by returning an error.
+// VOLATILE: This API is subject to change at any time.
+type OperationInterceptor func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error)
+
+type operationInterceptors struct {
+	global      []OperationInterceptor
+	collections map[string][]OperationInterceptor
+}
+
+func newOperationInterceptors(global []OperationInterceptor,
+	collections map[string][]OperationInterceptor) *operationInterceptors {
+	if len(global) == 0 && len(collections) == 0 {
+		return nil
+	}
+
+	return &operationInterceptors{
+		global:      global,
+		collections: collections,
+	}
+}
+
+func (oi *operationInterceptors) all() []OperationInterceptor {
+	if oi == nil {
+		return nil
+	}
+
+	return oi.global
+}
+
+func (oi *operationInterceptors) forCollection(bucket, scope, collection string) []OperationInterceptor {
+	if oi == nil {
+		return nil
+	}
+
+	if interceptors, ok := oi.collections[bucket+"."+scope+"."+collection]; ok {
+		return interceptors
+	}
+
+	return oi.global
+}
+
+func chainInterceptors(interceptors []OperationInterceptor, invoker OperationInvoker) OperationInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		next := invoker
+		invoker = func(ctx context.Context, info *OperationInfo) (interface{}, error) {
+			return interceptor(ctx, info, next)
+		}
+	}
+
+	return invoker
+}
+
+// interceptOperation runs fn through the interceptor chain, converting any result back to the operations result
+// type.
+func interceptOperation[T any](ctx context.Context, interceptors []OperationInterceptor, info *OperationInfo,
+	fn func(ctx context.Context, info *OperationInfo) (T, error)) (T, error) {
+	if len(interceptors) == 0 {
+		return fn(ctx, info)
+	}
+
+	// Interceptors are always given a context, but if the user didn't provide one then the operation must still see
+	// a nil context unless an interceptor replaced it, transactional queries for example reject any context.
+	userCtx := ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	defaultCtx := ctx
+
+	res, err := chainInterceptors(interceptors, func(ctx context.Context, info *OperationInfo) (interface{}, error) {
+		if userCtx == nil && ctx == defaultCtx {
+			ctx = nil
+		}
+		return fn(ctx, info)
+	})(ctx, info)
+
+	var typed T
+	if res == nil {
+		return typed, err
+	}
+
+	typed, ok := res.(T)
+	if !ok {
+		if err != nil {
+			return typed, err
+		}
+
+		return typed, makeInvalidArgumentsError(fmt.Sprintf("interceptor returned %T for %s operation, expected %T",
+			res, info.Operation, typed))
+	}
+
+	return typed, err
+}
+
+// copyOptions returns a shallow copy of opts, so that interceptors cannot modify the options passed in by the user.
+func copyOptions[T any](opts *T) *T {
+	if opts == nil {
+		return nil
+	}
+
+	optsCopy := *opts
+	return &optsCopy
+}
+
+// interceptedOptions returns a copy of the options set on info, so that the context provided by the interceptors can
+// be applied without modifying the options passed in by the user.
+func interceptedOptions[T any](info *OperationInfo) (*T, error) {
+	opts, ok := info.Options.(*T)
+	if !ok || opts == nil {
+		var expected *T
+		return nil, makeInvalidArgumentsError(fmt.Sprintf("interceptor set options to %T for %s operation, expected %T",
+			info.Options, info.Operation, expected))
+	}
+
+	optsCopy := *opts
+	return &optsCopy, nil
+}
+
+// interceptRequest runs a request that does not expose its result to interceptors through the interceptor chain.
+func interceptRequest[T any](ctx context.Context, interceptors []OperationInterceptor, info *OperationInfo,
+	fn func(ctx context.Context) (T, error)) (T, error) {
+	if len(interceptors) == 0 {
+		return fn(ctx)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var res T
+	var invoked bool
+	_, err := chainInterceptors(interceptors, func(ctx context.Context, info *OperationInfo) (interface{}, error) {
+		invoked = true
+		var err error
+		res, err = fn(ctx)
+		return nil, err
+	})(ctx, info)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	if !invoked {
+		return res, makeInvalidArgumentsError(fmt.Sprintf("interceptor short-circuited %s operation without an error",
+			info.Operation))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,331 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// interceptingKvProvider runs every key-value operation through the interceptors configured for the collection.
+type interceptingKvProvider struct {
+	provider     kvProvider
+	interceptors []OperationInterceptor
+}
+
+var _ kvProvider = (*interceptingKvProvider)(nil)
+
+func (p *interceptingKvProvider) newInfo(c *Collection, opName, id string, opts interface{}) *OperationInfo {
+	return &OperationInfo{
+		Service:        ServiceTypeKeyValue,
+		Operation:      opName,
+		BucketName:     c.bucketName(),
+		ScopeName:      c.ScopeName(),
+		CollectionName: c.Name(),
+		DocumentID:     id,
+		Options:        opts,
+	}
+}
+
+func (p *interceptingKvProvider) Insert(c *Collection, id string, val interface{}, opts *InsertOptions) (*MutationResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "insert", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*MutationResult, error) {
+			opts, err := interceptedOptions[InsertOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Insert(c, id, val, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Upsert(c *Collection, id string, val interface{}, opts *UpsertOptions) (*MutationResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "upsert", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*MutationResult, error) {
+			opts, err := interceptedOptions[UpsertOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Upsert(c, id, val, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Replace(c *Collection, id string, val interface{}, opts *ReplaceOptions) (*MutationResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "replace", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*MutationResult, error) {
+			opts, err := interceptedOptions[ReplaceOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Replace(c, id, val, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Remove(c *Collection, id string, opts *RemoveOptions) (*MutationResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "remove", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*MutationResult, error) {
+			opts, err := interceptedOptions[RemoveOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Remove(c, id, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Get(c *Collection, id string, opts *GetOptions) (*GetResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "get", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*GetResult, error) {
+			opts, err := interceptedOptions[GetOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Get(c, id, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Exists(c *Collection, id string, opts *ExistsOptions) (*ExistsResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "exists", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*ExistsResult, error) {
+			opts, err := interceptedOptions[ExistsOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Exists(c, id, opts)
+		})
+}
+
+func (p *interceptingKvProvider) GetAndTouch(c *Collection, id string, expiry time.Duration, opts *GetAndTouchOptions) (*GetResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "get_and_touch", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*GetResult, error) {
+			opts, err := interceptedOptions[GetAndTouchOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.GetAndTouch(c, id, expiry, opts)
+		})
+}
+
+func (p *interceptingKvProvider) GetAndLock(c *Collection, id string, lockTime time.Duration, opts *GetAndLockOptions) (*GetResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "get_and_lock", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*GetResult, error) {
+			opts, err := interceptedOptions[GetAndLockOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.GetAndLock(c, id, lockTime, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Unlock(c *Collection, id string, cas Cas, opts *UnlockOptions) error {
+	_, err := interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "unlock", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (interface{}, error) {
+			opts, err := interceptedOptions[UnlockOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return nil, p.provider.Unlock(c, id, cas, opts)
+		})
+	return err
+}
+
+func (p *interceptingKvProvider) Touch(c *Collection, id string, expiry time.Duration, opts *TouchOptions) (*MutationResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "touch", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*MutationResult, error) {
+			opts, err := interceptedOptions[TouchOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Touch(c, id, expiry, opts)
+		})
+}
+
+func (p *interceptingKvProvider) GetAnyReplica(c *Collection, id string, opts *GetAnyReplicaOptions) (*GetReplicaResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "get_any_replica", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*GetReplicaResult, error) {
+			opts, err := interceptedOptions[GetAnyReplicaOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.GetAnyReplica(c, id, opts)
+		})
+}
+
+func (p *interceptingKvProvider) GetAllReplicas(c *Collection, id string, opts *GetAllReplicaOptions) (*GetAllReplicasResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "get_all_replicas", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*GetAllReplicasResult, error) {
+			opts, err := interceptedOptions[GetAllReplicaOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.GetAllReplicas(c, id, opts)
+		})
+}
+
+func (p *interceptingKvProvider) LookupIn(c *Collection, id string, ops []LookupInSpec, opts *LookupInOptions) (*LookupInResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "lookup_in", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*LookupInResult, error) {
+			opts, err := interceptedOptions[LookupInOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.LookupIn(c, id, ops, opts)
+		})
+}
+
+func (p *interceptingKvProvider) LookupInAnyReplica(c *Collection, id string, ops []LookupInSpec, opts *LookupInAnyReplicaOptions) (*LookupInReplicaResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "lookup_in_any_replica", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*LookupInReplicaResult, error) {
+			opts, err := interceptedOptions[LookupInAnyReplicaOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.LookupInAnyReplica(c, id, ops, opts)
+		})
+}
+
+func (p *interceptingKvProvider) LookupInAllReplicas(c *Collection, id string, ops []LookupInSpec, opts *LookupInAllReplicaOptions) (*LookupInAllReplicasResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "lookup_in_all_replicas", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*LookupInAllReplicasResult, error) {
+			opts, err := interceptedOptions[LookupInAllReplicaOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.LookupInAllReplicas(c, id, ops, opts)
+		})
+}
+
+func (p *interceptingKvProvider) MutateIn(c *Collection, id string, ops []MutateInSpec, opts *MutateInOptions) (*MutateInResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "mutate_in", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*MutateInResult, error) {
+			opts, err := interceptedOptions[MutateInOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.MutateIn(c, id, ops, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Increment(c *Collection, id string, opts *IncrementOptions) (*CounterResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "increment", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*CounterResult, error) {
+			opts, err := interceptedOptions[IncrementOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Increment(c, id, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Decrement(c *Collection, id string, opts *DecrementOptions) (*CounterResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "decrement", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*CounterResult, error) {
+			opts, err := interceptedOptions[DecrementOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Decrement(c, id, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Append(c *Collection, id string, val []byte, opts *AppendOptions) (*MutationResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "append", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*MutationResult, error) {
+			opts, err := interceptedOptions[AppendOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Append(c, id, val, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Prepend(c *Collection, id string, val []byte, opts *PrependOptions) (*MutationResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "prepend", id, copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*MutationResult, error) {
+			opts, err := interceptedOptions[PrependOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Prepend(c, id, val, opts)
+		})
+}
+
+func (p *interceptingKvProvider) Scan(c *Collection, scanType ScanType, opts *ScanOptions) (*ScanResult, error) {
+	return interceptOperation(opts.Context, p.interceptors, p.newInfo(c, "range_scan", "", copyOptions(opts)),
+		func(ctx context.Context, info *OperationInfo) (*ScanResult, error) {
+			opts, err := interceptedOptions[ScanOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return p.provider.Scan(c, scanType, opts)
+		})
+}
+
+// interceptingKvBulkProvider runs bulk operations through the interceptors configured for the collection, the bulk
+// operation as a whole is passed through the interceptors rather than each of the individual operations.
+type interceptingKvBulkProvider struct {
+	provider     kvBulkProvider
+	interceptors []OperationInterceptor
+}
+
+var _ kvBulkProvider = (*interceptingKvBulkProvider)(nil)
+
+func (p *interceptingKvBulkProvider) Do(c *Collection, ops []BulkOp, opts *BulkOpOptions) error {
+	info := &OperationInfo{
+		Service:        ServiceTypeKeyValue,
+		Operation:      "bulk",
+		BucketName:     c.bucketName(),
+		ScopeName:      c.ScopeName(),
+		CollectionName: c.Name(),
+		Options:        copyOptions(opts),
+	}
+	_, err := interceptOperation(opts.Context, p.interceptors, info,
+		func(ctx context.Context, info *OperationInfo) (interface{}, error) {
+			opts, err := interceptedOptions[BulkOpOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return nil, p.provider.Do(c, ops, opts)
+		})
+	return err
+}
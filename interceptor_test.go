@@ -0,0 +1,336 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
+type interceptorTestContextKey struct{}
+
+func (suite *UnitTestSuite) interceptorCollection(cluster *Cluster, cli *mockConnectionManager) *Collection {
+	cluster.connectionManager = cli
+	return newBucket(cluster, "bucket").Scope("scope").Collection("collection")
+}
+
+func (suite *UnitTestSuite) TestInterceptorsChainKV() {
+	var calls []string
+	recorder := func(name string) OperationInterceptor {
+		return func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+			calls = append(calls, name)
+			return invoker(ctx, info)
+		}
+	}
+	tagger := func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+		suite.Assert().Equal(ServiceTypeKeyValue, info.Service)
+		suite.Assert().Equal("get", info.Operation)
+		suite.Assert().Equal("bucket", info.BucketName)
+		suite.Assert().Equal("scope", info.ScopeName)
+		suite.Assert().Equal("collection", info.CollectionName)
+		suite.Assert().Equal("key", info.DocumentID)
+
+		opts := info.Options.(*GetOptions)
+		opts.Timeout = 5 * time.Second
+
+		return invoker(context.WithValue(ctx, interceptorTestContextKey{}, "tenant"), info)
+	}
+
+	expected := &GetResult{Result: Result{cas: 10}}
+	provider := new(mockKvProvider)
+	provider.
+		On("Get", mock.AnythingOfType("*gocb.Collection"), "key", mock.MatchedBy(func(opts *GetOptions) bool {
+			return opts.Timeout == 5*time.Second && opts.Context.Value(interceptorTestContextKey{}) == "tenant"
+		})).
+		Return(expected, nil).
+		Once()
+
+	cli := new(mockConnectionManager)
+	cli.On("getKvProvider", "bucket").Return(provider, nil)
+
+	cluster := clusterFromOptions(ClusterOptions{
+		Tracer:       &NoopTracer{},
+		Meter:        &NoopMeter{},
+		Interceptors: []OperationInterceptor{recorder("first"), recorder("second"), tagger},
+	})
+	collection := suite.interceptorCollection(cluster, cli)
+
+	opts := &GetOptions{}
+	res, err := collection.Get("key", opts)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(expected, res)
+	suite.Assert().Equal([]string{"first", "second"}, calls)
+
+	// The options passed in by the user should not be modified by the interceptors.
+	suite.Assert().Nil(opts.Context)
+	suite.Assert().Zero(opts.Timeout)
+	provider.AssertExpectations(suite.T())
+}
+
+type interceptorTestBulkProvider struct {
+	opts *BulkOpOptions
+}
+
+func (p *interceptorTestBulkProvider) Do(_ *Collection, ops []BulkOp, opts *BulkOpOptions) error {
+	p.opts = opts
+	return nil
+}
+
+func (suite *UnitTestSuite) TestInterceptorsBulk() {
+	provider := &interceptorTestBulkProvider{}
+	cli := new(mockConnectionManager)
+	cli.On("getKvBulkProvider", "bucket").Return(provider, nil)
+
+	var infos []*OperationInfo
+	cluster := clusterFromOptions(ClusterOptions{
+		Tracer: &NoopTracer{},
+		Meter:  &NoopMeter{},
+		Interceptors: []OperationInterceptor{
+			func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+				infos = append(infos, info)
+				info.Options.(*BulkOpOptions).Timeout = 5 * time.Second
+				return invoker(ctx, info)
+			},
+		},
+	})
+	collection := suite.interceptorCollection(cluster, cli)
+
+	opts := &BulkOpOptions{}
+	suite.Require().NoError(collection.Do([]BulkOp{&GetOp{ID: "key"}}, opts))
+
+	suite.Require().Len(infos, 1)
+	suite.Assert().Equal(ServiceTypeKeyValue, infos[0].Service)
+	suite.Assert().Equal("bulk", infos[0].Operation)
+	suite.Assert().Equal("collection", infos[0].CollectionName)
+	suite.Require().NotNil(provider.opts)
+	suite.Assert().Equal(5*time.Second, provider.opts.Timeout)
+	suite.Assert().Zero(opts.Timeout)
+}
+
+func (suite *UnitTestSuite) TestInterceptorsShortCircuitKV() {
+	expected := &MutationResult{Result: Result{cas: 20}}
+	rejectErr := errors.New("rejected")
+	provider := new(mockKvProvider)
+	cli := new(mockConnectionManager)
+	cli.On("getKvProvider", "bucket").Return(provider, nil)
+
+	cluster := clusterFromOptions(ClusterOptions{
+		Tracer: &NoopTracer{},
+		Meter:  &NoopMeter{},
+		Interceptors: []OperationInterceptor{
+			func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+				switch info.Operation {
+				case "upsert":
+					return expected, nil
+				case "remove":
+					return nil, rejectErr
+				default:
+					return "not a result", nil
+				}
+			},
+		},
+	})
+	collection := suite.interceptorCollection(cluster, cli)
+
+	res, err := collection.Upsert("key", "value", nil)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(expected, res)
+
+	_, err = collection.Remove("key", nil)
+	suite.Require().ErrorIs(err, rejectErr)
+
+	_, err = collection.Get("key", nil)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestInterceptorsCollectionOverride() {
+	var global, override int
+	provider := new(mockKvProvider)
+	provider.
+		On("Unlock", mock.AnythingOfType("*gocb.Collection"), "key", Cas(1), mock.AnythingOfType("*gocb.UnlockOptions")).
+		Return(nil).
+		Twice()
+
+	cli := new(mockConnectionManager)
+	cli.On("getKvProvider", "bucket").Return(provider, nil)
+
+	cluster := clusterFromOptions(ClusterOptions{
+		Tracer: &NoopTracer{},
+		Meter:  &NoopMeter{},
+		Interceptors: []OperationInterceptor{
+			func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+				global++
+				return invoker(ctx, info)
+			},
+		},
+		CollectionInterceptors: map[string][]OperationInterceptor{
+			"bucket.scope.collection": {
+				func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+					override++
+					return invoker(ctx, info)
+				},
+			},
+		},
+	})
+	collection := suite.interceptorCollection(cluster, cli)
+	other := collection.bucket.Scope("scope").Collection("other")
+
+	suite.Require().NoError(collection.Unlock("key", 1, nil))
+	suite.Require().NoError(other.Unlock("key", 1, nil))
+
+	suite.Assert().Equal(1, global)
+	suite.Assert().Equal(1, override)
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestInterceptorsQuery() {
+	provider := new(mockQueryProvider)
+	provider.
+		On("Query", "SELECT 1", (*Scope)(nil), mock.MatchedBy(func(opts *QueryOptions) bool {
+			return opts.ClientContextID == "tagged"
+		})).
+		Return(&QueryResult{}, nil).
+		Once()
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(provider, nil)
+
+	cluster := clusterFromOptions(ClusterOptions{
+		Tracer: &NoopTracer{},
+		Meter:  &NoopMeter{},
+		Interceptors: []OperationInterceptor{
+			func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+				suite.Assert().Equal(ServiceTypeQuery, info.Service)
+				suite.Assert().Equal("SELECT 1", info.Statement)
+
+				opts := *info.Options.(*QueryOptions)
+				opts.ClientContextID = "tagged"
+				info.Options = &opts
+
+				return invoker(ctx, info)
+			},
+		},
+	})
+	cluster.connectionManager = cli
+
+	_, err := cluster.Query("SELECT 1", nil)
+	suite.Require().NoError(err)
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestInterceptorsManagement() {
+	rejectErr := errors.New("rejected")
+	var statements []string
+	provider := new(mockHttpProvider)
+	provider.
+		On("DoHTTPRequest", mock.Anything, mock.AnythingOfType("*gocbcore.HTTPRequest")).
+		Return(&gocbcore.HTTPResponse{StatusCode: 200}, nil).
+		Once()
+
+	mgmtProvider := &mgmtProviderCore{
+		provider:             provider,
+		mgmtTimeout:          time.Second,
+		retryStrategyWrapper: newCoreRetryStrategyWrapper(NewBestEffortRetryStrategy(nil)),
+		interceptors: []OperationInterceptor{
+			func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+				suite.Assert().Equal(ServiceTypeManagement, info.Service)
+				statements = append(statements, info.Statement)
+				if info.Statement == "DELETE /pools/default/buckets/default" {
+					return nil, rejectErr
+				}
+
+				return invoker(ctx, info)
+			},
+		},
+	}
+
+	resp, err := mgmtProvider.executeMgmtRequest(context.Background(), mgmtRequest{
+		Service: ServiceTypeManagement,
+		Method:  "GET",
+		Path:    "/pools/default/buckets",
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(uint32(200), resp.StatusCode)
+
+	_, err = mgmtProvider.executeMgmtRequest(context.Background(), mgmtRequest{
+		Service: ServiceTypeManagement,
+		Method:  "DELETE",
+		Path:    "/pools/default/buckets/default",
+	})
+	suite.Require().ErrorIs(err, rejectErr)
+
+	suite.Assert().Equal([]string{"GET /pools/default/buckets", "DELETE /pools/default/buckets/default"}, statements)
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestInterceptorsQueryAsTransaction() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results[:1],
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("PreparedN1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(reader, nil).
+		Once()
+
+	queryProvider := &queryProviderCore{
+		provider: provider,
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("close").Return(nil)
+
+	var intercepted int
+	cluster := clusterFromOptions(ClusterOptions{
+		Tracer: &NoopTracer{},
+		Meter:  &NoopMeter{},
+		Interceptors: []OperationInterceptor{
+			func(ctx context.Context, info *OperationInfo, invoker OperationInvoker) (interface{}, error) {
+				intercepted++
+				suite.Assert().NotNil(ctx)
+				return invoker(ctx, info)
+			},
+		},
+	})
+	cluster.connectionManager = cli
+	queryProvider.meter = cluster.meter
+	queryProvider.tracer = cluster.tracer
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	cluster.transactions, err = cluster.initTransactions(TransactionsConfig{
+		CleanupConfig: TransactionsCleanupConfig{
+			DisableLostAttemptCleanup: true,
+		},
+	})
+	suite.Require().Nil(err, err)
+
+	// The interceptor is given a context, but the query must not be, as transactional queries reject any context.
+	res, err := cluster.Query("SELECT 1", &QueryOptions{
+		AsTransaction: &SingleQueryTransactionOptions{},
+	})
+	suite.Require().NoError(err)
+	suite.Assert().NotZero(intercepted)
+
+	rows, err := QueryRows[testBreweryDocument](res)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(dataset.Results[:1], rows)
+	provider.AssertExpectations(suite.T())
+
+	err = cluster.Close(nil)
+	suite.Require().Nil(err, err)
+}
@@ -28,6 +28,7 @@ func (p *kvBulkProviderCore) Do(c *Collection, ops []BulkOp, opts *BulkOpOptions
 	if opts.RetryStrategy != nil {
 		retryWrapper = newCoreRetryStrategyWrapper(opts.RetryStrategy)
 	}
+	retryWrapper = retryWrapper.forServiceRequests(ServiceTypeKeyValue, c.meter, len(ops))
 
 	transcoder := opts.Transcoder
 	if transcoder == nil {
@@ -38,30 +39,38 @@ func (p *kvBulkProviderCore) Do(c *Collection, ops []BulkOp, opts *BulkOpOptions
 	//   we get delayed inside execute (don't want to block the
 	//   individual op handlers when they dispatch their signal).
 	signal := make(chan BulkOp, len(ops))
+	admission := newBulkAdmission(opts.Context, c.admission.forService(ServiceTypeKeyValue), time.Now().Add(timeout),
+		signal)
+	defer admission.close()
+	opSignal := admission.opSignal()
 	for _, item := range ops {
+		if !admission.admit(item) {
+			continue
+		}
+
 		switch i := item.(type) {
 		case *GetOp:
-			p.Get(i, span.Context(), c, transcoder, signal, retryWrapper, time.Now().Add(timeout))
+			p.Get(i, span.Context(), c, transcoder, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *GetAndTouchOp:
-			p.GetAndTouch(i, span.Context(), c, transcoder, signal, retryWrapper, time.Now().Add(timeout))
+			p.GetAndTouch(i, span.Context(), c, transcoder, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *TouchOp:
-			p.Touch(i, span.Context(), c, signal, retryWrapper, time.Now().Add(timeout))
+			p.Touch(i, span.Context(), c, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *RemoveOp:
-			p.Delete(i, span.Context(), c, signal, retryWrapper, time.Now().Add(timeout))
+			p.Delete(i, span.Context(), c, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *UpsertOp:
-			p.Set(i, span.Context(), c, transcoder, signal, retryWrapper, time.Now().Add(timeout))
+			p.Set(i, span.Context(), c, transcoder, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *InsertOp:
-			p.Add(i, span.Context(), c, transcoder, signal, retryWrapper, time.Now().Add(timeout))
+			p.Add(i, span.Context(), c, transcoder, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *ReplaceOp:
-			p.Replace(i, span.Context(), c, transcoder, signal, retryWrapper, time.Now().Add(timeout))
+			p.Replace(i, span.Context(), c, transcoder, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *AppendOp:
-			p.Append(i, span.Context(), c, signal, retryWrapper, time.Now().Add(timeout))
+			p.Append(i, span.Context(), c, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *PrependOp:
-			p.Prepend(i, span.Context(), c, signal, retryWrapper, time.Now().Add(timeout))
+			p.Prepend(i, span.Context(), c, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *IncrementOp:
-			p.Increment(i, span.Context(), c, signal, retryWrapper, time.Now().Add(timeout))
+			p.Increment(i, span.Context(), c, opSignal, retryWrapper, time.Now().Add(timeout))
 		case *DecrementOp:
-			p.Decrement(i, span.Context(), c, signal, retryWrapper, time.Now().Add(timeout))
+			p.Decrement(i, span.Context(), c, opSignal, retryWrapper, time.Now().Add(timeout))
 		}
 	}
 
@@ -84,7 +93,7 @@ func (p *kvBulkProviderCore) Get(item *GetOp, tracectx RequestSpanContext, c *Co
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "get", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("get"), item.Err, start)
 	}
 
 	_, err := p.agent.Get(gocbcore.GetOptions{
@@ -122,7 +131,7 @@ func (p *kvBulkProviderCore) GetAndTouch(item *GetAndTouchOp, tracectx RequestSp
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "get_and_touch", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("get_and_touch"), item.Err, start)
 	}
 
 	_, err := p.agent.GetAndTouch(gocbcore.GetAndTouchOptions{
@@ -158,7 +167,7 @@ func (p *kvBulkProviderCore) Touch(item *TouchOp, tracectx RequestSpanContext, c
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "touch", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("touch"), item.Err, start)
 	}
 
 	_, err := p.agent.Touch(gocbcore.TouchOptions{
@@ -199,7 +208,7 @@ func (p *kvBulkProviderCore) Delete(item *RemoveOp, tracectx RequestSpanContext,
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "remove", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("remove"), item.Err, start)
 	}
 
 	_, err := p.agent.Delete(gocbcore.DeleteOptions{
@@ -240,7 +249,7 @@ func (p *kvBulkProviderCore) Set(item *UpsertOp, tracectx RequestSpanContext, c
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "upsert", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("upsert"), item.Err, start)
 	}
 
 	etrace := c.startKvOpTrace("request_encoding", span.Context(), true)
@@ -294,7 +303,7 @@ func (p *kvBulkProviderCore) Add(item *InsertOp, tracectx RequestSpanContext, c
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "insert", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("insert"), item.Err, start)
 	}
 
 	etrace := c.startKvOpTrace("request_encoding", span.Context(), true)
@@ -347,7 +356,7 @@ func (p *kvBulkProviderCore) Replace(item *ReplaceOp, tracectx RequestSpanContex
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "replace", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("replace"), item.Err, start)
 	}
 
 	etrace := c.startKvOpTrace("request_encoding", span.Context(), true)
@@ -401,7 +410,7 @@ func (p *kvBulkProviderCore) Append(item *AppendOp, tracectx RequestSpanContext,
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "append", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("append"), item.Err, start)
 	}
 
 	_, err := p.agent.Append(gocbcore.AdjoinOptions{
@@ -442,7 +451,7 @@ func (p *kvBulkProviderCore) Prepend(item *PrependOp, tracectx RequestSpanContex
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "prepend", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("prepend"), item.Err, start)
 	}
 
 	_, err := p.agent.Prepend(gocbcore.AdjoinOptions{
@@ -483,7 +492,7 @@ func (p *kvBulkProviderCore) Increment(item *IncrementOp, tracectx RequestSpanCo
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "increment", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("increment"), item.Err, start)
 	}
 
 	realInitial := uint64(0xFFFFFFFFFFFFFFFF)
@@ -534,7 +543,7 @@ func (p *kvBulkProviderCore) Decrement(item *DecrementOp, tracectx RequestSpanCo
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "decrement", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("decrement"), item.Err, start)
 	}
 
 	realInitial := uint64(0xFFFFFFFFFFFFFFFF)
@@ -19,6 +19,7 @@ type bulkOpChanStruct struct {
 
 type kvBulkProviderPs struct {
 	client     kv_v1.KvServiceClient
+	compressor *psCompressor
 	once       sync.Once
 	workerChan chan bulkOpChanStruct
 }
@@ -56,14 +57,21 @@ func (p *kvBulkProviderPs) Do(c *Collection, ops []BulkOp, opts *BulkOpOptions)
 	//   we get delayed inside execute (don't want to block the
 	//   individual op handlers when they dispatch their signal).
 	signal := make(chan BulkOp, len(ops))
+	deadline, _ := ctx.Deadline()
+	admission := newBulkAdmission(ctx, c.admission.forService(ServiceTypeKeyValue), deadline, signal)
+	defer admission.close()
 	for _, item := range ops {
+		if !admission.admit(item) {
+			continue
+		}
+
 		p.workerChan <- bulkOpChanStruct{
 			ctx:         ctx,
 			i:           item,
 			spanContext: span.Context(),
 			c:           c,
 			transcoder:  transcoder,
-			signal:      signal,
+			signal:      admission.opSignal(),
 		}
 	}
 
@@ -121,7 +129,7 @@ func (p *kvBulkProviderPs) Get(ctx context.Context, item *GetOp, tracectx Reques
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "get", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("get"), item.Err, start)
 	}
 
 	request := &kv_v1.GetRequest{
@@ -130,6 +138,7 @@ func (p *kvBulkProviderPs) Get(ctx context.Context, item *GetOp, tracectx Reques
 		CollectionName: c.name(),
 		ScopeName:      c.ScopeName(),
 		BucketName:     c.bucketName(),
+		Compression:    p.compressor.RequestCompression(),
 	}
 
 	res, err := p.client.Get(ctx, request)
@@ -144,8 +153,12 @@ func (p *kvBulkProviderPs) Get(ctx context.Context, item *GetOp, tracectx Reques
 	case *kv_v1.GetResponse_ContentUncompressed:
 		content = c.ContentUncompressed
 	case *kv_v1.GetResponse_ContentCompressed:
-		content = c.ContentCompressed
-		logWarnf("couchbase2 does not currently support compressed content, passing through compressed value")
+		content, err = psDecompressValue(c.ContentCompressed)
+		if err != nil {
+			item.Err = err
+			signal <- item
+			return
+		}
 	}
 
 	item.Result = &GetResult{
@@ -165,7 +178,7 @@ func (p *kvBulkProviderPs) GetAndTouch(ctx context.Context, item *GetAndTouchOp,
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "get_and_touch", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("get_and_touch"), item.Err, start)
 	}
 
 	reqExpiry := &kv_v1.GetAndTouchRequest_ExpirySecs{ExpirySecs: uint32(item.Expiry.Seconds())}
@@ -177,7 +190,8 @@ func (p *kvBulkProviderPs) GetAndTouch(ctx context.Context, item *GetAndTouchOp,
 		ScopeName:      c.ScopeName(),
 		BucketName:     c.bucketName(),
 
-		Expiry: reqExpiry,
+		Expiry:      reqExpiry,
+		Compression: p.compressor.RequestCompression(),
 	}
 
 	res, err := p.client.GetAndTouch(ctx, request)
@@ -192,8 +206,12 @@ func (p *kvBulkProviderPs) GetAndTouch(ctx context.Context, item *GetAndTouchOp,
 	case *kv_v1.GetAndTouchResponse_ContentUncompressed:
 		content = c.ContentUncompressed
 	case *kv_v1.GetAndTouchResponse_ContentCompressed:
-		content = c.ContentCompressed
-		logWarnf("couchbase2 does not currently support compressed content, passing through compressed value")
+		content, err = psDecompressValue(c.ContentCompressed)
+		if err != nil {
+			item.Err = err
+			signal <- item
+			return
+		}
 	}
 
 	item.Result = &GetResult{
@@ -213,7 +231,7 @@ func (p *kvBulkProviderPs) Touch(ctx context.Context, item *TouchOp, tracectx Re
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "touch", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("touch"), item.Err, start)
 	}
 
 	request := &kv_v1.TouchRequest{
@@ -251,7 +269,7 @@ func (p *kvBulkProviderPs) Remove(ctx context.Context, item *RemoveOp, tracectx
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "remove", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("remove"), item.Err, start)
 	}
 
 	var cas *uint64
@@ -294,7 +312,7 @@ func (p *kvBulkProviderPs) Upsert(ctx context.Context, item *UpsertOp, tracectx
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "upsert", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("upsert"), item.Err, start)
 	}
 
 	etrace := c.startKvOpTrace("request_encoding", span.Context(), true)
@@ -323,6 +341,9 @@ func (p *kvBulkProviderPs) Upsert(ctx context.Context, item *UpsertOp, tracectx
 
 		Expiry: expiry,
 	}
+	if compressed, ok := p.compressor.Compress(bytes); ok {
+		request.Content = &kv_v1.UpsertRequest_ContentCompressed{ContentCompressed: compressed}
+	}
 
 	res, err := p.client.Upsert(ctx, request)
 	if err != nil {
@@ -349,7 +370,7 @@ func (p *kvBulkProviderPs) Insert(ctx context.Context, item *InsertOp, tracectx
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "insert", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("insert"), item.Err, start)
 	}
 
 	etrace := c.startKvOpTrace("request_encoding", span.Context(), true)
@@ -378,6 +399,9 @@ func (p *kvBulkProviderPs) Insert(ctx context.Context, item *InsertOp, tracectx
 
 		Expiry: expiry,
 	}
+	if compressed, ok := p.compressor.Compress(bytes); ok {
+		request.Content = &kv_v1.InsertRequest_ContentCompressed{ContentCompressed: compressed}
+	}
 
 	res, err := p.client.Insert(ctx, request)
 	if err != nil {
@@ -404,7 +428,7 @@ func (p *kvBulkProviderPs) Replace(ctx context.Context, item *ReplaceOp, tracect
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "replace", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("replace"), item.Err, start)
 	}
 
 	etrace := c.startKvOpTrace("request_encoding", span.Context(), true)
@@ -439,6 +463,9 @@ func (p *kvBulkProviderPs) Replace(ctx context.Context, item *ReplaceOp, tracect
 
 		Expiry: expiry,
 	}
+	if compressed, ok := p.compressor.Compress(bytes); ok {
+		request.Content = &kv_v1.ReplaceRequest_ContentCompressed{ContentCompressed: compressed}
+	}
 
 	res, err := p.client.Replace(ctx, request)
 	if err != nil {
@@ -465,7 +492,7 @@ func (p *kvBulkProviderPs) Append(ctx context.Context, item *AppendOp, tracectx
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "append", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("append"), item.Err, start)
 	}
 
 	request := &kv_v1.AppendRequest{
@@ -501,7 +528,7 @@ func (p *kvBulkProviderPs) Prepend(ctx context.Context, item *PrependOp, tracect
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "prepend", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("prepend"), item.Err, start)
 	}
 
 	request := &kv_v1.PrependRequest{
@@ -537,7 +564,7 @@ func (p *kvBulkProviderPs) Increment(ctx context.Context, item *IncrementOp, tra
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "increment", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("increment"), item.Err, start)
 	}
 
 	var expiry *kv_v1.IncrementRequest_ExpirySecs
@@ -578,7 +605,7 @@ func (p *kvBulkProviderPs) Decrement(ctx context.Context, item *DecrementOp, tra
 	start := time.Now()
 	item.bulkOp.finishFn = func() {
 		span.End()
-		c.meter.ValueRecord(meterValueServiceKV, "decrement", start)
+		c.meter.ValueRecordOutcome(c.meterOperation("decrement"), item.Err, start)
 	}
 
 	var expiry *kv_v1.DecrementRequest_ExpirySecs
@@ -30,6 +30,7 @@ type kvOpManagerCore struct {
 	replicateTo     uint
 	durabilityLevel memd.DurabilityLevel
 	retryStrategy   *coreRetryStrategyWrapper
+	retryRecorder   retryReasonRecorder
 	cancelCh        chan struct{}
 	impersonate     string
 
@@ -37,6 +38,8 @@ type kvOpManagerCore struct {
 	createdTime   time.Time
 	meter         *meterWrapper
 	preserveTTL   bool
+	admitted      bool
+	opErr         error
 
 	ctx context.Context
 }
@@ -65,6 +68,7 @@ func (m *kvOpManagerCore) getTimeout() time.Duration {
 
 func (m *kvOpManagerCore) SetDocumentID(id string) {
 	m.documentID = id
+	spanSetDocumentID(m.span, id)
 }
 
 func (m *kvOpManagerCore) SetCancelCh(cancelCh chan struct{}) {
@@ -146,7 +150,8 @@ func (m *kvOpManagerCore) SetRetryStrategy(retryStrategy RetryStrategy) {
 	if retryStrategy != nil {
 		wrapper = newCoreRetryStrategyWrapper(retryStrategy)
 	}
-	m.retryStrategy = wrapper
+	m.retryStrategy = wrapper.forService(ServiceTypeKeyValue, m.parent.meter)
+	m.retryRecorder.wrapped = m.retryStrategy
 }
 
 func (m *kvOpManagerCore) SetImpersonate(user string) {
@@ -165,10 +170,22 @@ func (m *kvOpManagerCore) SetPreserveExpiry(preserveTTL bool) {
 }
 
 func (m *kvOpManagerCore) Finish(noMetrics bool) {
+	// The request is only retried for the reasons recorded by the retry strategy, if there are none then fall back to
+	// the error in case the operation failed before the retry strategy was set.
+	reasons := m.retryRecorder.RetryReasons()
+	if len(reasons) == 0 {
+		reasons = retryReasonsFromError(m.opErr)
+	}
+	spanSetRetryReasons(m.span, reasons)
 	m.span.End()
 
+	if m.admitted {
+		m.parent.admission.forService(ServiceTypeKeyValue).Release()
+		m.admitted = false
+	}
+
 	if !noMetrics {
-		m.meter.ValueRecord(meterValueServiceKV, m.operationName, m.createdTime)
+		m.meter.ValueRecordOutcome(m.parent.meterOperation(m.operationName), m.opErr, m.createdTime)
 	}
 }
 
@@ -237,8 +254,8 @@ func (m *kvOpManagerCore) Deadline() time.Time {
 	return m.deadline
 }
 
-func (m *kvOpManagerCore) RetryStrategy() *coreRetryStrategyWrapper {
-	return m.retryStrategy
+func (m *kvOpManagerCore) RetryStrategy() gocbcore.RetryStrategy {
+	return &m.retryRecorder
 }
 
 func (m *kvOpManagerCore) Impersonate() string {
@@ -251,11 +268,21 @@ func (m *kvOpManagerCore) PreserveExpiry() bool {
 
 func (m *kvOpManagerCore) CheckReadyForOp() error {
 	if m.err != nil {
+		m.opErr = m.err
 		return m.err
 	}
 
 	if m.getTimeout() == 0 {
-		return errors.New("op manager had no timeout specified")
+		m.opErr = errors.New("op manager had no timeout specified")
+		return m.opErr
+	}
+
+	if limiter := m.parent.admission.forService(ServiceTypeKeyValue); limiter != nil {
+		if err := limiter.Acquire(m.ctx, m.Deadline()); err != nil {
+			m.opErr = err
+			return err
+		}
+		m.admitted = true
 	}
 
 	return nil
@@ -266,7 +293,8 @@ func (m *kvOpManagerCore) NeedsObserve() bool {
 }
 
 func (m *kvOpManagerCore) EnhanceErr(err error) error {
-	return maybeEnhanceCollKVErr(err, m.parent, m.documentID)
+	m.opErr = maybeEnhanceCollKVErr(err, m.parent, m.documentID)
+	return m.opErr
 }
 
 func (m *kvOpManagerCore) EnhanceMt(token gocbcore.MutationToken) *MutationToken {
@@ -291,6 +319,15 @@ func (m *kvOpManagerCore) Resolve(token *MutationToken) {
 }
 
 func (m *kvOpManagerCore) Wait(op gocbcore.PendingOp, err error) error {
+	err = m.wait(op, err)
+	if err != nil {
+		m.opErr = err
+	}
+
+	return err
+}
+
+func (m *kvOpManagerCore) wait(op gocbcore.PendingOp, err error) error {
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,8 @@ package gocb
 import (
 	"testing"
 	"time"
+
+	"github.com/couchbase/gocbcore/v10"
 )
 
 func (suite *IntegrationTestSuite) TestKvOpManagerTimeouts() {
@@ -172,3 +174,34 @@ func (suite *IntegrationTestSuite) TestKvOpManagerTimeouts() {
 		})
 	}
 }
+
+// retryReasonsSpan captures the retry reasons that are recorded against a span.
+type retryReasonsSpan struct {
+	noopSpan
+	reasons []RetryReason
+}
+
+func (s *retryReasonsSpan) setDocumentID(id string) {
+}
+
+func (s *retryReasonsSpan) setRetryReasons(reasons []RetryReason) {
+	s.reasons = reasons
+}
+
+func (suite *UnitTestSuite) TestKvOpManagerCoreRetryReasonsOnSuccess() {
+	col := suite.collection("default", "_default", "_default", nil)
+	m := newKvOpManagerCore(col, "get", nil, nil)
+	span := &retryReasonsSpan{}
+	m.span = span
+	m.SetRetryStrategy(nil)
+
+	req := &mockGocbcoreRequest{
+		reasons: []gocbcore.RetryReason{gocbcore.KVLockedRetryReason},
+	}
+	action := m.RetryStrategy().RetryAfter(req, gocbcore.KVLockedRetryReason)
+	suite.Require().NotZero(action.Duration())
+
+	// The operation succeeded after being retried, the reasons must still be recorded.
+	m.Finish(true)
+	suite.Assert().Equal([]RetryReason{KVLockedRetryReason}, span.reasons)
+}
@@ -33,6 +33,8 @@ type kvOpManagerPs struct {
 	operationName string
 	createdTime   time.Time
 	meter         *meterWrapper
+	opErr         error
+	retryReq      *retriableRequestPs
 }
 
 func (m *kvOpManagerPs) getTimeout() time.Duration {
@@ -59,6 +61,7 @@ func (m *kvOpManagerPs) getTimeout() time.Duration {
 
 func (m *kvOpManagerPs) SetDocumentID(id string) {
 	m.documentID = id
+	spanSetDocumentID(m.span, id)
 }
 
 func (m *kvOpManagerPs) SetTimeout(timeout time.Duration) {
@@ -116,6 +119,7 @@ func (m *kvOpManagerPs) SetRetryStrategy(retryStrategy RetryStrategy) {
 	if retryStrategy != nil {
 		strat = retryStrategy
 	}
+	observeRetryBudgets(strat)
 	m.retryStrategy = strat
 }
 
@@ -132,10 +136,13 @@ func (m *kvOpManagerPs) SetIsIdempotent(idempotent bool) {
 }
 
 func (m *kvOpManagerPs) Finish(noMetrics bool) {
+	if m.retryReq != nil {
+		spanSetRetryReasons(m.span, m.retryReq.RetryReasons())
+	}
 	m.span.End()
 
 	if !noMetrics {
-		m.meter.ValueRecord(meterValueServiceKV, m.operationName, m.createdTime)
+		m.meter.ValueRecordOutcome(m.parent.meterOperation(m.operationName), m.opErr, m.createdTime)
 	}
 }
 
@@ -179,6 +186,10 @@ func (m *kvOpManagerPs) DurabilityLevel() *kv_v1.DurabilityLevel {
 	return m.durabilityLevel
 }
 
+func (m *kvOpManagerPs) Meter() *meterWrapper {
+	return m.meter
+}
+
 func (m *kvOpManagerPs) RetryStrategy() RetryStrategy {
 	return m.retryStrategy
 }
@@ -189,20 +200,44 @@ func (m *kvOpManagerPs) IsIdempotent() bool {
 
 func (m *kvOpManagerPs) CheckReadyForOp() error {
 	if m.err != nil {
+		m.opErr = m.err
 		return m.err
 	}
 
 	if m.getTimeout() == 0 {
-		return errors.New("op manager had no timeout specified")
+		m.opErr = errors.New("op manager had no timeout specified")
+		return m.opErr
 	}
 
 	return nil
 }
 
+func (m *kvOpManagerPs) RecordError(err error) {
+	m.opErr = err
+}
+
 func (m *kvOpManagerPs) OpName() string {
 	return m.operationName
 }
 
+func (m *kvOpManagerPs) Service() string {
+	return meterValueServiceKV
+}
+
+func (m *kvOpManagerPs) CircuitBreaker() *serviceCircuitBreaker {
+	// Circuit breakers for KV are handled by the connection itself.
+	return nil
+}
+
+func (m *kvOpManagerPs) AdmissionLimiter() *admissionLimiter {
+	return m.parent.admission.forService(ServiceTypeKeyValue)
+}
+
+func (m *kvOpManagerPs) Interceptors() []OperationInterceptor {
+	// Key-value interceptors are applied by the collection's kvProvider.
+	return nil
+}
+
 func (m *kvOpManagerPs) CreatedAt() time.Time {
 	return m.createdTime
 }
@@ -216,7 +251,8 @@ func (m *kvOpManagerPs) RetryInfo() retriedRequestInfo {
 }
 
 func (m *kvOpManagerPs) SetRetryRequest(req *retriableRequestPs) {
-	// we don't store this as we don't need it.
+	// We only store this so that the retry reasons can be added to the span.
+	m.retryReq = req
 }
 
 func (m *kvOpManagerPs) OperationID() string {
@@ -242,11 +278,13 @@ func (m *kvOpManagerPs) RetryReasonFor(err error) RetryReason {
 }
 
 func (m *kvOpManagerPs) EnhanceErrorStatus(st *status.Status, readOnly bool) error {
-	return mapPsErrorStatusToGocbError(st, readOnly)
+	m.opErr = mapPsErrorStatusToGocbError(st, readOnly)
+	return m.opErr
 }
 
 func (m *kvOpManagerPs) EnhanceErr(err error, readOnly bool) error {
-	return mapPsErrorToGocbError(err, readOnly)
+	m.opErr = mapPsErrorToGocbError(err, readOnly)
+	return m.opErr
 }
 
 func newKvOpManagerPs(c *Collection, opName string, parentSpan RequestSpan) *kvOpManagerPs {
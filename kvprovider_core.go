@@ -852,13 +852,16 @@ func (p *kvProviderCore) GetAllReplicas(c *Collection, id string, opts *GetAllRe
 	return repRes, nil
 }
 
-func (p *kvProviderCore) GetAnyReplica(c *Collection, id string, opts *GetAnyReplicaOptions) (*GetReplicaResult, error) {
+func (p *kvProviderCore) GetAnyReplica(c *Collection, id string,
+	opts *GetAnyReplicaOptions) (docOut *GetReplicaResult, errOut error) {
 	if opts == nil {
 		opts = &GetAnyReplicaOptions{}
 	}
 
 	start := time.Now()
-	defer c.meter.ValueRecord("kv", "get_any_replica", start)
+	defer func() {
+		c.meter.ValueRecordOutcome(c.meterOperation("get_any_replica"), errOut, start)
+	}()
 
 	var tracectx RequestSpanContext
 	if opts.ParentSpan != nil {
@@ -925,6 +928,10 @@ func (p *kvProviderCore) getOneReplica(
 	opm.SetImpersonate(user)
 	opm.SetContext(ctx)
 
+	if err := opm.CheckReadyForOp(); err != nil {
+		return nil, err
+	}
+
 	if replicaIdx == 0 {
 		var docOut *GetReplicaResult
 		var errOut error
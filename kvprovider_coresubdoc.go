@@ -133,7 +133,9 @@ func (p *kvProviderCore) LookupInAllReplicas(c *Collection, id string, ops []Loo
 func (p *kvProviderCore) LookupInAnyReplica(c *Collection, id string, ops []LookupInSpec,
 	opts *LookupInAnyReplicaOptions) (docOut *LookupInReplicaResult, errOut error) {
 	start := time.Now()
-	defer c.meter.ValueRecord("kv", "lookup_in_any_replica", start)
+	defer func() {
+		c.meter.ValueRecordOutcome(c.meterOperation("lookup_in_any_replica"), errOut, start)
+	}()
 
 	var tracectx RequestSpanContext
 	if opts.ParentSpan != nil {
@@ -198,6 +200,10 @@ func (p *kvProviderCore) lookupInOneReplica(
 	opm.SetContext(ctx)
 	opm.SetCancelCh(cancelCh)
 
+	if err := opm.CheckReadyForOp(); err != nil {
+		return nil, err
+	}
+
 	if replicaIdx == 0 {
 		res, err := p.internalLookupIn(opm, ops, flags, 0)
 		if err != nil {
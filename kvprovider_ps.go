@@ -18,7 +18,8 @@ var _ kvProvider = &kvProviderPs{}
 
 // // wraps kv and makes it compliant for gocb
 type kvProviderPs struct {
-	client kv_v1.KvServiceClient
+	client     kv_v1.KvServiceClient
+	compressor *psCompressor
 }
 
 // this is uint8 to int32, need to check overflows etc
@@ -122,12 +123,16 @@ func (p *kvProviderPs) LookupIn(c *Collection, id string, ops []LookupInSpec, op
 	return docOut, nil
 }
 
+// The couchbase2 KV service only exposes replica reads for whole documents, there is no replica variant of LookupIn.
+var errPsLookupInReplicaNotSupported = wrapError(ErrFeatureNotAvailable,
+	"lookup in replica operations are not supported by the couchbase2 protocol")
+
 func (p *kvProviderPs) LookupInAnyReplica(*Collection, string, []LookupInSpec, *LookupInAnyReplicaOptions) (*LookupInReplicaResult, error) {
-	return nil, ErrFeatureNotAvailable
+	return nil, errPsLookupInReplicaNotSupported
 }
 
 func (p *kvProviderPs) LookupInAllReplicas(*Collection, string, []LookupInSpec, *LookupInAllReplicaOptions) (*LookupInAllReplicasResult, error) {
-	return nil, ErrFeatureNotAvailable
+	return nil, errPsLookupInReplicaNotSupported
 }
 
 func (p *kvProviderPs) MutateIn(c *Collection, id string, ops []MutateInSpec, opts *MutateInOptions) (*MutateInResult, error) {
@@ -184,7 +189,9 @@ func (p *kvProviderPs) MutateIn(c *Collection, id string, ops []MutateInSpec, op
 		}
 
 		if flags&memd.SubdocFlagExpandMacros == memd.SubdocFlagExpandMacros {
-			return nil, wrapError(ErrFeatureNotAvailable, "unsupported flag for  the couchbase2 protocol: macro expansion")
+			// The couchbase2 protocol has no way to request that the server expands macros, sending the macro as
+			// a plain value would silently store the macro text instead.
+			return nil, makePsNotSupportedError("mutation macro expansion")
 		}
 		createPath := op.createPath
 		isXattr := op.isXattr
@@ -260,7 +267,8 @@ func (p *kvProviderPs) MutateIn(c *Collection, id string, ops []MutateInSpec, op
 }
 
 func (p *kvProviderPs) Scan(c *Collection, scanType ScanType, opts *ScanOptions) (*ScanResult, error) {
-	return nil, ErrFeatureNotAvailable
+	// The couchbase2 KV service does not expose a scan RPC, so there is nothing that we can translate to.
+	return nil, wrapError(ErrFeatureNotAvailable, "range and sampling scans are not supported by the couchbase2 protocol")
 }
 
 func (p *kvProviderPs) Insert(c *Collection, id string, val interface{}, opts *InsertOptions) (*MutationResult, error) {
@@ -298,6 +306,9 @@ func (p *kvProviderPs) Insert(c *Collection, id string, val interface{}, opts *I
 		Expiry:          expiry,
 		DurabilityLevel: opm.DurabilityLevel(),
 	}
+	if compressed, ok := p.compressor.Compress(opm.ValueBytes()); ok {
+		request.Content = &kv_v1.InsertRequest_ContentCompressed{ContentCompressed: compressed}
+	}
 
 	res, err := wrapPSOp(opm, request, p.client.Insert)
 	if err != nil {
@@ -360,6 +371,9 @@ func (p *kvProviderPs) Upsert(c *Collection, id string, val interface{}, opts *U
 		Expiry:                   expiry,
 		DurabilityLevel:          opm.DurabilityLevel(),
 	}
+	if compressed, ok := p.compressor.Compress(opm.ValueBytes()); ok {
+		request.Content = &kv_v1.UpsertRequest_ContentCompressed{ContentCompressed: compressed}
+	}
 
 	res, err := wrapPSOp(opm, request, p.client.Upsert)
 	if err != nil {
@@ -424,6 +438,9 @@ func (p *kvProviderPs) Replace(c *Collection, id string, val interface{}, opts *
 		Expiry:          expiry,
 		DurabilityLevel: opm.DurabilityLevel(),
 	}
+	if compressed, ok := p.compressor.Compress(opm.ValueBytes()); ok {
+		request.Content = &kv_v1.ReplaceRequest_ContentCompressed{ContentCompressed: compressed}
+	}
 
 	res, err := wrapPSOp(opm, request, p.client.Replace)
 	if err != nil {
@@ -464,6 +481,7 @@ func (p *kvProviderPs) Get(c *Collection, id string, opts *GetOptions) (*GetResu
 		ScopeName:      opm.ScopeName(),
 		BucketName:     opm.BucketName(),
 		Project:        opts.Project,
+		Compression:    p.compressor.RequestCompression(),
 	}
 
 	res, err := wrapPSOp(opm, request, p.client.Get)
@@ -476,8 +494,10 @@ func (p *kvProviderPs) Get(c *Collection, id string, opts *GetOptions) (*GetResu
 	case *kv_v1.GetResponse_ContentUncompressed:
 		content = c.ContentUncompressed
 	case *kv_v1.GetResponse_ContentCompressed:
-		content = c.ContentCompressed
-		logWarnf("couchbase2 does not currently support compressed content, passing through compressed value")
+		content, err = psDecompressValue(c.ContentCompressed)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	resOut := GetResult{
@@ -520,7 +540,8 @@ func (p *kvProviderPs) GetAndTouch(c *Collection, id string, expiry time.Duratio
 		ScopeName:      opm.ScopeName(),
 		BucketName:     opm.BucketName(),
 
-		Expiry: reqExpiry,
+		Expiry:      reqExpiry,
+		Compression: p.compressor.RequestCompression(),
 	}
 
 	res, err := wrapPSOp(opm, request, p.client.GetAndTouch)
@@ -533,8 +554,10 @@ func (p *kvProviderPs) GetAndTouch(c *Collection, id string, expiry time.Duratio
 	case *kv_v1.GetAndTouchResponse_ContentUncompressed:
 		content = c.ContentUncompressed
 	case *kv_v1.GetAndTouchResponse_ContentCompressed:
-		content = c.ContentCompressed
-		logWarnf("couchbase2 does not currently support compressed content, passing through compressed value")
+		content, err = psDecompressValue(c.ContentCompressed)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	resOut := GetResult{
@@ -572,6 +595,7 @@ func (p *kvProviderPs) GetAndLock(c *Collection, id string, lockTime time.Durati
 		CollectionName: opm.CollectionName(),
 		Key:            opm.DocumentID(),
 		LockTime:       uint32(lockTime.Seconds()),
+		Compression:    p.compressor.RequestCompression(),
 	}
 
 	res, err := wrapPSOp(opm, request, p.client.GetAndLock)
@@ -584,8 +608,10 @@ func (p *kvProviderPs) GetAndLock(c *Collection, id string, lockTime time.Durati
 	case *kv_v1.GetAndLockResponse_ContentUncompressed:
 		content = c.ContentUncompressed
 	case *kv_v1.GetAndLockResponse_ContentCompressed:
-		content = c.ContentCompressed
-		logWarnf("couchbase2 does not currently support compressed content, passing through compressed value")
+		content, err = psDecompressValue(c.ContentCompressed)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	resOut := GetResult{
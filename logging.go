@@ -1,6 +1,8 @@
 package gocb
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -38,6 +40,9 @@ const (
 )
 
 // SetLogRedactionLevel specifies the level with which logs should be redacted.
+// The level also applies to the output of errors, span attributes, threshold logging and orphan reports and diagnostics
+// reports, where user data is tagged with <ud> at partial and full redaction, and system data with <sd> at full
+// redaction.
 func SetLogRedactionLevel(level LogRedactLevel) {
 	globalLogRedactionLevel = level
 	gocbcore.SetLogRedactionLevel(gocbcore.LogRedactLevel(level))
@@ -51,6 +56,38 @@ func redactSystemDataString(v string) string {
 	return "<sd>" + v + "</sd>"
 }
 
+// redactUserData tags v as user data, such as a document ID or statement, when log redaction is enabled.
+func redactUserData(v string) string {
+	if v == "" || globalLogRedactionLevel == RedactNone {
+		return v
+	}
+
+	return redactUserDataString(v)
+}
+
+// redactUserDataValue tags the JSON representation of v as user data when log redaction is enabled.
+func redactUserDataValue(v interface{}) interface{} {
+	if v == nil || globalLogRedactionLevel == RedactNone {
+		return v
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redactUserDataString(fmt.Sprintf("%v", v))
+	}
+
+	return redactUserDataString(string(data))
+}
+
+// redactSystemData tags v as system data, such as a hostname, when full log redaction is enabled.
+func redactSystemData(v string) string {
+	if v == "" || globalLogRedactionLevel != RedactFull {
+		return v
+	}
+
+	return redactSystemDataString(v)
+}
+
 // Logger defines a logging interface. You can either use one of the default loggers
 // (DefaultStdioLogger(), VerboseStdioLogger()) or implement your own.
 type Logger interface {
@@ -62,6 +99,41 @@ type Logger interface {
 	Log(level LogLevel, offset int, format string, v ...interface{}) error
 }
 
+// LogField is a key/value pair which the SDK attaches to a log message to describe the context in which it was
+// logged, such as the bucket or operation ID.
+// VOLATILE: This API is subject to change at any time.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// The keys of the fields which the SDK attaches to log messages.
+// VOLATILE: This API is subject to change at any time.
+const (
+	LogFieldComponent            = "component"
+	LogFieldBucket               = "bucket"
+	LogFieldOperationID          = "operation_id"
+	LogFieldEndpoint             = "endpoint"
+	LogFieldError                = "error"
+	LogFieldClientContextID      = "client_context_id"
+	LogFieldTransactionID        = "transaction_id"
+	LogFieldTransactionAttemptID = "transaction_attempt_id"
+)
+
+// StructuredLogger is a Logger which can also receive the fields that the SDK attaches to log messages. Loggers
+// which do not implement this interface will receive the same messages via Log, without the fields.
+// NewZapLogger and, when building with Go 1.21 or later, NewSlogLogger provide StructuredLogger implementations. The
+// slog adapter is excluded from builds with earlier versions of Go as log/slog was added in Go 1.21, whereas the
+// module itself supports Go 1.19.
+// VOLATILE: This API is subject to change at any time.
+type StructuredLogger interface {
+	Logger
+
+	// LogWithFields outputs logging information, as per Log, along with a set of fields describing the context in
+	// which the message was logged.
+	LogWithFields(level LogLevel, offset int, fields []LogField, format string, v ...interface{}) error
+}
+
 var (
 	globalLogger            Logger
 	globalLogRedactionLevel LogRedactLevel
@@ -98,9 +170,69 @@ type coreLogger struct {
 }
 
 func (wrapper coreLogger) Log(level gocbcore.LogLevel, offset int, format string, v ...interface{}) error {
+	if wrapper.wrapped == nil {
+		return nil
+	}
+
+	if format == coreOrphanReportFormat && len(v) == 1 && globalLogRedactionLevel == RedactFull {
+		v = []interface{}{redactCoreOrphanReport(v[0])}
+	}
+
+	if structured, ok := wrapper.wrapped.(StructuredLogger); ok {
+		return structured.LogWithFields(LogLevel(level), offset+2, coreLogFields, format, v...)
+	}
+
 	return wrapper.wrapped.Log(LogLevel(level), offset+2, format, v...)
 }
 
+// coreOrphanReportFormat is the format used by gocbcore when logging orphaned responses, in zombielogger_component.go.
+// gocbcore does not redact the sockets in the report so we do so before passing it on. TestCoreOrphanReportFormat
+// checks that this still matches the version of gocbcore in use.
+const coreOrphanReportFormat = "Orphaned responses observed:\n %s"
+
+// coreOrphanReportSocketKeys are the keys of the sockets in each item of the orphan report.
+var coreOrphanReportSocketKeys = []string{"last_remote_socket", "last_local_socket"}
+
+func redactCoreOrphanReport(report interface{}) interface{} {
+	data, ok := report.([]byte)
+	if !ok {
+		return report
+	}
+
+	// The report is decoded generically so that any fields which we don't know about are passed on unchanged.
+	var services map[string]map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&services); err != nil {
+		return report
+	}
+
+	for _, service := range services {
+		items, _ := service["top_requests"].([]interface{})
+		for _, item := range items {
+			fields, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, key := range coreOrphanReportSocketKeys {
+				if socket, ok := fields[key].(string); ok {
+					fields[key] = redactSystemData(socket)
+				}
+			}
+		}
+	}
+
+	redacted, err := marshalErrorJSON(services)
+	if err != nil {
+		return report
+	}
+
+	return redacted
+}
+
+var coreLogFields = []LogField{{Key: LogFieldComponent, Value: "gocbcore"}}
+
 func getCoreLogger(logger Logger) gocbcore.Logger {
 	typedLogger, isCoreLogger := logger.(*coreLogWrapper)
 	if isCoreLogger {
@@ -130,6 +262,22 @@ func logExf(level LogLevel, offset int, format string, v ...interface{}) {
 	}
 }
 
+func logExfWithFields(level LogLevel, offset int, fields []LogField, format string, v ...interface{}) {
+	if globalLogger == nil {
+		return
+	}
+
+	var err error
+	if structured, ok := globalLogger.(StructuredLogger); ok {
+		err = structured.LogWithFields(level, offset+1, fields, format, v...)
+	} else {
+		err = globalLogger.Log(level, offset+1, format, v...)
+	}
+	if err != nil {
+		log.Printf("Logger error occurred (%s)\n", err)
+	}
+}
+
 func logInfof(format string, v ...interface{}) {
 	logExf(LogInfo, 1, format, v...)
 }
@@ -150,6 +298,22 @@ func logErrorf(format string, v ...interface{}) {
 	logExf(LogError, 1, format, v...)
 }
 
+func logInfofWithFields(fields []LogField, format string, v ...interface{}) {
+	logExfWithFields(LogInfo, 1, fields, format, v...)
+}
+
+func logDebugfWithFields(fields []LogField, format string, v ...interface{}) {
+	logExfWithFields(LogDebug, 1, fields, format, v...)
+}
+
+func logWarnfWithFields(fields []LogField, format string, v ...interface{}) {
+	logExfWithFields(LogWarn, 1, fields, format, v...)
+}
+
+func logErrorfWithFields(fields []LogField, format string, v ...interface{}) {
+	logExfWithFields(LogError, 1, fields, format, v...)
+}
+
 func reindentLog(indent, message string) string {
 	reindentedMessage := strings.Replace(message, "\n", "\n"+indent, -1)
 	return fmt.Sprintf("%s%s", indent, reindentedMessage)
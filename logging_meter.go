@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -32,19 +36,113 @@ func (amg *aggregatingMeterGroup) Recorders() []*aggregatingValueRecorder {
 	return recorders
 }
 
+type aggregatingCounterGroup struct {
+	lock     sync.RWMutex
+	counters map[string]*aggregatingCounter
+}
+
+func (acg *aggregatingCounterGroup) Counter(name string, tags map[string]string) *aggregatingCounter {
+	key := aggregatingCounterKey(name, tags)
+
+	// Counters are almost always already present so only take the write lock when one needs to be created.
+	acg.lock.RLock()
+	counter := acg.counters[key]
+	acg.lock.RUnlock()
+	if counter != nil {
+		return counter
+	}
+
+	acg.lock.Lock()
+	counter = acg.counters[key]
+	if counter == nil {
+		// Take a copy of the tags so that we don't hold onto a map that the caller might modify.
+		counterTags := make(map[string]string, len(tags))
+		for k, v := range tags {
+			counterTags[k] = v
+		}
+		counter = &aggregatingCounter{
+			key:  key,
+			name: name,
+			tags: counterTags,
+		}
+		acg.counters[key] = counter
+	}
+	acg.lock.Unlock()
+
+	return counter
+}
+
+// Counters returns the counters in the group, ordered by name and then tags.
+func (acg *aggregatingCounterGroup) Counters() []*aggregatingCounter {
+	acg.lock.Lock()
+	counters := make([]*aggregatingCounter, 0, len(acg.counters))
+	for _, c := range acg.counters {
+		counters = append(counters, c)
+	}
+	acg.lock.Unlock()
+
+	sort.Slice(counters, func(i, j int) bool {
+		return counters[i].key < counters[j].key
+	})
+
+	return counters
+}
+
+// aggregatingCounterKey builds the key for the counter with the name and tags, with the tags in key order. The SDK
+// uses a handful of tags so the keys are sorted in place on the stack, leaving the key itself as the only allocation.
+func aggregatingCounterKey(name string, tags map[string]string) string {
+	var keysBuf [8]string
+	keys := keysBuf[:0]
+	size := len(name)
+	for k, v := range tags {
+		keys = append(keys, k)
+		size += len(k) + len(v) + 2
+	}
+	for i := 1; i < len(keys); i++ {
+		for j := i; j > 0 && keys[j] < keys[j-1]; j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(size)
+	sb.WriteString(name)
+	for _, k := range keys {
+		sb.WriteString("|")
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(tags[k])
+	}
+
+	return sb.String()
+}
+
 // LoggingMeter is a Meter implementation providing a simplified, but useful, view into current SDK state.
 type LoggingMeter struct {
-	interval time.Duration
+	interval    time.Duration
+	percentiles []float64
+	writer      io.Writer
 
 	valueRecorderGroups map[string]*aggregatingMeterGroup
+	counterGroup        *aggregatingCounterGroup
 	stopCh              chan struct{}
 }
 
 // LoggingMeterOptions is the set of options available when creating a LoggingMeter.
 type LoggingMeterOptions struct {
 	EmitInterval time.Duration
+
+	// Percentiles are the latency percentiles to output for each operation, defaults to 50, 90, 99, 99.9 and 100.
+	// VOLATILE: This API is subject to change at any time.
+	Percentiles []float64
+
+	// Writer, if set, is written to with each report as a line of JSON instead of the report being logged.
+	// VOLATILE: This API is subject to change at any time.
+	Writer io.Writer
 }
 
+var loggingMeterDefaultPercentiles = []float64{50, 90, 99, 99.9, 100}
+
 // NewLoggingMeter creates a new LoggingMeter.
 func NewLoggingMeter(opts *LoggingMeterOptions) *LoggingMeter {
 	am := newAggregatingMeter(opts)
@@ -80,8 +178,14 @@ func newAggregatingMeter(opts *LoggingMeterOptions) *LoggingMeter {
 	if interval == 0 {
 		interval = 10 * time.Minute
 	}
+	percentiles := opts.Percentiles
+	if len(percentiles) == 0 {
+		percentiles = loggingMeterDefaultPercentiles
+	}
 	am := &LoggingMeter{
-		interval: interval,
+		interval:    interval,
+		percentiles: percentiles,
+		writer:      opts.Writer,
 		valueRecorderGroups: map[string]*aggregatingMeterGroup{
 			meterValueServiceKV: {
 				recorders: make(map[string]*aggregatingValueRecorder),
@@ -102,6 +206,9 @@ func newAggregatingMeter(opts *LoggingMeterOptions) *LoggingMeter {
 				recorders: make(map[string]*aggregatingValueRecorder),
 			},
 		},
+		counterGroup: &aggregatingCounterGroup{
+			counters: make(map[string]*aggregatingCounter),
+		},
 		stopCh: make(chan struct{}),
 	}
 
@@ -120,23 +227,35 @@ func (am *LoggingMeter) loggerRoutine() {
 		case <-time.After(am.interval):
 		}
 
-		jsonData := am.generateOutput()
-		if len(jsonData) == 1 {
-			// Nothing to log so make sure we don't just log empty objects.
-			continue
-		}
+		am.emit()
+	}
+}
 
-		// If we don't do this then json.Marshal will escape any < and > characters.
-		jsonBytes := &bytes.Buffer{}
-		encoder := json.NewEncoder(jsonBytes)
-		encoder.SetEscapeHTML(false)
-		err := encoder.Encode(jsonData)
-		if err != nil {
-			logDebugf("Failed to generate threshold logging service JSON: %s", err)
-		}
+func (am *LoggingMeter) emit() {
+	jsonData := am.generateOutput()
+	if len(jsonData) == 1 {
+		// Nothing to log so make sure we don't just log empty objects.
+		return
+	}
 
-		logInfof("Aggregate metrics: %s", jsonBytes)
+	// If we don't do this then json.Marshal will escape any < and > characters.
+	jsonBytes := &bytes.Buffer{}
+	encoder := json.NewEncoder(jsonBytes)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(jsonData)
+	if err != nil {
+		logDebugf("Failed to generate aggregate metrics JSON: %s", err)
+		return
 	}
+
+	if am.writer != nil {
+		if _, err := am.writer.Write(jsonBytes.Bytes()); err != nil {
+			logDebugf("Failed to write aggregate metrics: %s", err)
+		}
+		return
+	}
+
+	logInfof("Aggregate metrics: %s", jsonBytes)
 }
 
 func (am *LoggingMeter) generateOutput() map[string]interface{} {
@@ -153,7 +272,7 @@ func (am *LoggingMeter) generateOutput() map[string]interface{} {
 			continue
 		}
 		for _, recorder := range recorders {
-			count, values := recorder.GetAndResetValues()
+			count, values := recorder.GetAndResetValues(am.percentiles)
 			// Don't log if there's nothing to log for this recorder.
 			if count > 0 {
 				serviceMap[recorder.operationName] = values
@@ -164,11 +283,26 @@ func (am *LoggingMeter) generateOutput() map[string]interface{} {
 		}
 	}
 
+	counterMap := make(map[string][]map[string]interface{})
+	for _, counter := range am.counterGroup.Counters() {
+		count := counter.GetAndReset()
+		// Don't log if there's nothing to log for this counter.
+		if count > 0 {
+			counterMap[counter.name] = append(counterMap[counter.name], map[string]interface{}{
+				"tags":  counter.tags,
+				"count": count,
+			})
+		}
+	}
+	if len(counterMap) > 0 {
+		output["counters"] = counterMap
+	}
+
 	return output
 }
 
-func (am *LoggingMeter) Counter(_ string, _ map[string]string) (Counter, error) {
-	return defaultNoopCounter, nil
+func (am *LoggingMeter) Counter(name string, tags map[string]string) (Counter, error) {
+	return am.counterGroup.Counter(name, tags), nil
 }
 
 func (am *LoggingMeter) ValueRecorder(name string, tags map[string]string) (ValueRecorder, error) {
@@ -301,17 +435,45 @@ func (bc *aggregatingValueRecorder) RecordValue(val uint64) {
 	bc.hist.RecordValue(val)
 }
 
-func (bc *aggregatingValueRecorder) GetAndResetValues() (uint64, map[string]interface{}) {
+func (bc *aggregatingValueRecorder) GetAndResetValues(percentiles []float64) (uint64, map[string]interface{}) {
 	hist := bc.hist.AggregateAndReset()
 	c := hist.TotalCount()
+	values := make(map[string]string, len(percentiles))
+	for _, p := range percentiles {
+		values[percentileKey(p)] = hist.BinAtPercentile(p)
+	}
+
 	return c, map[string]interface{}{
-		"total_count": c,
-		"percentiles_us": map[string]string{
-			"50.0":  hist.BinAtPercentile(50.0),
-			"90.0":  hist.BinAtPercentile(90.0),
-			"99.0":  hist.BinAtPercentile(99.0),
-			"99.9":  hist.BinAtPercentile(99.9),
-			"100.0": hist.BinAtPercentile(100),
-		},
+		"total_count":    c,
+		"percentiles_us": values,
 	}
 }
+
+// percentileKey formats a percentile with at least one decimal place, e.g. 50 becomes "50.0" and 99.99 "99.99".
+func percentileKey(percentile float64) string {
+	key := strconv.FormatFloat(percentile, 'f', -1, 64)
+	if !strings.Contains(key, ".") {
+		key += ".0"
+	}
+
+	return key
+}
+
+type aggregatingCounter struct {
+	key   string
+	name  string
+	tags  map[string]string
+	count uint64
+}
+
+func (ac *aggregatingCounter) IncrementBy(num uint64) {
+	atomic.AddUint64(&ac.count, num)
+}
+
+func (ac *aggregatingCounter) Value() uint64 {
+	return atomic.LoadUint64(&ac.count)
+}
+
+func (ac *aggregatingCounter) GetAndReset() uint64 {
+	return atomic.SwapUint64(&ac.count, 0)
+}
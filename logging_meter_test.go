@@ -1,6 +1,9 @@
 package gocb
 
 import (
+	"bytes"
+	"encoding/json"
+	"testing"
 	"time"
 )
 
@@ -115,3 +118,99 @@ func (suite *UnitTestSuite) TestAggregatingMeter() {
 	suite.Assert().Equal("<= 129746.34", percentilesq["99.9"])
 	suite.Assert().Equal("<= 129746.34", percentilesq["100.0"])
 }
+
+func (suite *UnitTestSuite) TestLoggingMeterCounters() {
+	meter := newAggregatingMeter(nil)
+	mw := newMeterWrapper(meter)
+
+	mw.IncrementRetries(meterValueServiceKV, KVLockedRetryReason)
+	mw.IncrementRetries(meterValueServiceKV, KVLockedRetryReason)
+	mw.ValueRecordOutcome(meterOperation{service: meterValueServiceKV, operation: "get"},
+		&TimeoutError{InnerError: ErrAmbiguousTimeout}, time.Now())
+	mw.ValueRecordOutcome(meterOperation{service: meterValueServiceQuery, operation: "query"},
+		ErrCircuitBreakerOpen, time.Now())
+
+	output := meter.generateOutput()
+	suite.Require().Contains(output, "counters")
+	counters := output["counters"].(map[string][]map[string]interface{})
+
+	suite.Require().Len(counters[meterNameCBRetries], 1)
+	suite.Assert().Equal(uint64(2), counters[meterNameCBRetries][0]["count"])
+	suite.Assert().Equal(map[string]string{
+		meterAttribServiceKey:     meterValueServiceKV,
+		meterAttribRetryReasonKey: KVLockedRetryReason.Description(),
+	}, counters[meterNameCBRetries][0]["tags"])
+
+	suite.Require().Len(counters[meterNameCBTimeouts], 1)
+	suite.Assert().Equal(uint64(1), counters[meterNameCBTimeouts][0]["count"])
+	suite.Require().Len(counters[meterNameCBCircuitBreakerRejections], 1)
+	suite.Assert().Equal(map[string]string{
+		meterAttribServiceKey:   meterValueServiceQuery,
+		meterAttribOperationKey: "query",
+	}, counters[meterNameCBCircuitBreakerRejections][0]["tags"])
+
+	// Counters are reset each time that they are output.
+	output = meter.generateOutput()
+	suite.Assert().NotContains(output, "counters")
+}
+
+func (suite *UnitTestSuite) TestLoggingMeterCounterKey() {
+	tags := map[string]string{
+		meterAttribServiceKey:    meterValueServiceKV,
+		meterAttribOperationKey:  "get",
+		meterAttribBucketNameKey: "default",
+	}
+	suite.Assert().Equal("db.couchbase.operations.timeouts|db.couchbase.service=kv|db.name=default|db.operation=get",
+		aggregatingCounterKey(meterNameCBTimeouts, tags))
+
+	meter := newAggregatingMeter(nil)
+	counter, err := meter.Counter(meterNameCBTimeouts, tags)
+	suite.Require().NoError(err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		same, _ := meter.Counter(meterNameCBTimeouts, tags)
+		if same != counter {
+			suite.T().Fatal("expected the existing counter to be returned")
+		}
+	})
+	suite.Assert().LessOrEqual(allocs, float64(1))
+}
+
+func (suite *UnitTestSuite) TestLoggingMeterPercentilesAndWriter() {
+	buf := &bytes.Buffer{}
+	meter := newAggregatingMeter(&LoggingMeterOptions{
+		Percentiles: []float64{75, 99.99},
+		Writer:      buf,
+	})
+
+	recorder, err := meter.ValueRecorder(meterNameCBOperations, map[string]string{
+		meterAttribServiceKey:   meterValueServiceKV,
+		meterAttribOperationKey: "get",
+	})
+	suite.Require().NoError(err)
+	recorder.RecordValue(1000)
+
+	meter.emit()
+
+	var output map[string]map[string]struct {
+		TotalCount    uint64            `json:"total_count"`
+		PercentilesUs map[string]string `json:"percentiles_us"`
+	}
+	// The meta entry doesn't match the structure of the services, so only decode what we need.
+	var raw map[string]json.RawMessage
+	suite.Require().NoError(json.Unmarshal(buf.Bytes(), &raw))
+	suite.Require().Contains(raw, "kv")
+	suite.Require().NoError(json.Unmarshal([]byte(`{"kv":`+string(raw["kv"])+`}`), &output))
+
+	get := output["kv"]["get"]
+	suite.Assert().Equal(uint64(1), get.TotalCount)
+	suite.Assert().Equal(map[string]string{
+		"75.0":  "<= 1000.00",
+		"99.99": "<= 1000.00",
+	}, get.PercentilesUs)
+
+	// Nothing is written when there is nothing to report.
+	buf.Reset()
+	meter.emit()
+	suite.Assert().Zero(buf.Len())
+}
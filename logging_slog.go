@@ -0,0 +1,68 @@
+//go:build go1.21
+
+package gocb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// slogLevelTrace is the slog level used for messages logged at LogTrace or LogSched, slog has no level below debug.
+const slogLevelTrace = slog.LevelDebug - 4
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates a Logger which writes messages, and the fields attached to them, to the provided slog Logger.
+// It is only available when building with Go 1.21 or later.
+//
+//	gocb.SetLogger(gocb.NewSlogLogger(slog.Default()))
+//
+// VOLATILE: This API is subject to change at any time.
+func NewSlogLogger(logger *slog.Logger) StructuredLogger {
+	return &slogLogger{
+		logger: logger,
+	}
+}
+
+func (l *slogLogger) Log(level LogLevel, offset int, format string, v ...interface{}) error {
+	return l.LogWithFields(level, offset+1, nil, format, v...)
+}
+
+func (l *slogLogger) LogWithFields(level LogLevel, offset int, fields []LogField, format string, v ...interface{}) error {
+	ctx := context.Background()
+	slogLevel := l.level(level)
+	if !l.logger.Enabled(ctx, slogLevel) {
+		return nil
+	}
+
+	// Skip runtime.Callers and this function to find the origin of the message.
+	var pcs [1]uintptr
+	runtime.Callers(offset+2, pcs[:])
+
+	record := slog.NewRecord(time.Now(), slogLevel, fmt.Sprintf(format, v...), pcs[0])
+	for _, field := range fields {
+		record.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+
+	return l.logger.Handler().Handle(ctx, record)
+}
+
+func (l *slogLogger) level(level LogLevel) slog.Level {
+	switch level {
+	case LogError:
+		return slog.LevelError
+	case LogWarn:
+		return slog.LevelWarn
+	case LogInfo:
+		return slog.LevelInfo
+	case LogDebug:
+		return slog.LevelDebug
+	default:
+		return slogLevelTrace
+	}
+}
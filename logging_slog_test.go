@@ -0,0 +1,57 @@
+//go:build go1.21
+
+package gocb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestSlogLogger() {
+	buf := &bytes.Buffer{}
+	logger := NewSlogLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     slog.LevelInfo,
+	})))
+
+	prevLogger := globalLogger
+	globalLogger = logger
+	defer func() {
+		globalLogger = prevLogger
+	}()
+
+	logErrorfWithFields([]LogField{
+		{Key: LogFieldComponent, Value: "kv"},
+		{Key: LogFieldBucket, Value: "default"},
+		{Key: LogFieldError, Value: errors.New("bucket not found")},
+	}, "failed to open bucket %s", "default")
+	logDebugf("filtered by level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	suite.Require().Len(lines, 1)
+
+	var entry map[string]interface{}
+	suite.Require().NoError(json.Unmarshal([]byte(lines[0]), &entry))
+
+	suite.Assert().Equal("ERROR", entry["level"])
+	suite.Assert().Equal("failed to open bucket default", entry["msg"])
+	suite.Assert().Equal("kv", entry[LogFieldComponent])
+	suite.Assert().Equal("default", entry[LogFieldBucket])
+	suite.Assert().Equal("bucket not found", entry[LogFieldError])
+
+	source, ok := entry["source"].(map[string]interface{})
+	suite.Require().True(ok)
+	suite.Assert().True(strings.HasSuffix(source["file"].(string), "logging_slog_test.go"), source["file"])
+}
+
+func (suite *UnitTestSuite) TestSlogLoggerTraceLevel() {
+	buf := &bytes.Buffer{}
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slogLevelTrace})))
+
+	suite.Require().NoError(logger.Log(LogSched, 0, "scheduled %d", 1))
+	suite.Assert().Contains(buf.String(), "level=DEBUG-4")
+	suite.Assert().Contains(buf.String(), `msg="scheduled 1"`)
+}
@@ -0,0 +1,66 @@
+package gocb
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	gocbcore "github.com/couchbase/gocbcore/v10"
+)
+
+type capturingLogger struct {
+	messages []string
+}
+
+func (l *capturingLogger) Log(level LogLevel, offset int, format string, v ...interface{}) error {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+	return nil
+}
+
+func (suite *UnitTestSuite) TestCoreLoggerRedactsOrphanReport() {
+	report := []byte(`{"kv":{"total_count":1,"top_requests":[{"last_local_id":"66388CF5BFCF7522/18CC8791579B567C",` +
+		`"operation_id":"0x23","last_remote_socket":"10.112.210.101:11210","last_local_socket":"10.112.210.1:54042",` +
+		`"last_server_duration_us":1000,"operation_name":"Get","new_field":{"a":[1,2]}}],"new_total":18446744073709551615}}`)
+
+	capture := &capturingLogger{}
+	logger := getCoreLogger(capture)
+
+	suite.Require().NoError(logger.Log(gocbcore.LogWarn, 0, coreOrphanReportFormat, report))
+
+	SetLogRedactionLevel(RedactFull)
+	defer SetLogRedactionLevel(RedactNone)
+	suite.Require().NoError(logger.Log(gocbcore.LogWarn, 0, coreOrphanReportFormat, report))
+
+	suite.Require().Len(capture.messages, 2)
+	suite.Assert().Equal("Orphaned responses observed:\n "+string(report), capture.messages[0])
+
+	redacted := capture.messages[1]
+	suite.Assert().True(strings.HasPrefix(redacted, "Orphaned responses observed:\n "), redacted)
+	suite.Assert().Contains(redacted, `"last_remote_socket":"<sd>10.112.210.101:11210</sd>"`)
+	suite.Assert().Contains(redacted, `"last_local_socket":"<sd>10.112.210.1:54042</sd>"`)
+	suite.Assert().Contains(redacted, `"operation_id":"0x23"`)
+	suite.Assert().Contains(redacted, `"total_count":1`)
+	// Fields which aren't known about are passed on unchanged.
+	suite.Assert().Contains(redacted, `"new_field":{"a":[1,2]}`)
+	suite.Assert().Contains(redacted, `"new_total":18446744073709551615`)
+}
+
+// TestCoreOrphanReportFormat checks that the orphan report logged by the version of gocbcore in use still matches the
+// format and socket keys which redactCoreOrphanReport relies on, so that updating gocbcore can't silently stop the
+// sockets being redacted.
+func (suite *UnitTestSuite) TestCoreOrphanReportFormat() {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/couchbase/gocbcore/v10").Output()
+	suite.Require().NoError(err)
+
+	src, err := os.ReadFile(filepath.Join(strings.TrimSpace(string(out)), "zombielogger_component.go"))
+	suite.Require().NoError(err)
+
+	suite.Assert().Contains(string(src), "logWarnf("+strconv.Quote(coreOrphanReportFormat)+", jsonBytes)")
+	suite.Assert().Contains(string(src), "`json:\"top_requests\"`")
+	for _, key := range coreOrphanReportSocketKeys {
+		suite.Assert().Contains(string(src), "`json:\""+key+",omitempty\"`")
+	}
+}
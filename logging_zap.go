@@ -0,0 +1,62 @@
+package gocb
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type zapStructuredLogger struct {
+	logger *zap.Logger
+}
+
+// NewZapLogger creates a Logger which writes messages, and the fields attached to them, to the provided zap Logger.
+// Messages logged at LogTrace or LogSched are written at zap's debug level.
+//
+//	gocb.SetLogger(gocb.NewZapLogger(zap.L()))
+//
+// VOLATILE: This API is subject to change at any time.
+func NewZapLogger(logger *zap.Logger) StructuredLogger {
+	return &zapStructuredLogger{
+		logger: logger,
+	}
+}
+
+func (l *zapStructuredLogger) Log(level LogLevel, offset int, format string, v ...interface{}) error {
+	return l.LogWithFields(level, offset+1, nil, format, v...)
+}
+
+func (l *zapStructuredLogger) LogWithFields(level LogLevel, offset int, fields []LogField, format string, v ...interface{}) error {
+	zapLevel := l.level(level)
+	if !l.logger.Core().Enabled(zapLevel) {
+		return nil
+	}
+
+	// The caller skip is relative to this function, so lift it out to the origin of the message.
+	entry := l.logger.WithOptions(zap.AddCallerSkip(offset+1)).Check(zapLevel, fmt.Sprintf(format, v...))
+	if entry == nil {
+		return nil
+	}
+
+	zapFields := make([]zap.Field, len(fields))
+	for i, field := range fields {
+		zapFields[i] = zap.Any(field.Key, field.Value)
+	}
+	entry.Write(zapFields...)
+
+	return nil
+}
+
+func (l *zapStructuredLogger) level(level LogLevel) zapcore.Level {
+	switch level {
+	case LogError:
+		return zapcore.ErrorLevel
+	case LogWarn:
+		return zapcore.WarnLevel
+	case LogInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
@@ -0,0 +1,129 @@
+package gocb
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func (suite *UnitTestSuite) TestZapLogger() {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := NewZapLogger(zap.New(core, zap.AddCaller()))
+
+	prevLogger := globalLogger
+	globalLogger = logger
+	defer func() {
+		globalLogger = prevLogger
+	}()
+
+	testErr := errors.New("connection refused")
+	logWarnfWithFields([]LogField{
+		{Key: LogFieldComponent, Value: "query"},
+		{Key: LogFieldClientContextID, Value: "ctx-1"},
+		{Key: LogFieldError, Value: testErr},
+	}, "query failed: %s", testErr)
+	logInfof("plain %d", 1)
+	logDebugf("filtered by level")
+
+	entries := logs.AllUntimed()
+	suite.Require().Len(entries, 2)
+
+	suite.Assert().Equal(zapcore.WarnLevel, entries[0].Level)
+	suite.Assert().Equal("query failed: connection refused", entries[0].Message)
+	suite.Assert().Equal(map[string]interface{}{
+		LogFieldComponent:       "query",
+		LogFieldClientContextID: "ctx-1",
+		LogFieldError:           "connection refused",
+	}, entries[0].ContextMap())
+	suite.Assert().True(strings.HasSuffix(entries[0].Caller.File, "logging_zap_test.go"), entries[0].Caller.File)
+
+	suite.Assert().Equal(zapcore.InfoLevel, entries[1].Level)
+	suite.Assert().Equal("plain 1", entries[1].Message)
+	suite.Assert().Empty(entries[1].Context)
+	suite.Assert().True(strings.HasSuffix(entries[1].Caller.File, "logging_zap_test.go"), entries[1].Caller.File)
+}
+
+func (suite *UnitTestSuite) TestZapLoggerCoreFields() {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := getCoreLogger(NewZapLogger(zap.New(core)))
+
+	suite.Require().NoError(logger.Log(0, 0, "from core %s", "agent"))
+
+	entries := logs.AllUntimed()
+	suite.Require().Len(entries, 1)
+	suite.Assert().Equal(zapcore.ErrorLevel, entries[0].Level)
+	suite.Assert().Equal("from core agent", entries[0].Message)
+	suite.Assert().Equal(map[string]interface{}{LogFieldComponent: "gocbcore"}, entries[0].ContextMap())
+}
+
+func (suite *UnitTestSuite) TestZapLoggerKvConnectionFields() {
+	core, logs := observer.New(zapcore.DebugLevel)
+	prevLogger := globalLogger
+	globalLogger = NewZapLogger(zap.New(core))
+	defer func() {
+		globalLogger = prevLogger
+	}()
+
+	bus := newEventBus()
+	unsubscribe := bus.subscribe(func(event Event) {})
+	defer unsubscribe()
+	provider := &mockCoreDiagnosticsProvider{info: &gocbcore.DiagnosticInfo{MemdConns: []gocbcore.MemdConnInfo{
+		{ID: "a", LocalAddr: "10.0.0.9:50000", RemoteAddr: "10.0.0.2:11210", Scope: "<md>default</md>",
+			State: gocbcore.EndpointStateConnected},
+	}}}
+	newKvConnectionTracker(provider, nil, newMeterWrapper(&NoopMeter{}), bus).poll()
+
+	entries := logs.FilterMessageSnippet("Observed KV connection").AllUntimed()
+	suite.Require().Len(entries, 1)
+	suite.Assert().Equal(map[string]interface{}{
+		LogFieldComponent: "kv",
+		LogFieldEndpoint:  "10.0.0.2:11210",
+		LogFieldBucket:    "default",
+	}, entries[0].ContextMap())
+}
+
+func (suite *UnitTestSuite) TestZapLoggerQueryFailureFields() {
+	core, logs := observer.New(zapcore.DebugLevel)
+	prevLogger := globalLogger
+	globalLogger = NewZapLogger(zap.New(core))
+	defer func() {
+		globalLogger = prevLogger
+	}()
+
+	retErr := errors.New("an error")
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(nil, retErr)
+
+	queryProvider := &queryProviderCore{
+		provider: provider,
+	}
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+
+	cluster := suite.newCluster(cli)
+	queryProvider.meter = cluster.meter
+	queryProvider.tracer = cluster.tracer
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	_, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{
+		Adhoc:           true,
+		ClientContextID: "ctx-1",
+	})
+	suite.Require().Equal(retErr, err)
+
+	entries := logs.FilterMessageSnippet("Query failed").AllUntimed()
+	suite.Require().Len(entries, 1)
+	suite.Assert().Equal(map[string]interface{}{
+		LogFieldComponent:       "query",
+		LogFieldClientContextID: "ctx-1",
+		LogFieldError:           "an error",
+	}, entries[0].ContextMap())
+}
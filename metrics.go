@@ -1,9 +1,11 @@
 package gocb
 
 import (
-	"github.com/couchbase/gocbcore/v10"
+	"errors"
 	"sync"
 	"time"
+
+	"github.com/couchbase/gocbcore/v10"
 )
 
 // Meter handles metrics information for SDK operations.
@@ -104,9 +106,34 @@ func (nm *coreValueRecorderWrapper) RecordValue(val uint64) {
 }
 
 type meterWrapper struct {
-	attribsCache sync.Map
+	attribsLock  sync.RWMutex
+	attribsCache map[meterAttribsKey]map[string]string
 	meter        Meter
 	isNoopMeter  bool
+	// isDefaultMeter is set when the meter is the LoggingMeter created because no meter was configured.
+	isDefaultMeter bool
+}
+
+// meterAttribsKey is used to look up cached attributes, a comparable struct is used so that lookups don't allocate.
+type meterAttribsKey struct {
+	service    string
+	operation  string
+	bucket     string
+	scope      string
+	collection string
+	outcome    string
+	reason     string
+}
+
+// meterOperation describes the operation that a value is being recorded for. Operations are not tagged with the node
+// that they were sent to, gocbcore does not report it for key-value operations and it would multiply the number of
+// series by the number of nodes in the cluster.
+type meterOperation struct {
+	service    string
+	operation  string
+	bucket     string
+	scope      string
+	collection string
 }
 
 func newMeterWrapper(meter Meter) *meterWrapper {
@@ -118,24 +145,19 @@ func newMeterWrapper(meter Meter) *meterWrapper {
 }
 
 func (mw *meterWrapper) ValueRecorder(service, operation string) (ValueRecorder, error) {
+	return mw.valueRecorder(meterAttribsKey{
+		service:   service,
+		operation: operation,
+	})
+}
+
+func (mw *meterWrapper) valueRecorder(key meterAttribsKey) (ValueRecorder, error) {
 	if mw.isNoopMeter {
 		// If it's a noop meter then let's not pay the overhead of creating and caching attributes.
 		return defaultNoopValueRecorder, nil
 	}
 
-	key := service + "." + operation
-	attribs, ok := mw.attribsCache.Load(key)
-	if !ok {
-		// It doesn't really matter if we end up storing the attribs against the same key multiple times. We just need
-		// to have a read efficient cache that doesn't cause actual data races.
-		attribs = map[string]string{
-			meterAttribServiceKey:   service,
-			meterAttribOperationKey: operation,
-		}
-		mw.attribsCache.Store(key, attribs)
-	}
-
-	recorder, err := mw.meter.ValueRecorder(meterNameCBOperations, attribs.(map[string]string))
+	recorder, err := mw.meter.ValueRecorder(meterNameCBOperations, mw.attribs(key))
 	if err != nil {
 		return nil, err
 	}
@@ -143,8 +165,124 @@ func (mw *meterWrapper) ValueRecorder(service, operation string) (ValueRecorder,
 	return recorder, nil
 }
 
+func (mw *meterWrapper) attribs(key meterAttribsKey) map[string]string {
+	mw.attribsLock.RLock()
+	attribs, ok := mw.attribsCache[key]
+	mw.attribsLock.RUnlock()
+	if ok {
+		return attribs
+	}
+
+	attribs = make(map[string]string, 2)
+	if key.service != "" {
+		attribs[meterAttribServiceKey] = key.service
+	}
+	if key.operation != "" {
+		attribs[meterAttribOperationKey] = key.operation
+	}
+	if key.bucket != "" {
+		attribs[meterAttribBucketNameKey] = key.bucket
+	}
+	if key.scope != "" {
+		attribs[meterAttribScopeNameKey] = key.scope
+	}
+	if key.collection != "" {
+		attribs[meterAttribCollectionNameKey] = key.collection
+	}
+	if key.outcome != "" {
+		attribs[meterAttribOutcomeKey] = key.outcome
+	}
+	if key.reason != "" {
+		attribs[meterAttribRetryReasonKey] = key.reason
+	}
+
+	// It doesn't really matter if we end up storing the attribs against the same key multiple times. We just need
+	// to have a read efficient cache that doesn't cause actual data races.
+	mw.attribsLock.Lock()
+	if mw.attribsCache == nil {
+		mw.attribsCache = make(map[meterAttribsKey]map[string]string)
+	}
+	mw.attribsCache[key] = attribs
+	mw.attribsLock.Unlock()
+
+	return attribs
+}
+
 func (mw *meterWrapper) ValueRecord(service, operation string, start time.Time) {
-	recorder, err := mw.ValueRecorder(service, operation)
+	mw.record(meterAttribsKey{
+		service:   service,
+		operation: operation,
+	}, start)
+}
+
+// ValueRecordOutcome records the duration of an operation, tagged with the keyspace of the operation and its outcome.
+// Operations which timed out or were rejected by a circuit breaker are also counted.
+// Orphaned responses are not counted as gocbcore only reports them through its logs, not per agent.
+func (mw *meterWrapper) ValueRecordOutcome(op meterOperation, err error, start time.Time) {
+	key := meterAttribsKey{
+		service:    op.service,
+		operation:  op.operation,
+		bucket:     op.bucket,
+		scope:      op.scope,
+		collection: op.collection,
+	}
+	outcome := meterOutcome(err)
+
+	if err != nil && !mw.isNoopMeter {
+		switch {
+		case errors.Is(err, ErrTimeout):
+			mw.increment(meterNameCBTimeouts, key, 1)
+		case errors.Is(err, ErrCircuitBreakerOpen):
+			mw.increment(meterNameCBCircuitBreakerRejections, key, 1)
+		}
+	}
+
+	key.outcome = outcome
+	mw.record(key, start)
+}
+
+// IncrementRetries counts a retry of an operation against the service for the provided reason.
+func (mw *meterWrapper) IncrementRetries(service string, reason RetryReason) {
+	if mw == nil || mw.isNoopMeter || reason == nil {
+		return
+	}
+
+	mw.increment(meterNameCBRetries, meterAttribsKey{
+		service: service,
+		reason:  reason.Description(),
+	}, 1)
+}
+
+// countsReconnects returns whether reconnects should be counted against the meter. Reconnects are observed by polling
+// the connections so they are only counted for meters which have been configured.
+func (mw *meterWrapper) countsReconnects() bool {
+	return mw != nil && !mw.isNoopMeter && !mw.isDefaultMeter
+}
+
+// IncrementReconnects counts connections to the service which have been reestablished.
+func (mw *meterWrapper) IncrementReconnects(service, bucket string, count uint64) {
+	if mw == nil || mw.isNoopMeter || count == 0 {
+		return
+	}
+
+	mw.increment(meterNameCBReconnects, meterAttribsKey{
+		service: service,
+		bucket:  bucket,
+	}, count)
+}
+
+func (mw *meterWrapper) increment(name string, key meterAttribsKey, count uint64) {
+	counter, err := mw.meter.Counter(name, mw.attribs(key))
+	if err != nil {
+		logDebugf("Failed to create counter: %v", err)
+		return
+	}
+
+	counter.IncrementBy(count)
+}
+
+func (mw *meterWrapper) record(key meterAttribsKey, start time.Time) {
+	recorder, err := mw.valueRecorder(key)
 	if err != nil {
 		logDebugf("Failed to create value recorder: %v", err)
 		return
@@ -157,3 +295,75 @@ func (mw *meterWrapper) ValueRecord(service, operation string, start time.Time)
 
 	recorder.RecordValue(duration)
 }
+
+// meterOutcome classifies the error that an operation completed with for use as the outcome attribute.
+// None of the errors that are classified implement Is, so walking the chain of wrapped errors is equivalent to calling
+// errors.Is for each of them. A switch is used rather than a map as errors in the chain are not necessarily hashable.
+func meterOutcome(err error) string {
+	if err == nil {
+		return "success"
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		if outcome := meterOutcomeOf(err); outcome != "" {
+			return outcome
+		}
+	}
+
+	return "error"
+}
+
+func meterOutcomeOf(err error) string {
+	switch err {
+	case ErrTimeout:
+		return "timeout"
+	case ErrRequestCanceled:
+		return "request_canceled"
+	case ErrCasMismatch:
+		return "cas_mismatch"
+	case ErrDocumentNotFound:
+		return "doc_not_found"
+	case ErrDocumentExists:
+		return "doc_exists"
+	case ErrDocumentLocked:
+		return "doc_locked"
+	case ErrDocumentNotLocked:
+		return "doc_not_locked"
+	case ErrPathNotFound:
+		return "path_not_found"
+	case ErrPathExists:
+		return "path_exists"
+	case ErrValueTooLarge:
+		return "value_too_large"
+	case ErrDurabilityImpossible:
+		return "durability_impossible"
+	case ErrDurabilityAmbiguous:
+		return "durability_ambiguous"
+	case ErrDurableWriteInProgress:
+		return "durable_write_in_progress"
+	case ErrServiceNotAvailable:
+		return "service_not_available"
+	case ErrAuthenticationFailure:
+		return "authentication_failure"
+	case ErrRateLimitedFailure:
+		return "rate_limited"
+	case ErrQuotaLimitedFailure:
+		return "quota_limited"
+	case ErrCircuitBreakerOpen:
+		return "circuit_breaker_open"
+	case ErrAdmissionRejected:
+		return "admission_rejected"
+	case ErrParsingFailure:
+		return "parsing_failure"
+	case ErrIndexNotFound:
+		return "index_not_found"
+	case ErrPlanningFailure:
+		return "planning_failure"
+	case ErrInvalidArgument:
+		return "invalid_argument"
+	case ErrFeatureNotAvailable:
+		return "feature_not_available"
+	}
+
+	return ""
+}
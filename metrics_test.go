@@ -1,8 +1,11 @@
 package gocb
 
 import (
+	"errors"
 	"sync"
 	"sync/atomic"
+	"testing"
+	"time"
 )
 
 type testCounter struct {
@@ -70,3 +73,112 @@ func (tc *testMeter) ValueRecorder(name string, tags map[string]string) (ValueRe
 	tc.lock.Unlock()
 	return recorder, nil
 }
+
+type tagCapturingMeter struct {
+	lock     sync.Mutex
+	tags     []map[string]string
+	recorder testValueRecorder
+}
+
+func (tm *tagCapturingMeter) Counter(name string, tags map[string]string) (Counter, error) {
+	return &testCounter{}, nil
+}
+
+func (tm *tagCapturingMeter) ValueRecorder(name string, tags map[string]string) (ValueRecorder, error) {
+	tm.lock.Lock()
+	tm.tags = append(tm.tags, tags)
+	tm.lock.Unlock()
+	return &tm.recorder, nil
+}
+
+type countingValueRecorder struct {
+	count uint64
+}
+
+func (cvr *countingValueRecorder) RecordValue(val uint64) {
+	atomic.AddUint64(&cvr.count, 1)
+}
+
+// fixedRecorderMeter returns the same recorder for every call so that it doesn't allocate.
+type fixedRecorderMeter struct {
+	recorder countingValueRecorder
+}
+
+func (fm *fixedRecorderMeter) Counter(name string, tags map[string]string) (Counter, error) {
+	return &testCounter{}, nil
+}
+
+func (fm *fixedRecorderMeter) ValueRecorder(name string, tags map[string]string) (ValueRecorder, error) {
+	return &fm.recorder, nil
+}
+
+func (suite *UnitTestSuite) TestMeterOutcome() {
+	suite.Assert().Equal("success", meterOutcome(nil))
+	suite.Assert().Equal("timeout", meterOutcome(ErrUnambiguousTimeout))
+	suite.Assert().Equal("timeout", meterOutcome(&TimeoutError{InnerError: ErrAmbiguousTimeout}))
+	suite.Assert().Equal("cas_mismatch", meterOutcome(&KeyValueError{InnerError: ErrCasMismatch}))
+	suite.Assert().Equal("doc_not_found", meterOutcome(&KeyValueError{InnerError: ErrDocumentNotFound}))
+	suite.Assert().Equal("circuit_breaker_open", meterOutcome(ErrCircuitBreakerOpen))
+	suite.Assert().Equal("admission_rejected", meterOutcome(ErrAdmissionRejected))
+	// QueryError is not comparable, classifying it must not panic.
+	suite.Assert().Equal("planning_failure", meterOutcome(QueryError{
+		InnerError: ErrPlanningFailure,
+		Errors:     []QueryErrorDesc{{Code: 4000}},
+	}))
+	suite.Assert().Equal("error", meterOutcome(errors.New("something else")))
+}
+
+func (suite *UnitTestSuite) TestMeterWrapperValueRecordOutcomeTags() {
+	meter := &tagCapturingMeter{}
+	mw := newMeterWrapper(meter)
+
+	op := meterOperation{
+		service:    meterValueServiceKV,
+		operation:  "get",
+		bucket:     "default",
+		scope:      "_default",
+		collection: "_default",
+	}
+	mw.ValueRecordOutcome(op, nil, time.Now())
+	mw.ValueRecordOutcome(op, &KeyValueError{InnerError: ErrDocumentNotFound}, time.Now())
+	mw.ValueRecord(meterValueServiceQuery, "query", time.Now())
+
+	suite.Require().Len(meter.tags, 3)
+	suite.Assert().Equal(map[string]string{
+		meterAttribServiceKey:        meterValueServiceKV,
+		meterAttribOperationKey:      "get",
+		meterAttribBucketNameKey:     "default",
+		meterAttribScopeNameKey:      "_default",
+		meterAttribCollectionNameKey: "_default",
+		meterAttribOutcomeKey:        "success",
+	}, meter.tags[0])
+	suite.Assert().Equal("doc_not_found", meter.tags[1][meterAttribOutcomeKey])
+	suite.Assert().Equal(map[string]string{
+		meterAttribServiceKey:   meterValueServiceQuery,
+		meterAttribOperationKey: "query",
+	}, meter.tags[2])
+	suite.Assert().Len(meter.recorder.values, 3)
+}
+
+func (suite *UnitTestSuite) TestMeterWrapperValueRecordOutcomeAllocs() {
+	meter := &fixedRecorderMeter{}
+	mw := newMeterWrapper(meter)
+
+	op := meterOperation{
+		service:    meterValueServiceKV,
+		operation:  "get",
+		bucket:     "default",
+		scope:      "_default",
+		collection: "_default",
+	}
+	err := &KeyValueError{InnerError: ErrDocumentNotFound}
+
+	// Warm the attribute cache before measuring.
+	mw.ValueRecordOutcome(op, err, time.Now())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		mw.ValueRecordOutcome(op, err, time.Now())
+	})
+	suite.Assert().Zero(allocs)
+	suite.Assert().NotZero(atomic.LoadUint64(&meter.recorder.count))
+}
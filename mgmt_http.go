@@ -40,6 +40,8 @@ type mgmtProviderCore struct {
 	provider             httpProvider
 	mgmtTimeout          time.Duration
 	retryStrategyWrapper *coreRetryStrategyWrapper
+	meter                *meterWrapper
+	interceptors         []OperationInterceptor
 }
 
 func (mpc *mgmtProviderCore) executeMgmtRequest(ctx context.Context, req mgmtRequest) (mgmtRespOut *mgmtResponse, errOut error) {
@@ -52,6 +54,7 @@ func (mpc *mgmtProviderCore) executeMgmtRequest(ctx context.Context, req mgmtReq
 	if req.RetryStrategy != nil {
 		retryStrategy = newCoreRetryStrategyWrapper(req.RetryStrategy)
 	}
+	retryStrategy = retryStrategy.forService(req.Service, mpc.meter)
 
 	corereq := &gocbcore.HTTPRequest{
 		Service:       gocbcore.ServiceType(req.Service),
@@ -68,7 +71,9 @@ func (mpc *mgmtProviderCore) executeMgmtRequest(ctx context.Context, req mgmtReq
 		Endpoint:      req.Endpoint,
 	}
 
-	coreresp, err := mpc.provider.DoHTTPRequest(ctx, corereq)
+	coreresp, err := interceptMgmtRequest(ctx, mpc.interceptors, req, func(ctx context.Context) (*gocbcore.HTTPResponse, error) {
+		return mpc.provider.DoHTTPRequest(ctx, corereq)
+	})
 	if err != nil {
 		return nil, makeGenericHTTPError(err, corereq, coreresp)
 	}
@@ -96,6 +101,7 @@ func (c *Cluster) executeMgmtRequest(ctx context.Context, req mgmtRequest) (mgmt
 	if req.RetryStrategy != nil {
 		retryStrategy = newCoreRetryStrategyWrapper(req.RetryStrategy)
 	}
+	retryStrategy = retryStrategy.forService(req.Service, c.meter)
 
 	corereq := &gocbcore.HTTPRequest{
 		Service:       gocbcore.ServiceType(req.Service),
@@ -112,7 +118,9 @@ func (c *Cluster) executeMgmtRequest(ctx context.Context, req mgmtRequest) (mgmt
 		Endpoint:      req.Endpoint,
 	}
 
-	coreresp, err := provider.DoHTTPRequest(ctx, corereq)
+	coreresp, err := interceptMgmtRequest(ctx, c.interceptors.all(), req, func(ctx context.Context) (*gocbcore.HTTPResponse, error) {
+		return provider.DoHTTPRequest(ctx, corereq)
+	})
 	if err != nil {
 		return nil, makeGenericHTTPError(err, corereq, coreresp)
 	}
@@ -140,6 +148,7 @@ func (b *Bucket) executeMgmtRequest(ctx context.Context, req mgmtRequest) (mgmtR
 	if req.RetryStrategy != nil {
 		retryStrategy = newCoreRetryStrategyWrapper(req.RetryStrategy)
 	}
+	retryStrategy = retryStrategy.forService(req.Service, b.meter)
 
 	corereq := &gocbcore.HTTPRequest{
 		Service:       gocbcore.ServiceType(req.Service),
@@ -156,7 +165,9 @@ func (b *Bucket) executeMgmtRequest(ctx context.Context, req mgmtRequest) (mgmtR
 		Endpoint:      req.Endpoint,
 	}
 
-	coreresp, err := provider.DoHTTPRequest(ctx, corereq)
+	coreresp, err := interceptMgmtRequest(ctx, b.interceptors.all(), req, func(ctx context.Context) (*gocbcore.HTTPResponse, error) {
+		return provider.DoHTTPRequest(ctx, corereq)
+	})
 	if err != nil {
 		return nil, makeGenericHTTPError(err, corereq, coreresp)
 	}
@@ -169,6 +180,16 @@ func (b *Bucket) executeMgmtRequest(ctx context.Context, req mgmtRequest) (mgmtR
 	return resp, nil
 }
 
+func interceptMgmtRequest(ctx context.Context, interceptors []OperationInterceptor, req mgmtRequest,
+	fn func(ctx context.Context) (*gocbcore.HTTPResponse, error)) (*gocbcore.HTTPResponse, error) {
+	info := &OperationInfo{
+		Service:   req.Service,
+		Operation: "management",
+		Statement: req.Method + " " + req.Path,
+	}
+	return interceptRequest(ctx, interceptors, info, fn)
+}
+
 func ensureBodyClosed(body io.ReadCloser) {
 	err := body.Close()
 	if err != nil {
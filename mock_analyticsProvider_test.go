@@ -2,37 +2,32 @@
 
 package gocb
 
-import (
-	context "context"
-
-	gocbcore "github.com/couchbase/gocbcore/v10"
-	mock "github.com/stretchr/testify/mock"
-)
+import mock "github.com/stretchr/testify/mock"
 
 // mockAnalyticsProvider is an autogenerated mock type for the analyticsProvider type
 type mockAnalyticsProvider struct {
 	mock.Mock
 }
 
-// AnalyticsQuery provides a mock function with given fields: ctx, opts
-func (_m *mockAnalyticsProvider) AnalyticsQuery(ctx context.Context, opts gocbcore.AnalyticsQueryOptions) (analyticsRowReader, error) {
-	ret := _m.Called(ctx, opts)
+// AnalyticsQuery provides a mock function with given fields: statement, s, opts
+func (_m *mockAnalyticsProvider) AnalyticsQuery(statement string, s *Scope, opts *AnalyticsOptions) (*AnalyticsResult, error) {
+	ret := _m.Called(statement, s, opts)
 
-	var r0 analyticsRowReader
+	var r0 *AnalyticsResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, gocbcore.AnalyticsQueryOptions) (analyticsRowReader, error)); ok {
-		return rf(ctx, opts)
+	if rf, ok := ret.Get(0).(func(string, *Scope, *AnalyticsOptions) (*AnalyticsResult, error)); ok {
+		return rf(statement, s, opts)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, gocbcore.AnalyticsQueryOptions) analyticsRowReader); ok {
-		r0 = rf(ctx, opts)
+	if rf, ok := ret.Get(0).(func(string, *Scope, *AnalyticsOptions) *AnalyticsResult); ok {
+		r0 = rf(statement, s, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(analyticsRowReader)
+			r0 = ret.Get(0).(*AnalyticsResult)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, gocbcore.AnalyticsQueryOptions) error); ok {
-		r1 = rf(ctx, opts)
+	if rf, ok := ret.Get(1).(func(string, *Scope, *AnalyticsOptions) error); ok {
+		r1 = rf(statement, s, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -80,6 +80,32 @@ func (_m *mockConnectionManager) connection(bucketName string) (*gocbcore.Agent,
 	return r0, r1
 }
 
+// getAnalyticsIndexProvider provides a mock function with given fields:
+func (_m *mockConnectionManager) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+	ret := _m.Called()
+
+	var r0 analyticsIndexProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (analyticsIndexProvider, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() analyticsIndexProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(analyticsIndexProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // getAnalyticsProvider provides a mock function with given fields:
 func (_m *mockConnectionManager) getAnalyticsProvider() (analyticsProvider, error) {
 	ret := _m.Called()
@@ -184,6 +210,32 @@ func (_m *mockConnectionManager) getDiagnosticsProvider(bucketName string) (diag
 	return r0, r1
 }
 
+// getEventingManagementProvider provides a mock function with given fields:
+func (_m *mockConnectionManager) getEventingManagementProvider() (eventingManagementProvider, error) {
+	ret := _m.Called()
+
+	var r0 eventingManagementProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (eventingManagementProvider, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() eventingManagementProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(eventingManagementProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // getHTTPProvider provides a mock function with given fields: bucketName
 func (_m *mockConnectionManager) getHTTPProvider(bucketName string) (httpProvider, error) {
 	ret := _m.Called(bucketName)
@@ -392,6 +444,32 @@ func (_m *mockConnectionManager) getSearchProvider() (searchProvider, error) {
 	return r0, r1
 }
 
+// getUserManagementProvider provides a mock function with given fields:
+func (_m *mockConnectionManager) getUserManagementProvider() (userManagementProvider, error) {
+	ret := _m.Called()
+
+	var r0 userManagementProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (userManagementProvider, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() userManagementProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(userManagementProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // getViewProvider provides a mock function with given fields: bucketName
 func (_m *mockConnectionManager) getViewProvider(bucketName string) (viewProvider, error) {
 	ret := _m.Called(bucketName)
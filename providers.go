@@ -17,10 +17,6 @@ type viewProvider interface {
 	ViewQuery(ctx context.Context, opts gocbcore.ViewQueryOptions) (viewRowReader, error)
 }
 
-type analyticsProvider interface {
-	AnalyticsQuery(ctx context.Context, opts gocbcore.AnalyticsQueryOptions) (analyticsRowReader, error)
-}
-
 type diagnosticsProvider interface {
 	Diagnostics(opts gocbcore.DiagnosticsOptions) (*gocbcore.DiagnosticInfo, error)
 	Ping(ctx context.Context, opts gocbcore.PingOptions) (*gocbcore.PingResult, error)
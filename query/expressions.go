@@ -0,0 +1,249 @@
+package query
+
+// Expr is a SQL++ expression, such as a field, a parameter or a condition.
+type Expr interface {
+	write(b *builder)
+}
+
+type exprFunc func(b *builder)
+
+func (f exprFunc) write(b *builder) {
+	f(b)
+}
+
+// Field returns an expression referring to the field at the provided path, such as "geo.lat" or "tags[0]". Each
+// element of the path is escaped.
+func Field(path string) Expr {
+	return exprFunc(func(b *builder) {
+		if path == "" {
+			b.fail(invalidArgument("field path cannot be empty"))
+			return
+		}
+		b.WriteString(EscapePath(path))
+	})
+}
+
+// Param returns an expression referring to the named parameter, such as $name. The value of the parameter must be
+// set with Bind before the statement is built.
+func Param(name string) Expr {
+	return exprFunc(func(b *builder) {
+		b.writeParam(name)
+	})
+}
+
+// Value returns an expression for a literal value. The value is passed as a query parameter rather than being
+// written into the statement.
+func Value(value interface{}) Expr {
+	return exprFunc(func(b *builder) {
+		b.writeValue(value)
+	})
+}
+
+// Raw returns an expression which is written into the statement as is.
+// Raw expressions are not escaped and so must never contain user input.
+func Raw(expr string) Expr {
+	return exprFunc(func(b *builder) {
+		b.WriteString(expr)
+	})
+}
+
+// MetaID returns an expression for the document ID, META().id.
+func MetaID() Expr {
+	return Raw("META().id")
+}
+
+// As returns an expression which aliases expr, as used in the fields of a select statement.
+func As(expr Expr, alias string) Expr {
+	return exprFunc(func(b *builder) {
+		expr.write(b)
+		b.WriteString(" AS ")
+		b.WriteString(EscapeIdentifier(alias))
+	})
+}
+
+// toExpr returns value as is if it is already an expression, otherwise as a literal value.
+func toExpr(value interface{}) Expr {
+	if expr, ok := value.(Expr); ok {
+		return expr
+	}
+
+	return Value(value)
+}
+
+func comparison(field string, op string, value interface{}) Expr {
+	return exprFunc(func(b *builder) {
+		Field(field).write(b)
+		b.WriteString(" " + op + " ")
+		toExpr(value).write(b)
+	})
+}
+
+// Eq returns a condition that field is equal to value. value may be an Expr, such as Param, or a literal value.
+func Eq(field string, value interface{}) Expr {
+	return comparison(field, "=", value)
+}
+
+// Ne returns a condition that field is not equal to value.
+func Ne(field string, value interface{}) Expr {
+	return comparison(field, "!=", value)
+}
+
+// Gt returns a condition that field is greater than value.
+func Gt(field string, value interface{}) Expr {
+	return comparison(field, ">", value)
+}
+
+// Gte returns a condition that field is greater than or equal to value.
+func Gte(field string, value interface{}) Expr {
+	return comparison(field, ">=", value)
+}
+
+// Lt returns a condition that field is less than value.
+func Lt(field string, value interface{}) Expr {
+	return comparison(field, "<", value)
+}
+
+// Lte returns a condition that field is less than or equal to value.
+func Lte(field string, value interface{}) Expr {
+	return comparison(field, "<=", value)
+}
+
+// Like returns a condition that field matches the pattern in value.
+func Like(field string, value interface{}) Expr {
+	return comparison(field, "LIKE", value)
+}
+
+// In returns a condition that field is one of the values in the array value.
+func In(field string, value interface{}) Expr {
+	return comparison(field, "IN", value)
+}
+
+func isCondition(field string, condition string) Expr {
+	return exprFunc(func(b *builder) {
+		Field(field).write(b)
+		b.WriteString(" " + condition)
+	})
+}
+
+// IsNull returns a condition that field is null.
+func IsNull(field string) Expr {
+	return isCondition(field, "IS NULL")
+}
+
+// IsNotNull returns a condition that field is not null.
+func IsNotNull(field string) Expr {
+	return isCondition(field, "IS NOT NULL")
+}
+
+// IsMissing returns a condition that field is missing.
+func IsMissing(field string) Expr {
+	return isCondition(field, "IS MISSING")
+}
+
+// IsValued returns a condition that field is neither null nor missing.
+func IsValued(field string) Expr {
+	return isCondition(field, "IS VALUED")
+}
+
+func logical(op string, conditions []Expr) Expr {
+	return exprFunc(func(b *builder) {
+		if len(conditions) == 0 {
+			b.fail(invalidArgument("%s requires at least one condition", op))
+			return
+		}
+
+		b.WriteByte('(')
+		b.writeExprs(conditions, " "+op+" ")
+		b.WriteByte(')')
+	})
+}
+
+// And returns a condition that all of the conditions are true.
+func And(conditions ...Expr) Expr {
+	return logical("AND", conditions)
+}
+
+// Or returns a condition that any of the conditions are true.
+func Or(conditions ...Expr) Expr {
+	return logical("OR", conditions)
+}
+
+// Not returns a condition that condition is not true.
+func Not(condition Expr) Expr {
+	return exprFunc(func(b *builder) {
+		b.WriteString("NOT (")
+		condition.write(b)
+		b.WriteByte(')')
+	})
+}
+
+// Order is an ordering term for OrderBy.
+type Order struct {
+	expr Expr
+	desc bool
+}
+
+// Asc returns an ascending ordering on field.
+func Asc(field string) Order {
+	return Order{expr: Field(field)}
+}
+
+// Desc returns a descending ordering on field.
+func Desc(field string) Order {
+	return Order{expr: Field(field), desc: true}
+}
+
+// AscExpr returns an ascending ordering on expr.
+func AscExpr(expr Expr) Order {
+	return Order{expr: expr}
+}
+
+// DescExpr returns a descending ordering on expr.
+func DescExpr(expr Expr) Order {
+	return Order{expr: expr, desc: true}
+}
+
+func (o Order) write(b *builder) {
+	o.expr.write(b)
+	if o.desc {
+		b.WriteString(" DESC")
+	} else {
+		b.WriteString(" ASC")
+	}
+}
+
+// writeWhere writes a WHERE clause with the conditions joined by AND.
+func writeWhere(b *builder, conditions []Expr) {
+	if len(conditions) == 0 {
+		return
+	}
+
+	b.WriteString(" WHERE ")
+	if len(conditions) == 1 {
+		conditions[0].write(b)
+		return
+	}
+	b.writeExprs(conditions, " AND ")
+}
+
+func writeReturning(b *builder, returning []Expr) {
+	if len(returning) == 0 {
+		return
+	}
+
+	b.WriteString(" RETURNING ")
+	b.writeExprs(returning, ", ")
+}
+
+func fieldExprs(fields []string) []Expr {
+	exprs := make([]Expr, len(fields))
+	for i, field := range fields {
+		if field == "*" {
+			exprs[i] = Raw("*")
+			continue
+		}
+		exprs[i] = Field(field)
+	}
+
+	return exprs
+}
@@ -0,0 +1,260 @@
+package query
+
+import (
+	"strconv"
+
+	"github.com/lissteron/gocb"
+)
+
+// UpsertBuilder builds an UPSERT statement.
+type UpsertBuilder struct {
+	keyspace  Keyspace
+	values    [][2]Expr
+	returning []Expr
+	bindings  map[string]interface{}
+}
+
+// Upsert starts an UPSERT statement into the collection.
+func Upsert(collection *gocb.Collection) *UpsertBuilder {
+	return UpsertKeyspace(KeyspaceOf(collection))
+}
+
+// UpsertKeyspace starts an UPSERT statement into the keyspace.
+func UpsertKeyspace(keyspace Keyspace) *UpsertBuilder {
+	return &UpsertBuilder{
+		keyspace: keyspace,
+	}
+}
+
+// Values adds a document to upsert. key and value may be an Expr, such as Param, or literal values.
+func (ub *UpsertBuilder) Values(key, value interface{}) *UpsertBuilder {
+	ub.values = append(ub.values, [2]Expr{toExpr(key), toExpr(value)})
+	return ub
+}
+
+// Returning sets the expressions to return for each upserted document.
+func (ub *UpsertBuilder) Returning(exprs ...Expr) *UpsertBuilder {
+	ub.returning = append(ub.returning, exprs...)
+	return ub
+}
+
+// Bind sets the value of a named parameter.
+func (ub *UpsertBuilder) Bind(name string, value interface{}) *UpsertBuilder {
+	if ub.bindings == nil {
+		ub.bindings = make(map[string]interface{})
+	}
+	ub.bindings[name] = value
+	return ub
+}
+
+// Build returns the statement.
+func (ub *UpsertBuilder) Build() (*Statement, error) {
+	return build(ub.write, ub.bindings)
+}
+
+func (ub *UpsertBuilder) write(b *builder) {
+	if len(ub.values) == 0 {
+		b.fail(invalidArgument("at least one document must be upserted"))
+		return
+	}
+
+	b.WriteString("UPSERT INTO ")
+	ub.keyspace.write(b)
+	b.WriteString(" (KEY, VALUE) VALUES ")
+	for i, kv := range ub.values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		kv[0].write(b)
+		b.WriteString(", ")
+		kv[1].write(b)
+		b.WriteByte(')')
+	}
+
+	writeReturning(b, ub.returning)
+}
+
+// UpdateBuilder builds an UPDATE statement.
+type UpdateBuilder struct {
+	keyspace  Keyspace
+	set       []Expr
+	unset     []Expr
+	where     []Expr
+	all       bool
+	limit     *uint64
+	returning []Expr
+	bindings  map[string]interface{}
+}
+
+// Update starts an UPDATE statement on the collection.
+func Update(collection *gocb.Collection) *UpdateBuilder {
+	return UpdateKeyspace(KeyspaceOf(collection))
+}
+
+// UpdateKeyspace starts an UPDATE statement on the keyspace.
+func UpdateKeyspace(keyspace Keyspace) *UpdateBuilder {
+	return &UpdateBuilder{
+		keyspace: keyspace,
+	}
+}
+
+// Set sets the field at path to value. value may be an Expr, such as Param, or a literal value.
+func (ub *UpdateBuilder) Set(path string, value interface{}) *UpdateBuilder {
+	ub.set = append(ub.set, comparison(path, "=", value))
+	return ub
+}
+
+// Unset removes the field at path.
+func (ub *UpdateBuilder) Unset(path string) *UpdateBuilder {
+	ub.unset = append(ub.unset, Field(path))
+	return ub
+}
+
+// Where adds conditions to the statement, all of which must be true.
+func (ub *UpdateBuilder) Where(conditions ...Expr) *UpdateBuilder {
+	ub.where = append(ub.where, conditions...)
+	return ub
+}
+
+// All allows the statement to be built without any conditions, updating every document in the keyspace. Without
+// it, Build fails if Where has not been used.
+func (ub *UpdateBuilder) All() *UpdateBuilder {
+	ub.all = true
+	return ub
+}
+
+// Limit sets the maximum number of documents to update.
+func (ub *UpdateBuilder) Limit(limit uint64) *UpdateBuilder {
+	ub.limit = &limit
+	return ub
+}
+
+// Returning sets the expressions to return for each updated document.
+func (ub *UpdateBuilder) Returning(exprs ...Expr) *UpdateBuilder {
+	ub.returning = append(ub.returning, exprs...)
+	return ub
+}
+
+// Bind sets the value of a named parameter.
+func (ub *UpdateBuilder) Bind(name string, value interface{}) *UpdateBuilder {
+	if ub.bindings == nil {
+		ub.bindings = make(map[string]interface{})
+	}
+	ub.bindings[name] = value
+	return ub
+}
+
+// Build returns the statement.
+func (ub *UpdateBuilder) Build() (*Statement, error) {
+	return build(ub.write, ub.bindings)
+}
+
+func (ub *UpdateBuilder) write(b *builder) {
+	if len(ub.set) == 0 && len(ub.unset) == 0 {
+		b.fail(invalidArgument("at least one field must be set or unset"))
+		return
+	}
+	if len(ub.where) == 0 && !ub.all {
+		b.fail(invalidArgument("an update without conditions must use All"))
+		return
+	}
+
+	b.WriteString("UPDATE ")
+	ub.keyspace.write(b)
+	if len(ub.set) > 0 {
+		b.WriteString(" SET ")
+		b.writeExprs(ub.set, ", ")
+	}
+	if len(ub.unset) > 0 {
+		b.WriteString(" UNSET ")
+		b.writeExprs(ub.unset, ", ")
+	}
+
+	writeWhere(b, ub.where)
+
+	if ub.limit != nil {
+		b.WriteString(" LIMIT " + strconv.FormatUint(*ub.limit, 10))
+	}
+
+	writeReturning(b, ub.returning)
+}
+
+// DeleteBuilder builds a DELETE statement.
+type DeleteBuilder struct {
+	keyspace  Keyspace
+	where     []Expr
+	all       bool
+	limit     *uint64
+	returning []Expr
+	bindings  map[string]interface{}
+}
+
+// DeleteFrom starts a DELETE statement on the collection.
+func DeleteFrom(collection *gocb.Collection) *DeleteBuilder {
+	return DeleteFromKeyspace(KeyspaceOf(collection))
+}
+
+// DeleteFromKeyspace starts a DELETE statement on the keyspace.
+func DeleteFromKeyspace(keyspace Keyspace) *DeleteBuilder {
+	return &DeleteBuilder{
+		keyspace: keyspace,
+	}
+}
+
+// Where adds conditions to the statement, all of which must be true.
+func (db *DeleteBuilder) Where(conditions ...Expr) *DeleteBuilder {
+	db.where = append(db.where, conditions...)
+	return db
+}
+
+// All allows the statement to be built without any conditions, deleting every document in the keyspace. Without
+// it, Build fails if Where has not been used.
+func (db *DeleteBuilder) All() *DeleteBuilder {
+	db.all = true
+	return db
+}
+
+// Limit sets the maximum number of documents to delete.
+func (db *DeleteBuilder) Limit(limit uint64) *DeleteBuilder {
+	db.limit = &limit
+	return db
+}
+
+// Returning sets the expressions to return for each deleted document.
+func (db *DeleteBuilder) Returning(exprs ...Expr) *DeleteBuilder {
+	db.returning = append(db.returning, exprs...)
+	return db
+}
+
+// Bind sets the value of a named parameter.
+func (db *DeleteBuilder) Bind(name string, value interface{}) *DeleteBuilder {
+	if db.bindings == nil {
+		db.bindings = make(map[string]interface{})
+	}
+	db.bindings[name] = value
+	return db
+}
+
+// Build returns the statement.
+func (db *DeleteBuilder) Build() (*Statement, error) {
+	return build(db.write, db.bindings)
+}
+
+func (db *DeleteBuilder) write(b *builder) {
+	if len(db.where) == 0 && !db.all {
+		b.fail(invalidArgument("a delete without conditions must use All"))
+		return
+	}
+
+	b.WriteString("DELETE FROM ")
+	db.keyspace.write(b)
+
+	writeWhere(b, db.where)
+
+	if db.limit != nil {
+		b.WriteString(" LIMIT " + strconv.FormatUint(*db.limit, 10))
+	}
+
+	writeReturning(b, db.returning)
+}
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/lissteron/gocb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsert(t *testing.T) {
+	stmt, err := Upsert(testCollection(t)).
+		Values("airline_1", map[string]interface{}{"name": "one"}).
+		Values(Param("key"), Param("doc")).
+		Returning(MetaID()).
+		Bind("key", "airline_2").
+		Bind("doc", map[string]interface{}{"name": "two"}).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "UPSERT INTO `travel-sample`.`inventory`.`airline` (KEY, VALUE) "+
+		"VALUES ($_p1, $_p2), ($key, $doc) RETURNING META().id", stmt.Statement)
+	assert.Equal(t, map[string]interface{}{
+		"_p1": "airline_1",
+		"_p2": map[string]interface{}{"name": "one"},
+		"key": "airline_2",
+		"doc": map[string]interface{}{"name": "two"},
+	}, stmt.NamedParameters)
+
+	_, err = UpsertKeyspace(Keyspace{Bucket: "default"}).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+}
+
+func TestUpdate(t *testing.T) {
+	stmt, err := Update(testCollection(t)).
+		Set("name", "renamed").
+		Set("geo.lat", 1.5).
+		Unset("callsign").
+		Where(Eq("id", 10)).
+		Limit(1).
+		Returning(Field("name")).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "UPDATE `travel-sample`.`inventory`.`airline` SET `name` = $1, `geo`.`lat` = $2 "+
+		"UNSET `callsign` WHERE `id` = $3 LIMIT 1 RETURNING `name`", stmt.Statement)
+	assert.Equal(t, []interface{}{"renamed", 1.5, 10}, stmt.PositionalParameters)
+
+	_, err = UpdateKeyspace(Keyspace{Bucket: "default"}).Where(Eq("a", 1)).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	// Updating every document must be asked for explicitly.
+	_, err = UpdateKeyspace(Keyspace{Bucket: "default"}).Set("a", 1).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	stmt, err = UpdateKeyspace(Keyspace{Bucket: "default"}).Set("a", 1).All().Build()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE `default`.`_default`.`_default` SET `a` = $1", stmt.Statement)
+}
+
+func TestDelete(t *testing.T) {
+	stmt, err := DeleteFrom(testCollection(t)).
+		Where(Eq("type", Param("t")), Not(IsNull("name"))).
+		Limit(100).
+		Returning(MetaID()).
+		Bind("t", "airline").
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE FROM `travel-sample`.`inventory`.`airline` WHERE `type` = $t AND NOT (`name` IS NULL) "+
+		"LIMIT 100 RETURNING META().id", stmt.Statement)
+	assert.Equal(t, map[string]interface{}{"t": "airline"}, stmt.NamedParameters)
+
+	_, err = DeleteFrom(testCollection(t)).Where(Eq("type", Param("t"))).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	// Deleting every document must be asked for explicitly.
+	_, err = DeleteFromKeyspace(Keyspace{Bucket: "default", Scope: "s"}).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	stmt, err = DeleteFromKeyspace(Keyspace{Bucket: "default", Scope: "s"}).All().Build()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `default`.`s`.`_default`", stmt.Statement)
+}
@@ -0,0 +1,118 @@
+package query
+
+import (
+	"strconv"
+
+	"github.com/lissteron/gocb"
+)
+
+// SelectBuilder builds a SELECT statement.
+type SelectBuilder struct {
+	fields   []Expr
+	keyspace *Keyspace
+	where    []Expr
+	orderBy  []Order
+	limit    *uint64
+	offset   *uint64
+	bindings map[string]interface{}
+}
+
+// Select starts a SELECT statement for the fields at the provided paths, each of which is escaped. "*" selects all
+// fields.
+func Select(fields ...string) *SelectBuilder {
+	return &SelectBuilder{
+		fields: fieldExprs(fields),
+	}
+}
+
+// SelectExprs starts a SELECT statement for the provided expressions.
+func SelectExprs(exprs ...Expr) *SelectBuilder {
+	return &SelectBuilder{
+		fields: exprs,
+	}
+}
+
+// From sets the collection to select from.
+func (sb *SelectBuilder) From(collection *gocb.Collection) *SelectBuilder {
+	keyspace := KeyspaceOf(collection)
+	sb.keyspace = &keyspace
+	return sb
+}
+
+// FromKeyspace sets the keyspace to select from.
+func (sb *SelectBuilder) FromKeyspace(keyspace Keyspace) *SelectBuilder {
+	sb.keyspace = &keyspace
+	return sb
+}
+
+// Where adds conditions to the statement, all of which must be true.
+func (sb *SelectBuilder) Where(conditions ...Expr) *SelectBuilder {
+	sb.where = append(sb.where, conditions...)
+	return sb
+}
+
+// OrderBy adds ordering terms to the statement.
+func (sb *SelectBuilder) OrderBy(orders ...Order) *SelectBuilder {
+	sb.orderBy = append(sb.orderBy, orders...)
+	return sb
+}
+
+// Limit sets the maximum number of results to return.
+func (sb *SelectBuilder) Limit(limit uint64) *SelectBuilder {
+	sb.limit = &limit
+	return sb
+}
+
+// Offset sets the number of results to skip.
+func (sb *SelectBuilder) Offset(offset uint64) *SelectBuilder {
+	sb.offset = &offset
+	return sb
+}
+
+// Bind sets the value of a named parameter.
+func (sb *SelectBuilder) Bind(name string, value interface{}) *SelectBuilder {
+	if sb.bindings == nil {
+		sb.bindings = make(map[string]interface{})
+	}
+	sb.bindings[name] = value
+	return sb
+}
+
+// Build returns the statement.
+func (sb *SelectBuilder) Build() (*Statement, error) {
+	return build(sb.write, sb.bindings)
+}
+
+func (sb *SelectBuilder) write(b *builder) {
+	if len(sb.fields) == 0 {
+		b.fail(invalidArgument("at least one field must be selected"))
+		return
+	}
+
+	b.WriteString("SELECT ")
+	b.writeExprs(sb.fields, ", ")
+
+	if sb.keyspace != nil {
+		b.WriteString(" FROM ")
+		sb.keyspace.write(b)
+	}
+
+	writeWhere(b, sb.where)
+
+	if len(sb.orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		for i, order := range sb.orderBy {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			order.write(b)
+		}
+	}
+
+	if sb.limit != nil {
+		b.WriteString(" LIMIT " + strconv.FormatUint(*sb.limit, 10))
+	}
+	if sb.offset != nil {
+		b.WriteString(" OFFSET " + strconv.FormatUint(*sb.offset, 10))
+	}
+}
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/lissteron/gocb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCollection(t *testing.T) *gocb.Collection {
+	cluster, err := gocb.Connect("couchbase://127.0.0.1", gocb.ClusterOptions{Username: "user", Password: "pass"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = cluster.Close(nil)
+	})
+
+	return cluster.Bucket("travel-sample").Scope("inventory").Collection("airline")
+}
+
+func TestSelect(t *testing.T) {
+	stmt, err := Select("name", "geo.lat").
+		From(testCollection(t)).
+		Where(Eq("type", Param("t")), Or(Gt("id", 10), IsMissing("id"))).
+		OrderBy(Asc("name"), Desc("geo.lat")).
+		Limit(10).
+		Offset(5).
+		Bind("t", "airline").
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `name`, `geo`.`lat` FROM `travel-sample`.`inventory`.`airline` "+
+		"WHERE `type` = $t AND (`id` > $_p1 OR `id` IS MISSING) ORDER BY `name` ASC, `geo`.`lat` DESC "+
+		"LIMIT 10 OFFSET 5", stmt.Statement)
+	assert.Equal(t, map[string]interface{}{"t": "airline", "_p1": 10}, stmt.NamedParameters)
+	assert.Nil(t, stmt.PositionalParameters)
+
+	opts := stmt.Apply(&gocb.QueryOptions{NamedParameters: map[string]interface{}{"other": 1}})
+	assert.Equal(t, map[string]interface{}{"t": "airline", "_p1": 10, "other": 1}, opts.NamedParameters)
+}
+
+func TestSelectPositional(t *testing.T) {
+	stmt, err := SelectExprs(As(MetaID(), "id"), Raw("*")).
+		FromKeyspace(Keyspace{Bucket: "default"}).
+		Where(Eq("country", "France"), In("city", []string{"Paris", "Lyon"})).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT META().id AS `id`, * FROM `default`.`_default`.`_default` "+
+		"WHERE `country` = $1 AND `city` IN $2", stmt.Statement)
+	assert.Equal(t, []interface{}{"France", []string{"Paris", "Lyon"}}, stmt.PositionalParameters)
+	assert.Nil(t, stmt.NamedParameters)
+
+	opts := stmt.Apply(nil)
+	assert.Equal(t, stmt.PositionalParameters, opts.PositionalParameters)
+}
+
+func TestSelectEscaping(t *testing.T) {
+	stmt, err := Select("*").
+		FromKeyspace(Keyspace{Bucket: "my`bucket", Scope: "s", Collection: "c"}).
+		Where(Eq("name` = 1 OR `a", "x")).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT * FROM `my``bucket`.`s`.`c` WHERE `name`` = 1 OR ``a` = $1", stmt.Statement)
+}
+
+func TestEscapePath(t *testing.T) {
+	assert.Equal(t, "`geo`.`lat`", EscapePath("geo.lat"))
+	assert.Equal(t, "`tags`[0]", EscapePath("tags[0]"))
+	assert.Equal(t, "`a`.`b`[1][-2].`c`", EscapePath("a.b[1][-2].c"))
+	assert.Equal(t, "`a[x]`", EscapePath("a[x]"))
+	assert.Equal(t, "`[0]`", EscapePath("[0]"))
+	assert.Equal(t, "`a```[0]", EscapePath("a`[0]"))
+}
+
+func TestSelectInvalid(t *testing.T) {
+	_, err := Select().Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	_, err = Select("*").FromKeyspace(Keyspace{}).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	_, err = Select("*").From(nil).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	_, err = Select("*").Where(Eq("a", Param("a; DROP"))).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	_, err = Select("*").Where(Eq("a", Param("_p1"))).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	_, err = Select("*").Where(Eq("a", Param("a"))).Bind("b", 1).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	_, err = Select("*").Where(Or()).Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+
+	_, err = Select("").Build()
+	assert.ErrorIs(t, err, gocb.ErrInvalidArgument)
+}
@@ -0,0 +1,223 @@
+// Package query provides a builder for SQL++ (N1QL) statements. Identifiers are always escaped and values are always
+// passed as query parameters rather than being written into the statement, so that statements built from user input
+// are not open to injection.
+// VOLATILE: This API is subject to change at any time.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lissteron/gocb"
+)
+
+// generatedParamPrefix is used for the names of the parameters created for values when a statement also uses named
+// parameters, as named and positional parameters cannot be mixed within a single query.
+const generatedParamPrefix = "_p"
+
+var paramNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pathIndexRegexp matches an element of a path which ends in one or more array indexes, such as "tags[0]".
+var pathIndexRegexp = regexp.MustCompile(`^(.+?)((?:\[-?[0-9]+\])+)$`)
+
+// Statement is a built SQL++ statement along with the parameters that it references. Only one of NamedParameters
+// and PositionalParameters is populated.
+type Statement struct {
+	Statement            string
+	NamedParameters      map[string]interface{}
+	PositionalParameters []interface{}
+}
+
+// Apply sets the parameters of the statement on the provided options, creating new options if opts is nil. Named
+// parameters are merged with any already present on opts.
+func (s *Statement) Apply(opts *gocb.QueryOptions) *gocb.QueryOptions {
+	if opts == nil {
+		opts = &gocb.QueryOptions{}
+	}
+
+	if len(s.NamedParameters) > 0 {
+		if opts.NamedParameters == nil {
+			opts.NamedParameters = make(map[string]interface{}, len(s.NamedParameters))
+		}
+		for name, value := range s.NamedParameters {
+			opts.NamedParameters[name] = value
+		}
+	}
+	if len(s.PositionalParameters) > 0 {
+		opts.PositionalParameters = s.PositionalParameters
+	}
+
+	return opts
+}
+
+// Keyspace identifies the collection that a statement operates on.
+type Keyspace struct {
+	Bucket     string
+	Scope      string
+	Collection string
+}
+
+// KeyspaceOf returns the Keyspace of the provided collection.
+func KeyspaceOf(collection *gocb.Collection) Keyspace {
+	if collection == nil || collection.Bucket() == nil {
+		return Keyspace{}
+	}
+
+	return Keyspace{
+		Bucket:     collection.Bucket().Name(),
+		Scope:      collection.ScopeName(),
+		Collection: collection.Name(),
+	}
+}
+
+func (k Keyspace) write(b *builder) {
+	if k.Bucket == "" {
+		b.fail(invalidArgument("a keyspace must have a bucket"))
+		return
+	}
+
+	// If the DefaultX functions on bucket are used then the names will be empty.
+	scope := k.Scope
+	if scope == "" {
+		scope = "_default"
+	}
+	collection := k.Collection
+	if collection == "" {
+		collection = "_default"
+	}
+
+	b.WriteString(EscapeIdentifier(k.Bucket))
+	b.WriteByte('.')
+	b.WriteString(EscapeIdentifier(scope))
+	b.WriteByte('.')
+	b.WriteString(EscapeIdentifier(collection))
+}
+
+// EscapeIdentifier escapes an identifier with backticks so that it is always treated as an identifier, no matter the
+// characters that it contains.
+func EscapeIdentifier(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+// EscapePath escapes each dot separated element of a path, such as "geo.lat", with backticks. Array indexes at the
+// end of an element, such as "tags[0]", are left outside of the backticks.
+func EscapePath(path string) string {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		if match := pathIndexRegexp.FindStringSubmatch(part); match != nil {
+			parts[i] = EscapeIdentifier(match[1]) + match[2]
+			continue
+		}
+		parts[i] = EscapeIdentifier(part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func invalidArgument(format string, args ...interface{}) error {
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), gocb.ErrInvalidArgument)
+}
+
+// builder accumulates the statement text and parameters as a statement is built.
+type builder struct {
+	strings.Builder
+
+	// bindings are the values set with Bind, every Param in the statement must have one.
+	bindings map[string]interface{}
+
+	// named is set when the statement uses named parameters, in which case values are also bound by name.
+	named      bool
+	bound      map[string]interface{}
+	namedUsed  map[string]struct{}
+	positional []interface{}
+	err        error
+}
+
+func (b *builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *builder) writeValue(value interface{}) {
+	if b.named {
+		name := generatedParamPrefix + strconv.Itoa(len(b.bound)+1)
+		b.bound[name] = value
+		b.WriteString("$" + name)
+		return
+	}
+
+	b.positional = append(b.positional, value)
+	b.WriteString("$" + strconv.Itoa(len(b.positional)))
+}
+
+func (b *builder) writeParam(name string) {
+	if !paramNameRegexp.MatchString(name) {
+		b.fail(invalidArgument("invalid parameter name %q", name))
+		return
+	}
+	if strings.HasPrefix(name, generatedParamPrefix) {
+		b.fail(invalidArgument("parameter names beginning with %s are reserved", generatedParamPrefix))
+		return
+	}
+	if _, ok := b.bindings[name]; !ok {
+		b.fail(invalidArgument("parameter %q has not been bound", name))
+		return
+	}
+
+	if b.namedUsed == nil {
+		b.namedUsed = make(map[string]struct{})
+	}
+	b.namedUsed[name] = struct{}{}
+	b.WriteString("$" + name)
+}
+
+func (b *builder) writeExprs(exprs []Expr, sep string) {
+	for i, expr := range exprs {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		expr.write(b)
+	}
+}
+
+// build writes the statement twice, the first time to discover whether named parameters are in use so that values
+// can be bound in the same way.
+func build(write func(b *builder), bindings map[string]interface{}) (*Statement, error) {
+	probe := &builder{
+		bindings: bindings,
+	}
+	write(probe)
+	if probe.err != nil {
+		return nil, probe.err
+	}
+
+	b := &builder{
+		named:    len(probe.namedUsed) > 0 || len(bindings) > 0,
+		bindings: bindings,
+		bound:    make(map[string]interface{}),
+	}
+	write(b)
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	stmt := &Statement{
+		Statement: b.String(),
+	}
+	if b.named {
+		for name, value := range bindings {
+			if !paramNameRegexp.MatchString(name) || strings.HasPrefix(name, generatedParamPrefix) {
+				return nil, invalidArgument("invalid parameter name %q", name)
+			}
+			b.bound[name] = value
+		}
+		stmt.NamedParameters = b.bound
+	} else {
+		stmt.PositionalParameters = b.positional
+	}
+
+	return stmt, nil
+}
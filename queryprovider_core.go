@@ -24,12 +24,19 @@ type queryProviderCore struct {
 	meter                *meterWrapper
 }
 
-func (qpc *queryProviderCore) Query(statement string, s *Scope, opts *QueryOptions) (*QueryResult, error) {
+func (qpc *queryProviderCore) Query(statement string, s *Scope, opts *QueryOptions) (resOut *QueryResult, errOut error) {
 	start := time.Now()
-	defer qpc.meter.ValueRecord(meterValueServiceQuery, "query", start)
+	defer func() {
+		op := meterOperation{service: meterValueServiceQuery, operation: "query"}
+		if s != nil {
+			op.bucket = s.BucketName()
+			op.scope = s.Name()
+		}
+		qpc.meter.ValueRecordOutcome(op, errOut, start)
+	}()
 
 	span := createSpan(qpc.tracer, opts.ParentSpan, "query", "query")
-	span.SetAttribute("db.statement", statement)
+	span.SetAttribute(spanAttribDBStatementKey, redactUserData(statement))
 	if s != nil {
 		span.SetAttribute("db.name", s.BucketName())
 		span.SetAttribute("db.couchbase.scope", s.Name())
@@ -40,6 +47,7 @@ func (qpc *queryProviderCore) Query(statement string, s *Scope, opts *QueryOptio
 	if opts.RetryStrategy != nil {
 		retryStrategy = newCoreRetryStrategyWrapper(opts.RetryStrategy)
 	}
+	retryStrategy = retryStrategy.forService(ServiceTypeQuery, qpc.meter)
 
 	queryOpts, err := opts.toMap()
 	if err != nil {
@@ -95,7 +103,14 @@ func (qpc *queryProviderCore) Query(statement string, s *Scope, opts *QueryOptio
 		})
 	}
 	if qErr != nil {
-		return nil, maybeEnhanceCoreQueryError(qErr)
+		err := maybeEnhanceCoreQueryError(qErr)
+		// The client context ID is generated as a uuid.UUID when one isn't provided.
+		logDebugfWithFields([]LogField{
+			{Key: LogFieldComponent, Value: "query"},
+			{Key: LogFieldClientContextID, Value: fmt.Sprint(queryOpts["client_context_id"])},
+			{Key: LogFieldError, Value: err},
+		}, "Query failed: %s", err)
+		return nil, err
 	}
 
 	return newQueryResult(&queryProviderCoreRowReader{reader: res}), nil
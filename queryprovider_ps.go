@@ -24,7 +24,7 @@ type queryProviderPs struct {
 
 func (qpc *queryProviderPs) Query(statement string, s *Scope, opts *QueryOptions) (*QueryResult, error) {
 	attribs := map[string]interface{}{
-		"db.statement": statement,
+		spanAttribDBStatementKey: statement,
 	}
 	if s != nil {
 		attribs["db.name"] = s.BucketName()
@@ -208,8 +208,9 @@ func (qpc *queryProviderPs) Query(statement string, s *Scope, opts *QueryOptions
 		cli:        res,
 		cancelFunc: reqCancel,
 
-		statement: statement,
-		readOnly:  opts.Readonly,
+		statement:       statement,
+		readOnly:        opts.Readonly,
+		clientContextID: *req.ClientContextId,
 
 		nextRows: firstRows.Rows,
 		meta:     firstRows.MetaData,
@@ -254,8 +255,9 @@ type queryProviderPsRowReader struct {
 	cli        query_v1.QueryService_QueryClient
 	cancelFunc context.CancelFunc
 
-	statement string
-	readOnly  bool
+	statement       string
+	readOnly        bool
+	clientContextID string
 
 	nextRowsIndex int
 	nextRows      [][]byte
@@ -387,6 +389,12 @@ func (q *queryProviderPsRowReader) Close() error {
 	return err
 }
 
+// cancel cancels the request, causing a pending Recv to fail. It only cancels the context of the request and so, unlike
+// Close, is safe to call whilst another goroutine is reading rows.
+func (q *queryProviderPsRowReader) cancel() {
+	q.cancelFunc()
+}
+
 func (q *queryProviderPsRowReader) PreparedName() (string, error) {
 	return "", nil
 }
@@ -395,12 +403,20 @@ func (q *queryProviderPsRowReader) Endpoint() string {
 	return ""
 }
 
+func (r *queryProviderPsRowReader) logFields(err error) []LogField {
+	return []LogField{
+		{Key: LogFieldComponent, Value: "query"},
+		{Key: LogFieldClientContextID, Value: r.clientContextID},
+		{Key: LogFieldError, Value: err},
+	}
+}
+
 func (r *queryProviderPsRowReader) finishWithoutError() {
 	r.cancelFunc()
 	// Close the stream now that we are done with it
 	err := r.cli.CloseSend()
 	if err != nil {
-		logWarnf("query stream close failed after meta-data: %s", err)
+		logWarnfWithFields(r.logFields(err), "query stream close failed after meta-data: %s", err)
 	}
 
 	r.cli = nil
@@ -416,7 +432,7 @@ func (r *queryProviderPsRowReader) finishWithError(err error) {
 	if closeErr != nil {
 		// We log this at debug level, but its almost always going to be an
 		// error since thats the most likely reason we are in finishWithError
-		logDebugf("query stream close failed after error: %s", closeErr)
+		logDebugfWithFields(r.logFields(closeErr), "query stream close failed after error: %s", closeErr)
 	}
 
 	// Our client is invalidated as soon as an error occurs
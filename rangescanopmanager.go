@@ -21,7 +21,8 @@ const (
 )
 
 type rangeScanOpManager struct {
-	err error
+	err   error
+	opErr error
 
 	span        RequestSpan
 	transcoder  Transcoder
@@ -90,8 +91,8 @@ func (p *kvProviderCore) newRangeScanOpManager(c *Collection, scanType ScanType,
 		}
 
 		span.SetAttribute("scan_type", "range")
-		span.SetAttribute("from_term", st.From.Term)
-		span.SetAttribute("to_term", st.To.Term)
+		span.SetAttribute("from_term", redactUserData(st.From.Term))
+		span.SetAttribute("to_term", redactUserData(st.To.Term))
 		var err error
 		rangeOptions, err = st.toCore()
 		if err != nil {
@@ -255,7 +256,14 @@ func (m *rangeScanOpManager) SetImpersonate(user string) {
 func (m *rangeScanOpManager) Finish() {
 	m.span.End()
 
-	m.meter.ValueRecord(meterValueServiceKV, "range_scan", m.createdTime)
+	op := meterOperation{
+		service:    meterValueServiceKV,
+		operation:  "range_scan",
+		bucket:     m.bucketName,
+		scope:      m.scopeName,
+		collection: m.collectionName,
+	}
+	m.meter.ValueRecordOutcome(op, m.opErr, m.createdTime)
 }
 
 func (m *rangeScanOpManager) TraceSpanContext() RequestSpanContext {
@@ -301,6 +309,7 @@ func (m *rangeScanOpManager) KeysOnly() bool {
 
 func (m *rangeScanOpManager) CheckReadyForOp() error {
 	if m.err != nil {
+		m.opErr = m.err
 		return m.err
 	}
 
@@ -346,6 +355,7 @@ func (m *rangeScanOpManager) IsRangeScan() bool {
 func (m *rangeScanOpManager) cancelScan(err error) {
 	if atomic.CompareAndSwapUint32(&m.cancelled, 0, 1) {
 		if err != nil {
+			m.opErr = err
 			m.result.setErr(err)
 		}
 		close(m.cancelCh)
@@ -603,8 +613,8 @@ func (m *rangeScanOpManager) createStream(ctx context.Context, spanCtx RequestSp
 		span.SetAttribute("seed", samplingOpts.Seed)
 	} else if rangeOpts != nil {
 		span.SetAttribute("scan_type", "range")
-		span.SetAttribute("from_term", string(rangeOpts.Start))
-		span.SetAttribute("to_term", string(rangeOpts.End))
+		span.SetAttribute("from_term", redactUserData(string(rangeOpts.Start)))
+		span.SetAttribute("to_term", redactUserData(string(rangeOpts.End)))
 		span.SetAttribute("from_exclusive", len(rangeOpts.ExclusiveStart) > 0)
 		span.SetAttribute("to_exclusive", len(rangeOpts.ExclusiveEnd) > 0)
 	}
@@ -0,0 +1,197 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RowOrErr is a single item sent on the channels returned by QueryStream, AnalyticsStream and ViewStream. Err is set
+// if the row could not be decoded or the stream failed, in which case it is the last item sent.
+// VOLATILE: This API is subject to change at any time.
+type RowOrErr[T any] struct {
+	Row T
+	Err error
+}
+
+// TypedViewRow represents a single row returned from a view query, with the key and value decoded.
+// VOLATILE: This API is subject to change at any time.
+type TypedViewRow[K any, V any] struct {
+	ID    string `json:"id"`
+	Key   K      `json:"key"`
+	Value V      `json:"value"`
+}
+
+// QueryRows reads all of the remaining rows from the result, decoding each into a T, and then closes the result.
+// The meta-data remains available from the result once the rows have been read.
+// VOLATILE: This API is subject to change at any time.
+func QueryRows[T any](res *QueryResult) ([]T, error) {
+	raw, err := res.rows()
+	if err != nil {
+		return nil, err
+	}
+
+	return readRows[T](raw)
+}
+
+// QueryStream reads the remaining rows from the result in the background, decoding each into a T and sending it on
+// the returned channel. Rows are only read from the result as they are received from the channel. The channel is
+// closed, and the result closed, once all rows have been read, an error has occurred or ctx is done.
+// No error is sent when the channel is closed because ctx is done, so callers must check ctx.Err() once the channel
+// has been closed to tell whether all of the rows were received.
+// When using couchbase2:// connection strings a read which is waiting for the server to send the next row is cancelled
+// as soon as ctx is done. Results from couchbase:// connection strings cannot be cancelled whilst being read, so the
+// channel is instead closed once the pending row arrives or the query times out.
+// VOLATILE: This API is subject to change at any time.
+func QueryStream[T any](ctx context.Context, res *QueryResult) <-chan RowOrErr[T] {
+	raw, err := res.rows()
+	return streamRows[T](ctx, raw, err)
+}
+
+// AnalyticsRows reads all of the remaining rows from the result, decoding each into a T, and then closes the result.
+// The meta-data remains available from the result once the rows have been read.
+// VOLATILE: This API is subject to change at any time.
+func AnalyticsRows[T any](res *AnalyticsResult) ([]T, error) {
+	raw, err := res.rows()
+	if err != nil {
+		return nil, err
+	}
+
+	return readRows[T](raw)
+}
+
+// AnalyticsStream reads the remaining rows from the result in the background, see QueryStream. As with QueryStream
+// callers must check ctx.Err() once the channel has been closed to tell whether all of the rows were received.
+// VOLATILE: This API is subject to change at any time.
+func AnalyticsStream[T any](ctx context.Context, res *AnalyticsResult) <-chan RowOrErr[T] {
+	raw, err := res.rows()
+	return streamRows[T](ctx, raw, err)
+}
+
+// ViewRows reads all of the remaining rows from the result, decoding the key of each into a K and the value into a V,
+// and then closes the result. The meta-data remains available from the result once the rows have been read.
+// VOLATILE: This API is subject to change at any time.
+func ViewRows[K any, V any](res *ViewResult) ([]TypedViewRow[K, V], error) {
+	raw, err := res.rows()
+	if err != nil {
+		return nil, err
+	}
+
+	return readRows[TypedViewRow[K, V]](raw)
+}
+
+// ViewStream reads the remaining rows from the result in the background, see QueryStream. As with QueryStream
+// callers must check ctx.Err() once the channel has been closed to tell whether all of the rows were received.
+// VOLATILE: This API is subject to change at any time.
+func ViewStream[K any, V any](ctx context.Context, res *ViewResult) <-chan RowOrErr[TypedViewRow[K, V]] {
+	raw, err := res.rows()
+	return streamRows[TypedViewRow[K, V]](ctx, raw, err)
+}
+
+// rawRowReader is implemented by each of the raw result types.
+type rawRowReader interface {
+	NextBytes() []byte
+	Close() error
+}
+
+// rowReaderCanceler is implemented by row readers, and raw results, which can be cancelled whilst being read.
+type rowReaderCanceler interface {
+	// cancel causes a pending or future read to stop, the reader then finishes with an error. Unlike the other methods
+	// of a reader it must be safe to call concurrently with them.
+	cancel()
+}
+
+func cancelRowReader(reader interface{}) {
+	if canceler, ok := reader.(rowReaderCanceler); ok {
+		canceler.cancel()
+	}
+}
+
+func readRows[T any](reader rawRowReader) ([]T, error) {
+	var rows []T
+	for rowBytes := reader.NextBytes(); rowBytes != nil; rowBytes = reader.NextBytes() {
+		var row T
+		if err := json.Unmarshal(rowBytes, &row); err != nil {
+			closeErr := reader.Close()
+			if closeErr != nil {
+				logDebugf("Failed to close result after decode error: %v", closeErr)
+			}
+			return nil, err
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := reader.Close(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func streamRows[T any](ctx context.Context, reader rawRowReader, err error) <-chan RowOrErr[T] {
+	// The channel is unbuffered so that rows are not read from the result until the receiver is ready for them.
+	ch := make(chan RowOrErr[T])
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	go func() {
+		defer close(ch)
+
+		send := func(item RowOrErr[T]) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+
+			select {
+			case ch <- item:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if err != nil {
+			send(RowOrErr[T]{Err: err})
+			return
+		}
+
+		// Reading the next row blocks until the server sends it, so the read is cancelled as soon as ctx is done rather
+		// than waiting for the row to arrive. Readers are not safe for concurrent use, so this must not close it.
+		if _, ok := reader.(rowReaderCanceler); ok {
+			readDone := make(chan struct{})
+			defer close(readDone)
+			go func() {
+				select {
+				case <-ctx.Done():
+					cancelRowReader(reader)
+				case <-readDone:
+				}
+			}()
+		}
+
+		for rowBytes := reader.NextBytes(); rowBytes != nil; rowBytes = reader.NextBytes() {
+			var row T
+			if err := json.Unmarshal(rowBytes, &row); err != nil {
+				if closeErr := reader.Close(); closeErr != nil {
+					logDebugf("Failed to close result after decode error: %v", closeErr)
+				}
+				send(RowOrErr[T]{Err: err})
+				return
+			}
+
+			if !send(RowOrErr[T]{Row: row}) {
+				if closeErr := reader.Close(); closeErr != nil {
+					logDebugf("Failed to close result after context done: %v", closeErr)
+				}
+				return
+			}
+		}
+
+		if err := reader.Close(); err != nil {
+			send(RowOrErr[T]{Err: err})
+		}
+	}()
+
+	return ch
+}
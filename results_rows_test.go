@@ -0,0 +1,268 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/couchbase/goprotostellar/genproto/analytics_v1"
+)
+
+func (suite *UnitTestSuite) TestQueryRows() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+	res := newQueryResult(reader)
+
+	rows, err := QueryRows[testBreweryDocument](res)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(dataset.Results, rows)
+
+	// The meta-data is still available once the rows have been read.
+	meta, err := res.MetaData()
+	suite.Require().NoError(err)
+	suite.Assert().Equal(dataset.RequestID, meta.RequestID)
+
+	reader = &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			CloseErr: ErrTimeout,
+			Suite:    suite,
+		},
+	}
+	_, err = QueryRows[testBreweryDocument](newQueryResult(reader))
+	suite.Assert().ErrorIs(err, ErrTimeout)
+
+	reader = &mockQueryRowReader{
+		Dataset:                dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+	}
+	_, err = QueryRows[int](newQueryResult(reader))
+	suite.Assert().Error(err)
+}
+
+func (suite *UnitTestSuite) TestQueryStream() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			CloseErr: ErrTimeout,
+			Suite:    suite,
+		},
+	}
+
+	var rows []testBreweryDocument
+	var streamErr error
+	for item := range QueryStream[testBreweryDocument](context.Background(), newQueryResult(reader)) {
+		if item.Err != nil {
+			streamErr = item.Err
+			continue
+		}
+		rows = append(rows, item.Row)
+	}
+	suite.Assert().Equal(dataset.Results, rows)
+	suite.Assert().ErrorIs(streamErr, ErrTimeout)
+
+	// Rows are only read as they are received, cancelling the context stops the stream.
+	reader = &mockQueryRowReader{
+		Dataset:                dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := QueryStream[testBreweryDocument](ctx, newQueryResult(reader))
+	item := <-ch
+	suite.Require().NoError(item.Err)
+	suite.Assert().Equal(dataset.Results[0], item.Row)
+	cancel()
+
+	// The truncated stream ends without an error, only the context says that rows are missing.
+	var received int
+	for item := range ch {
+		suite.Assert().NoError(item.Err)
+		received++
+	}
+	suite.Assert().Less(1+received, len(dataset.Results))
+	suite.Assert().Less(reader.idx, len(dataset.Results))
+	suite.Assert().ErrorIs(ctx.Err(), context.Canceled)
+}
+
+type blockingQueryRowReader struct {
+	mockQueryRowReaderBase
+
+	rows       chan []byte
+	cancelled  chan struct{}
+	cancelOnce sync.Once
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+func (r *blockingQueryRowReader) NextRow() []byte {
+	select {
+	case row := <-r.rows:
+		return row
+	case <-r.cancelled:
+		return nil
+	}
+}
+
+func (r *blockingQueryRowReader) cancel() {
+	r.cancelOnce.Do(func() {
+		close(r.cancelled)
+	})
+}
+
+func (r *blockingQueryRowReader) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+	})
+	return nil
+}
+
+func (suite *UnitTestSuite) TestQueryStreamCancelWhileReading() {
+	reader := &blockingQueryRowReader{
+		rows:      make(chan []byte, 1),
+		cancelled: make(chan struct{}),
+		closed:    make(chan struct{}),
+	}
+	reader.rows <- []byte(`1`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := QueryStream[int](ctx, newQueryResult(reader))
+
+	// The stream is now blocked waiting for the server to send the next row.
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		for ok {
+			_, ok = <-ch
+		}
+	case <-time.After(time.Second):
+		suite.T().Fatal("stream was not closed when the context was cancelled")
+	}
+
+	select {
+	case <-reader.closed:
+	default:
+		suite.T().Fatal("result was not closed")
+	}
+}
+
+func (suite *UnitTestSuite) TestAnalyticsStreamCancelWhileReadingPs() {
+	stream := &fakePsAnalyticsStream{
+		responses: []*analytics_v1.AnalyticsQueryResponse{
+			{Rows: [][]byte{[]byte(`1`)}},
+			{Rows: [][]byte{[]byte(`2`)}},
+		},
+		block: true,
+	}
+	provider := suite.newPsAnalyticsProvider(&fakePsAnalyticsClient{stream: stream})
+
+	scope := &Scope{bucket: &Bucket{bucketName: "travel"}, scopeName: "inventory"}
+	result, err := provider.AnalyticsQuery("SELECT 1", scope, &AnalyticsOptions{})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := AnalyticsStream[int](ctx, result)
+
+	var rows []int
+	for i := 0; i < 2; i++ {
+		item := <-ch
+		suite.Require().NoError(item.Err)
+		rows = append(rows, item.Row)
+	}
+	suite.Assert().Equal([]int{1, 2}, rows)
+
+	// The stream is now blocked waiting for the server to send the next response, cancelling must unblock the read
+	// without racing with it.
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		for ok {
+			_, ok = <-ch
+		}
+	case <-time.After(time.Second):
+		suite.T().Fatal("stream was not closed when the context was cancelled")
+	}
+
+	suite.Assert().Error(stream.ctx.Err())
+}
+
+func (suite *UnitTestSuite) TestAnalyticsRows() {
+	var dataset testAnalyticsDataset
+	err := loadJSONTestDataset("beer_sample_analytics_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockAnalyticsRowReader{
+		Dataset: dataset.Results,
+		Meta:    suite.mustConvertToBytes(dataset.jsonAnalyticsResponse),
+		Suite:   suite,
+	}
+	res := newAnalyticsResult(reader)
+
+	rows, err := AnalyticsRows[testBreweryDocument](res)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(dataset.Results, rows)
+
+	_, err = res.MetaData()
+	suite.Require().NoError(err)
+
+	reader = &mockAnalyticsRowReader{
+		Dataset:  dataset.Results,
+		CloseErr: errors.New("stream failed"),
+		Suite:    suite,
+	}
+	var count int
+	var streamErr error
+	for item := range AnalyticsStream[testBreweryDocument](context.Background(), newAnalyticsResult(reader)) {
+		if item.Err != nil {
+			streamErr = item.Err
+			continue
+		}
+		count++
+	}
+	suite.Assert().Equal(len(dataset.Results), count)
+	suite.Assert().Error(streamErr)
+}
+
+func (suite *UnitTestSuite) TestViewRows() {
+	reader := &mockViewRowReader{
+		Dataset: []jsonViewRow{
+			{ID: "a", Key: []byte(`["a",1]`), Value: []byte(`{"name":"first"}`)},
+			{ID: "b", Key: []byte(`["b",2]`), Value: []byte(`{"name":"second"}`)},
+		},
+		Suite: suite,
+	}
+
+	type value struct {
+		Name string `json:"name"`
+	}
+	rows, err := ViewRows[[]interface{}, value](newViewResult(reader))
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 2)
+	suite.Assert().Equal("a", rows[0].ID)
+	suite.Assert().Equal([]interface{}{"a", float64(1)}, rows[0].Key)
+	suite.Assert().Equal(value{Name: "second"}, rows[1].Value)
+
+	res := newViewResult(reader)
+	res.Raw()
+	_, err = ViewRows[string, string](res)
+	suite.Assert().Error(err)
+
+	for item := range ViewStream[string, string](context.Background(), res) {
+		suite.Assert().Error(item.Err)
+	}
+}
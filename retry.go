@@ -3,6 +3,9 @@ package gocb
 import (
 	"context"
 	"errors"
+	"math"
+	"math/rand"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
@@ -152,6 +155,413 @@ func (rs *BestEffortRetryStrategy) RetryAfter(req RetryRequest, reason RetryReas
 	return &NoRetryRetryAction{}
 }
 
+// FailFastRetryStrategy represents a strategy that will never retry. Operations will only be retried when the
+// reason for failure is one that must always be retried, such as the operation being sent to the wrong node.
+type FailFastRetryStrategy struct {
+}
+
+// NewFailFastRetryStrategy returns a new FailFastRetryStrategy.
+func NewFailFastRetryStrategy() *FailFastRetryStrategy {
+	return &FailFastRetryStrategy{}
+}
+
+// RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
+func (rs *FailFastRetryStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
+	return &NoRetryRetryAction{}
+}
+
+// RetryJitter specifies the type of jitter applied to backoff durations by the ExponentialJitterRetryStrategy.
+type RetryJitter uint
+
+const (
+	// RetryJitterFull indicates that the backoff will be a random duration between zero and the exponential
+	// backoff for the current attempt.
+	RetryJitterFull RetryJitter = iota
+
+	// RetryJitterDecorrelated indicates that the backoff will be a random duration between the minimum backoff and
+	// an upper bound which starts at three times the minimum backoff and triples with every attempt. Retry strategies
+	// are shared between requests and so do not know the previous backoff of a request, the upper bound therefore
+	// depends only on the number of attempts rather than on the previous backoff as in classic decorrelated jitter.
+	RetryJitterDecorrelated
+)
+
+// ExponentialJitterRetryStrategy represents a strategy that will keep retrying until it succeeds (or the caller times
+// out the request), using an exponential backoff with jitter applied to spread out retries from concurrent requests.
+type ExponentialJitterRetryStrategy struct {
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	Jitter     RetryJitter
+}
+
+// NewExponentialJitterRetryStrategy returns a new ExponentialJitterRetryStrategy. If minBackoff is 0 then 1ms
+// will be used, if maxBackoff is 0 then 500ms will be used.
+func NewExponentialJitterRetryStrategy(minBackoff, maxBackoff time.Duration, jitter RetryJitter) *ExponentialJitterRetryStrategy {
+	if minBackoff == 0 {
+		minBackoff = 1 * time.Millisecond
+	}
+	if maxBackoff == 0 {
+		maxBackoff = 500 * time.Millisecond
+	}
+
+	return &ExponentialJitterRetryStrategy{
+		MinBackoff: minBackoff,
+		MaxBackoff: maxBackoff,
+		Jitter:     jitter,
+	}
+}
+
+// RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
+func (rs *ExponentialJitterRetryStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
+	if req.Idempotent() || reason.AllowsNonIdempotentRetry() {
+		return &WithDurationRetryAction{WithDuration: rs.backoff(req.RetryAttempts())}
+	}
+
+	return &NoRetryRetryAction{}
+}
+
+func (rs *ExponentialJitterRetryStrategy) backoff(retryAttempts uint32) time.Duration {
+	minBackoff := float64(rs.MinBackoff)
+	maxBackoff := float64(rs.MaxBackoff)
+
+	var lower, upper float64
+	switch rs.Jitter {
+	case RetryJitterDecorrelated:
+		// Retry strategies are shared between requests so rather than tracking the previous backoff of each request
+		// we derive its upper bound from the number of attempts.
+		lower = minBackoff
+		upper = math.Min(maxBackoff, minBackoff*math.Pow(3, float64(retryAttempts+1)))
+	default:
+		upper = math.Min(maxBackoff, minBackoff*math.Pow(2, float64(retryAttempts)))
+	}
+
+	if upper <= lower {
+		return time.Duration(upper)
+	}
+
+	duration := time.Duration(lower + rand.Float64()*(upper-lower)) // #nosec G404
+	if duration <= 0 {
+		// A zero duration indicates that the request should not be retried.
+		duration = time.Microsecond
+	}
+
+	return duration
+}
+
+// PolicyRetryStrategy represents a strategy which delegates to other strategies depending on the reason for the
+// retry and the service that the request is for. Policies for retry reasons take precedence over policies for
+// services, if no policy applies then the default strategy is used.
+type PolicyRetryStrategy struct {
+	Default         RetryStrategy
+	ReasonPolicies  map[RetryReason]RetryStrategy
+	ServicePolicies map[ServiceType]RetryStrategy
+}
+
+// NewPolicyRetryStrategy returns a new PolicyRetryStrategy using the supplied strategy when no policy applies to a
+// request. If defaultStrategy is nil then a BestEffortRetryStrategy will be used.
+func NewPolicyRetryStrategy(defaultStrategy RetryStrategy) *PolicyRetryStrategy {
+	if defaultStrategy == nil {
+		defaultStrategy = NewBestEffortRetryStrategy(nil)
+	}
+
+	return &PolicyRetryStrategy{
+		Default:         defaultStrategy,
+		ReasonPolicies:  make(map[RetryReason]RetryStrategy),
+		ServicePolicies: make(map[ServiceType]RetryStrategy),
+	}
+}
+
+// WithReasonPolicy sets the strategy to use for retries due to the given reason.
+func (rs *PolicyRetryStrategy) WithReasonPolicy(reason RetryReason, strategy RetryStrategy) *PolicyRetryStrategy {
+	if rs.ReasonPolicies == nil {
+		rs.ReasonPolicies = make(map[RetryReason]RetryStrategy)
+	}
+	rs.ReasonPolicies[reason] = strategy
+	return rs
+}
+
+// WithServicePolicy sets the strategy to use for retries of requests for the given service.
+func (rs *PolicyRetryStrategy) WithServicePolicy(service ServiceType, strategy RetryStrategy) *PolicyRetryStrategy {
+	if rs.ServicePolicies == nil {
+		rs.ServicePolicies = make(map[ServiceType]RetryStrategy)
+	}
+	rs.ServicePolicies[service] = strategy
+	return rs
+}
+
+// RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
+func (rs *PolicyRetryStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
+	if strategy, ok := rs.ReasonPolicies[reason]; ok && strategy != nil {
+		return strategy.RetryAfter(req, reason)
+	}
+
+	if len(rs.ServicePolicies) > 0 {
+		if service, ok := retryRequestService(req, reason); ok {
+			if strategy, ok := rs.ServicePolicies[service]; ok && strategy != nil {
+				return strategy.RetryAfter(req, reason)
+			}
+		}
+	}
+
+	if rs.Default == nil {
+		return &NoRetryRetryAction{}
+	}
+
+	return rs.Default.RetryAfter(req, reason)
+}
+
+func (rs *PolicyRetryStrategy) requiresService() bool {
+	return len(rs.ServicePolicies) > 0
+}
+
+// RetryBudgetOptions are the options available when creating a RetryBudget.
+type RetryBudgetOptions struct {
+	// Percent is the percentage of requests that are allowed to be retried, defaults to 10.
+	Percent float64
+	// MinRetriesPerSecond is the number of retries per second that are always allowed regardless of the number of
+	// requests, so that retries are still possible at low traffic. Defaults to 10.
+	MinRetriesPerSecond uint32
+	// MaxTokens is the maximum number of retries that can be accumulated by the budget. Defaults to 1000.
+	MaxTokens uint32
+}
+
+// RetryBudget wraps a RetryStrategy and caps the number of retries to a percentage of the requests being made
+// using a token bucket. Every request deposits a fraction of a token and every retry withdraws a whole token,
+// retries are refused whilst the bucket is empty. This prevents retries from multiplying the load on the cluster
+// during an incident.
+// The budget is credited by every request that it could be asked to retry, whether it is set as the RetryStrategy on
+// ClusterOptions, on the options of individual operations, or within a PolicyRetryStrategy or another RetryBudget.
+// A RetryBudget wrapped by a custom RetryStrategy cannot be seen by the SDK and so is never credited, in that case
+// wrap the custom strategy in the budget instead.
+type RetryBudget struct {
+	strategy RetryStrategy
+
+	depositAmount float64
+	minPerSecond  float64
+	maxTokens     float64
+
+	lock       sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewRetryBudget returns a new RetryBudget wrapping the supplied strategy. If strategy is nil then a
+// BestEffortRetryStrategy will be used.
+func NewRetryBudget(strategy RetryStrategy, opts *RetryBudgetOptions) *RetryBudget {
+	if strategy == nil {
+		strategy = NewBestEffortRetryStrategy(nil)
+	}
+	if opts == nil {
+		opts = &RetryBudgetOptions{}
+	}
+
+	percent := opts.Percent
+	if percent <= 0 {
+		percent = 10
+	}
+	minPerSecond := opts.MinRetriesPerSecond
+	if minPerSecond == 0 {
+		minPerSecond = 10
+	}
+	maxTokens := opts.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 1000
+	}
+
+	return &RetryBudget{
+		strategy:      strategy,
+		depositAmount: percent / 100,
+		minPerSecond:  float64(minPerSecond),
+		maxTokens:     float64(maxTokens),
+		tokens:        float64(minPerSecond),
+		lastRefill:    time.Now(),
+	}
+}
+
+// RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
+func (rb *RetryBudget) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
+	action := rb.strategy.RetryAfter(req, reason)
+	if action == nil || action.Duration() == 0 {
+		return action
+	}
+
+	if !rb.withdraw() {
+		logDebugfWithFields(retryLogFields(req), "Retry budget exhausted, won't retry request. OperationID=%s. Reason=%s",
+			req.Identifier(), reason)
+		return &NoRetryRetryAction{}
+	}
+
+	return action
+}
+
+// Available returns the number of retries currently available in the budget.
+func (rb *RetryBudget) Available() uint32 {
+	rb.lock.Lock()
+	rb.refillLocked(time.Now())
+	tokens := rb.tokens
+	rb.lock.Unlock()
+
+	return uint32(tokens)
+}
+
+func (rb *RetryBudget) withdraw() bool {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	rb.refillLocked(time.Now())
+	if rb.tokens < 1 {
+		return false
+	}
+
+	rb.tokens--
+	return true
+}
+
+func (rb *RetryBudget) refillLocked(now time.Time) {
+	elapsed := now.Sub(rb.lastRefill)
+	if elapsed <= 0 {
+		return
+	}
+
+	rb.lastRefill = now
+	rb.tokens = math.Min(rb.maxTokens, rb.tokens+elapsed.Seconds()*rb.minPerSecond)
+}
+
+func (rb *RetryBudget) observeRequest() {
+	rb.observeRequests(1)
+}
+
+func (rb *RetryBudget) observeRequests(requests int) {
+	rb.lock.Lock()
+	rb.tokens = math.Min(rb.maxTokens, rb.tokens+float64(requests)*rb.depositAmount)
+	rb.lock.Unlock()
+}
+
+func (rb *RetryBudget) requiresService() bool {
+	return retryStrategyRequiresService(rb.strategy)
+}
+
+// retryBudgetsOf returns the RetryBudgets within strategy, looking through the strategies provided by the SDK which
+// delegate to other strategies.
+func retryBudgetsOf(strategy RetryStrategy) []*RetryBudget {
+	var budgets []*RetryBudget
+	var walk func(strategy RetryStrategy)
+	walk = func(strategy RetryStrategy) {
+		switch s := strategy.(type) {
+		case *RetryBudget:
+			for _, budget := range budgets {
+				if budget == s {
+					return
+				}
+			}
+			budgets = append(budgets, s)
+			walk(s.strategy)
+		case *PolicyRetryStrategy:
+			walk(s.Default)
+			for _, policy := range s.ReasonPolicies {
+				walk(policy)
+			}
+			for _, policy := range s.ServicePolicies {
+				walk(policy)
+			}
+		}
+	}
+	walk(strategy)
+
+	return budgets
+}
+
+// observeRetryBudgets credits each of the RetryBudgets within strategy with a request.
+func observeRetryBudgets(strategy RetryStrategy) {
+	switch strategy.(type) {
+	case *RetryBudget, *PolicyRetryStrategy:
+	default:
+		return
+	}
+
+	for _, budget := range retryBudgetsOf(strategy) {
+		budget.observeRequest()
+	}
+}
+
+// serviceAwareRetryStrategy is implemented by retry strategies which make decisions based on the service that
+// a request is for.
+type serviceAwareRetryStrategy interface {
+	requiresService() bool
+}
+
+func retryStrategyRequiresService(strategy RetryStrategy) bool {
+	if aware, ok := strategy.(serviceAwareRetryStrategy); ok {
+		return aware.requiresService()
+	}
+
+	return false
+}
+
+// serviceRetryRequest is implemented by retry requests which know the service that they are for.
+type serviceRetryRequest interface {
+	retryService() (ServiceType, bool)
+}
+
+// retryRequestService determines the service that a request is for, falling back to inferring it from the
+// reason when the request does not know.
+func retryRequestService(req RetryRequest, reason RetryReason) (ServiceType, bool) {
+	if svcReq, ok := req.(serviceRetryRequest); ok {
+		if service, ok := svcReq.retryService(); ok {
+			return service, true
+		}
+	}
+
+	switch reason {
+	case KVNotMyVBucketRetryReason, KVCollectionOutdatedRetryReason, KVErrMapRetryReason, KVLockedRetryReason,
+		KVTemporaryFailureRetryReason, KVSyncWriteInProgressRetryReason, KVSyncWriteRecommitInProgressRetryReason:
+		return ServiceTypeKeyValue, true
+	case QueryIndexNotFoundRetryReason, QueryPreparedStatementFailureRetryReason, QueryErrorRetryable:
+		return ServiceTypeQuery, true
+	case AnalyticsTemporaryFailureRetryReason:
+		return ServiceTypeAnalytics, true
+	case SearchTooManyRequestsRetryReason:
+		return ServiceTypeSearch, true
+	}
+
+	return 0, false
+}
+
+func serviceTypeFromMeterService(service string) (ServiceType, bool) {
+	switch service {
+	case meterValueServiceKV:
+		return ServiceTypeKeyValue, true
+	case meterValueServiceQuery:
+		return ServiceTypeQuery, true
+	case meterValueServiceAnalytics:
+		return ServiceTypeAnalytics, true
+	case meterValueServiceSearch:
+		return ServiceTypeSearch, true
+	case meterValueServiceViews:
+		return ServiceTypeViews, true
+	case meterValueServiceManagement:
+		return ServiceTypeManagement, true
+	}
+
+	return 0, false
+}
+
+func meterServiceFromServiceType(service ServiceType) string {
+	switch service {
+	case ServiceTypeKeyValue:
+		return meterValueServiceKV
+	case ServiceTypeQuery:
+		return meterValueServiceQuery
+	case ServiceTypeAnalytics:
+		return meterValueServiceAnalytics
+	case ServiceTypeSearch:
+		return meterValueServiceSearch
+	case ServiceTypeViews:
+		return meterValueServiceViews
+	}
+
+	return meterValueServiceManagement
+}
+
 type internalRetryRequest interface {
 	RetryAttempts() uint32
 	Identifier() string
@@ -162,12 +572,20 @@ type internalRetryRequest interface {
 	recordRetryAttempt(reason RetryReason)
 }
 
+func retryLogFields(req RetryRequest) []LogField {
+	return []LogField{
+		{Key: LogFieldComponent, Value: "retry"},
+		{Key: LogFieldOperationID, Value: req.Identifier()},
+	}
+}
+
 // retryOrchMaybeRetry will possibly retry an operation according to the strategy belonging to the request.
 // It will use the reason to determine whether or not the failure reason is one that can be retried.
 func retryOrchMaybeRetry(req internalRetryRequest, reason RetryReason) (bool, time.Time) {
 	if reason.AlwaysRetry() {
 		duration := gocbcore.ControlledBackoff(req.RetryAttempts())
-		logDebugf("Will retry request. Backoff=%s, OperationID=%s. Reason=%s", duration, req.Identifier(), reason)
+		logDebugfWithFields(retryLogFields(req), "Will retry request. Backoff=%s, OperationID=%s. Reason=%s", duration,
+			req.Identifier(), reason)
 
 		req.recordRetryAttempt(reason)
 
@@ -181,17 +599,18 @@ func retryOrchMaybeRetry(req internalRetryRequest, reason RetryReason) (bool, ti
 
 	action := retryStrategy.RetryAfter(req, reason)
 	if action == nil {
-		logDebugf("Won't retry request.  OperationID=%s. Reason=%s", req.Identifier(), reason)
+		logDebugfWithFields(retryLogFields(req), "Won't retry request.  OperationID=%s. Reason=%s", req.Identifier(), reason)
 		return false, time.Time{}
 	}
 
 	duration := action.Duration()
 	if duration == 0 {
-		logDebugf("Won't retry request.  OperationID=%s. Reason=%s", req.Identifier(), reason)
+		logDebugfWithFields(retryLogFields(req), "Won't retry request.  OperationID=%s. Reason=%s", req.Identifier(), reason)
 		return false, time.Time{}
 	}
 
-	logDebugf("Will retry request. Backoff=%s, OperationID=%s. Reason=%s", duration, req.Identifier(), reason)
+	logDebugfWithFields(retryLogFields(req), "Will retry request. Backoff=%s, OperationID=%s. Reason=%s", duration,
+		req.Identifier(), reason)
 	req.recordRetryAttempt(reason)
 
 	return true, time.Now().Add(duration)
@@ -210,26 +629,30 @@ type retriableRequestPs struct {
 	attempts uint32
 
 	operation        string
+	service          string
 	traceIdentifier  string
 	loggerIdentifier string
 	idempotent       bool
 	strategy         RetryStrategy
 	rootTraceContext RequestSpanContext
+	meter            *meterWrapper
 }
 
-func newRetriableRequestPS(operation string, idempotent bool, rootContext RequestSpanContext, traceIdentifier string,
-	strategy RetryStrategy) *retriableRequestPs {
+func newRetriableRequestPS(operation, service string, idempotent bool, rootContext RequestSpanContext,
+	traceIdentifier string, strategy RetryStrategy, meter *meterWrapper) *retriableRequestPs {
 	loggerIdentifier := traceIdentifier
 	if loggerIdentifier == "" {
 		loggerIdentifier = uuid.NewString()[:6]
 	}
 	return &retriableRequestPs{
 		operation:        operation,
+		service:          service,
 		traceIdentifier:  traceIdentifier,
 		loggerIdentifier: loggerIdentifier,
 		idempotent:       idempotent,
 		rootTraceContext: rootContext,
 		strategy:         strategy,
+		meter:            meter,
 	}
 }
 
@@ -253,12 +676,17 @@ func (w *retriableRequestPs) Operation() string {
 	return w.operation
 }
 
+func (w *retriableRequestPs) retryService() (ServiceType, bool) {
+	return serviceTypeFromMeterService(w.service)
+}
+
 func (w *retriableRequestPs) retryStrategy() RetryStrategy {
 	return w.strategy
 }
 
 func (w *retriableRequestPs) recordRetryAttempt(reason RetryReason) {
 	w.attempts++
+	w.meter.IncrementRetries(w.service, reason)
 	found := false
 	for i := 0; i < len(w.reasons); i++ {
 		if w.reasons[i] == reason {
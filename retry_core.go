@@ -1,6 +1,10 @@
 package gocb
 
-import "github.com/couchbase/gocbcore/v10"
+import (
+	"sync"
+
+	"github.com/couchbase/gocbcore/v10"
+)
 
 func translateCoreRetryReasons(reasons []gocbcore.RetryReason) []RetryReason {
 	var reasonsOut []RetryReason
@@ -18,7 +22,9 @@ func translateCoreRetryReasons(reasons []gocbcore.RetryReason) []RetryReason {
 }
 
 type wrappedCoreRetryRequest struct {
-	req gocbcore.RetryRequest
+	req     gocbcore.RetryRequest
+	service ServiceType
+	hasSvc  bool
 }
 
 func (req *wrappedCoreRetryRequest) RetryAttempts() uint32 {
@@ -37,21 +43,128 @@ func (req *wrappedCoreRetryRequest) RetryReasons() []RetryReason {
 	return translateCoreRetryReasons(req.req.RetryReasons())
 }
 
+func (req *wrappedCoreRetryRequest) retryService() (ServiceType, bool) {
+	return req.service, req.hasSvc
+}
+
 func newCoreRetryStrategyWrapper(strategy RetryStrategy) *coreRetryStrategyWrapper {
 	return &coreRetryStrategyWrapper{
 		wrapped: strategy,
+		budgets: retryBudgetsOf(strategy),
 	}
 }
 
 type coreRetryStrategyWrapper struct {
 	wrapped RetryStrategy
+	budgets []*RetryBudget
+	service ServiceType
+	hasSvc  bool
+	meter   *meterWrapper
+
+	forServiceLock  sync.RWMutex
+	forServiceCache map[forServiceKey]*coreRetryStrategyWrapper
+}
+
+// forServiceKey is used to look up wrappers previously created by forService.
+type forServiceKey struct {
+	service ServiceType
+	meter   *meterWrapper
+}
+
+// forService returns a wrapper which makes the service that requests are for available to the wrapped strategy, and
+// which counts retries against the meter. If the wrapped strategy does not make decisions based on service and there
+// is nothing to count retries against then the wrapper itself is returned.
+// forService is called once for each request, and so also credits any retry budgets with the request. The wrappers
+// that it creates are cached so that requests don't allocate one each.
+func (rs *coreRetryStrategyWrapper) forService(service ServiceType, meter *meterWrapper) *coreRetryStrategyWrapper {
+	return rs.forServiceRequests(service, meter, 1)
+}
+
+// forServiceRequests is the same as forService but credits any retry budgets with the given number of requests, for
+// when the returned wrapper is shared by several requests such as the operations of a bulk operation.
+func (rs *coreRetryStrategyWrapper) forServiceRequests(service ServiceType, meter *meterWrapper,
+	requests int) *coreRetryStrategyWrapper {
+	if rs != nil {
+		for _, budget := range rs.budgets {
+			budget.observeRequests(requests)
+		}
+	}
+
+	if meter != nil && meter.isNoopMeter {
+		meter = nil
+	}
+	if rs == nil || (!retryStrategyRequiresService(rs.wrapped) && meter == nil) {
+		return rs
+	}
+
+	key := forServiceKey{service: service, meter: meter}
+	rs.forServiceLock.RLock()
+	wrapper, ok := rs.forServiceCache[key]
+	rs.forServiceLock.RUnlock()
+	if ok {
+		return wrapper
+	}
+
+	wrapper = &coreRetryStrategyWrapper{
+		wrapped: rs.wrapped,
+		service: service,
+		hasSvc:  true,
+		meter:   meter,
+	}
+
+	// As with the meter attributes it doesn't matter if the wrapper for the same key is stored more than once.
+	rs.forServiceLock.Lock()
+	if rs.forServiceCache == nil {
+		rs.forServiceCache = make(map[forServiceKey]*coreRetryStrategyWrapper)
+	}
+	rs.forServiceCache[key] = wrapper
+	rs.forServiceLock.Unlock()
+
+	return wrapper
 }
 
 // RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
 func (rs *coreRetryStrategyWrapper) RetryAfter(req gocbcore.RetryRequest, reason gocbcore.RetryReason) gocbcore.RetryAction {
 	wreq := &wrappedCoreRetryRequest{
-		req: req,
+		req:     req,
+		service: rs.service,
+		hasSvc:  rs.hasSvc,
 	}
 	wrappedAction := rs.wrapped.RetryAfter(wreq, RetryReason(reason))
+	if rs.meter != nil && wrappedAction != nil && wrappedAction.Duration() > 0 {
+		rs.meter.IncrementRetries(meterServiceFromServiceType(rs.service), RetryReason(reason))
+	}
+
 	return gocbcore.RetryAction(wrappedAction)
 }
+
+// retryReasonRecorder wraps the retry strategy of a single request. It holds on to the request so that the reasons
+// that it was retried for are available once it has completed, whether or not it succeeded.
+type retryReasonRecorder struct {
+	wrapped *coreRetryStrategyWrapper
+
+	lock sync.Mutex
+	req  gocbcore.RetryRequest
+}
+
+// RetryAfter records the request and then calculates the RetryAction using the wrapped strategy.
+func (rr *retryReasonRecorder) RetryAfter(req gocbcore.RetryRequest, reason gocbcore.RetryReason) gocbcore.RetryAction {
+	rr.lock.Lock()
+	rr.req = req
+	rr.lock.Unlock()
+
+	return rr.wrapped.RetryAfter(req, reason)
+}
+
+// RetryReasons returns the reasons that the request has been retried for, if any.
+func (rr *retryReasonRecorder) RetryReasons() []RetryReason {
+	rr.lock.Lock()
+	req := rr.req
+	rr.lock.Unlock()
+
+	if req == nil {
+		return nil
+	}
+
+	return translateCoreRetryReasons(req.RetryReasons())
+}
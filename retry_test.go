@@ -1,25 +1,13 @@
 package gocb
 
 import (
+	"testing"
 	"time"
 
 	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
 )
 
-// failFastRetryStrategy represents a strategy that will never retry.
-type failFastRetryStrategy struct {
-}
-
-// newFailFastRetryStrategy returns a new FailFastRetryStrategy.
-func newFailFastRetryStrategy() *failFastRetryStrategy {
-	return &failFastRetryStrategy{}
-}
-
-// RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
-func (rs *failFastRetryStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
-	return &NoRetryRetryAction{}
-}
-
 type mockGocbcoreRequest struct {
 	attempts   uint32
 	identifier string
@@ -147,7 +135,7 @@ func (suite *UnitTestSuite) TestBestEffortRetryStrategy_RetryAfterDefaultCalcula
 }
 
 func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterNoRetry() {
-	strategy := newFailFastRetryStrategy()
+	strategy := NewFailFastRetryStrategy()
 	action := strategy.RetryAfter(&mockRetryRequest{}, RetryReason(gocbcore.UnknownRetryReason))
 	if action.Duration() != 0 {
 		suite.T().Fatalf("Expected duration to be %d but was %d", 0, action.Duration())
@@ -155,7 +143,7 @@ func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterNoRetry() {
 }
 
 func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterAlwaysRetry() {
-	strategy := newFailFastRetryStrategy()
+	strategy := NewFailFastRetryStrategy()
 	action := strategy.RetryAfter(&mockRetryRequest{}, RetryReason(gocbcore.KVCollectionOutdatedRetryReason))
 	if action.Duration() != 0 {
 		suite.T().Fatalf("Expected duration to be %d but was %d", 0, action.Duration())
@@ -163,9 +151,207 @@ func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterAlwaysRetry() {
 }
 
 func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterAllowsNonIdempotent() {
-	strategy := newFailFastRetryStrategy()
+	strategy := NewFailFastRetryStrategy()
 	action := strategy.RetryAfter(&mockRetryRequest{}, RetryReason(gocbcore.KVLockedRetryReason))
 	if action.Duration() != 0 {
 		suite.T().Fatalf("Expected duration to be %d but was %d", 0, action.Duration())
 	}
 }
+
+type mockServiceRetryRequest struct {
+	mockRetryRequest
+	service ServiceType
+}
+
+func (mgr *mockServiceRetryRequest) retryService() (ServiceType, bool) {
+	return mgr.service, true
+}
+
+func (suite *UnitTestSuite) TestExponentialJitterRetryStrategy_FullJitter() {
+	strategy := NewExponentialJitterRetryStrategy(10*time.Millisecond, 100*time.Millisecond, RetryJitterFull)
+
+	for attempts := uint32(0); attempts < 10; attempts++ {
+		action := strategy.RetryAfter(&mockRetryRequest{attempts: attempts}, KVLockedRetryReason)
+		suite.Assert().Greater(action.Duration(), time.Duration(0))
+		suite.Assert().LessOrEqual(action.Duration(), 100*time.Millisecond)
+	}
+
+	action := strategy.RetryAfter(&mockRetryRequest{}, UnknownRetryReason)
+	suite.Assert().Zero(action.Duration())
+}
+
+func (suite *UnitTestSuite) TestExponentialJitterRetryStrategy_DecorrelatedJitter() {
+	strategy := NewExponentialJitterRetryStrategy(10*time.Millisecond, 100*time.Millisecond, RetryJitterDecorrelated)
+
+	for attempts := uint32(0); attempts < 10; attempts++ {
+		action := strategy.RetryAfter(&mockRetryRequest{attempts: attempts, idempotent: true}, UnknownRetryReason)
+		suite.Assert().GreaterOrEqual(action.Duration(), 10*time.Millisecond)
+		suite.Assert().LessOrEqual(action.Duration(), 100*time.Millisecond)
+	}
+}
+
+func (suite *UnitTestSuite) TestPolicyRetryStrategy() {
+	reasonStrategy := &mockRetryStrategy{action: &WithDurationRetryAction{WithDuration: 1 * time.Millisecond}}
+	serviceStrategy := &mockRetryStrategy{action: &WithDurationRetryAction{WithDuration: 2 * time.Millisecond}}
+	defaultStrategy := &mockRetryStrategy{action: &WithDurationRetryAction{WithDuration: 3 * time.Millisecond}}
+
+	strategy := NewPolicyRetryStrategy(defaultStrategy).
+		WithReasonPolicy(NodeNotAvailableRetryReason, reasonStrategy).
+		WithServicePolicy(ServiceTypeQuery, serviceStrategy)
+
+	action := strategy.RetryAfter(&mockServiceRetryRequest{service: ServiceTypeQuery}, NodeNotAvailableRetryReason)
+	suite.Assert().Equal(1*time.Millisecond, action.Duration())
+
+	action = strategy.RetryAfter(&mockServiceRetryRequest{service: ServiceTypeQuery}, SocketNotAvailableRetryReason)
+	suite.Assert().Equal(2*time.Millisecond, action.Duration())
+
+	// The service is inferred from the reason when the request does not know it.
+	action = strategy.RetryAfter(&mockRetryRequest{}, QueryIndexNotFoundRetryReason)
+	suite.Assert().Equal(2*time.Millisecond, action.Duration())
+
+	action = strategy.RetryAfter(&mockServiceRetryRequest{service: ServiceTypeKeyValue}, SocketNotAvailableRetryReason)
+	suite.Assert().Equal(3*time.Millisecond, action.Duration())
+}
+
+func (suite *UnitTestSuite) TestPolicyRetryStrategy_CoreWrapperService() {
+	serviceStrategy := &mockRetryStrategy{action: &NoRetryRetryAction{}}
+	strategy := NewPolicyRetryStrategy(&mockRetryStrategy{action: &WithDurationRetryAction{WithDuration: 1}}).
+		WithServicePolicy(ServiceTypeKeyValue, serviceStrategy)
+
+	wrapper := newCoreRetryStrategyWrapper(strategy).forService(ServiceTypeKeyValue, nil)
+	wrapper.RetryAfter(&mockGocbcoreRequest{}, gocbcore.NodeNotAvailableRetryReason)
+	suite.Assert().True(serviceStrategy.retried)
+
+	// Strategies which don't need the service shouldn't cause a new wrapper to be created.
+	bestEffort := newCoreRetryStrategyWrapper(NewBestEffortRetryStrategy(nil))
+	suite.Assert().Same(bestEffort, bestEffort.forService(ServiceTypeKeyValue, nil))
+	suite.Assert().Same(bestEffort, bestEffort.forService(ServiceTypeKeyValue, newMeterWrapper(&NoopMeter{})))
+}
+
+func (suite *UnitTestSuite) TestCoreRetryStrategyWrapperCountsRetries() {
+	meter := newAggregatingMeter(nil)
+	wrapper := newCoreRetryStrategyWrapper(NewBestEffortRetryStrategy(mockBackoffCalculator)).
+		forService(ServiceTypeQuery, newMeterWrapper(meter))
+
+	wrapper.RetryAfter(&mockGocbcoreRequest{attempts: 1, idempotent: true}, gocbcore.ServiceNotAvailableRetryReason)
+	wrapper.RetryAfter(&mockGocbcoreRequest{attempts: 1, idempotent: true}, gocbcore.ServiceNotAvailableRetryReason)
+
+	counters := meter.counterGroup.Counters()
+	suite.Require().Len(counters, 1)
+	suite.Assert().Equal(meterNameCBRetries, counters[0].name)
+	suite.Assert().Equal(map[string]string{
+		meterAttribServiceKey:     meterValueServiceQuery,
+		meterAttribRetryReasonKey: gocbcore.ServiceNotAvailableRetryReason.Description(),
+	}, counters[0].tags)
+	suite.Assert().Equal(uint64(2), counters[0].Value())
+}
+
+func (suite *UnitTestSuite) TestRetryBudget() {
+	budget := NewRetryBudget(NewBestEffortRetryStrategy(mockBackoffCalculator), &RetryBudgetOptions{
+		Percent:             50,
+		MinRetriesPerSecond: 1,
+	})
+	// Prevent refills from the minimum rate interfering with the test.
+	budget.lastRefill = time.Now().Add(time.Hour)
+
+	req := &mockRetryRequest{attempts: 1, idempotent: true}
+	action := budget.RetryAfter(req, UnknownRetryReason)
+	suite.Assert().Equal(1*time.Millisecond, action.Duration())
+
+	action = budget.RetryAfter(req, UnknownRetryReason)
+	suite.Assert().Zero(action.Duration())
+
+	budget.observeRequest()
+	action = budget.RetryAfter(req, UnknownRetryReason)
+	suite.Assert().Zero(action.Duration())
+
+	budget.observeRequest()
+	action = budget.RetryAfter(req, UnknownRetryReason)
+	suite.Assert().Equal(1*time.Millisecond, action.Duration())
+}
+
+func (suite *UnitTestSuite) TestRetryBudgetObservesRequests() {
+	budget := NewRetryBudget(nil, &RetryBudgetOptions{MinRetriesPerSecond: 1})
+	budget.lastRefill = time.Now().Add(time.Hour)
+
+	// The budget is credited whether it is the strategy itself or nested within a policy, and whatever the meter.
+	wrapper := newCoreRetryStrategyWrapper(budget)
+	nested := newCoreRetryStrategyWrapper(NewPolicyRetryStrategy(nil).WithServicePolicy(ServiceTypeQuery, budget))
+	for i := 0; i < 5; i++ {
+		wrapper.forService(ServiceTypeKeyValue, nil)
+		nested.forService(ServiceTypeKeyValue, newMeterWrapper(&NoopMeter{}))
+	}
+
+	suite.Assert().Equal(uint32(2), budget.Available())
+}
+
+func (suite *UnitTestSuite) TestRetryBudgetObservesPerOperationRequests() {
+	budget := NewRetryBudget(nil, &RetryBudgetOptions{MinRetriesPerSecond: 1})
+	budget.lastRefill = time.Now().Add(time.Hour)
+	cluster := clusterFromOptions(ClusterOptions{
+		Meter:  &NoopMeter{},
+		Tracer: &NoopTracer{},
+	})
+	defer cluster.Close(nil)
+
+	for i := 0; i < 10; i++ {
+		m := &psOpManagerDefault{defaultRetryStrategy: cluster.retryStrategyWrapper.wrapped}
+		m.SetRetryStrategy(budget)
+		suite.Assert().Equal(budget, m.RetryStrategy())
+	}
+
+	suite.Assert().Equal(uint32(2), budget.Available())
+}
+
+func (suite *UnitTestSuite) TestRetryBudgetObservesBulkOperationRequests() {
+	budget := NewRetryBudget(nil, &RetryBudgetOptions{MinRetriesPerSecond: 1})
+	budget.lastRefill = time.Now().Add(time.Hour)
+
+	agent := new(mockKvProviderCoreProvider)
+	agent.On("Get", mock.AnythingOfType("gocbcore.GetOptions"), mock.AnythingOfType("gocbcore.GetCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.GetCallback)
+			cb(&gocbcore.GetResult{Value: []byte(`{}`)}, nil)
+		}).
+		Return(new(mockPendingOp), nil)
+
+	col := suite.collection("default", "_default", "_default", nil)
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{agent: agent}, nil
+	}
+
+	var ops []BulkOp
+	for i := 0; i < 10; i++ {
+		ops = append(ops, &GetOp{ID: "key"})
+	}
+	suite.Require().NoError(col.Do(ops, &BulkOpOptions{RetryStrategy: budget}))
+
+	// Each operation in the batch credits the budget, not the batch as a whole.
+	suite.Assert().Equal(uint32(2), budget.Available())
+}
+
+func (suite *UnitTestSuite) TestRetryBudgetsOf() {
+	budget := NewRetryBudget(nil, nil)
+	inner := NewRetryBudget(nil, nil)
+	strategy := NewPolicyRetryStrategy(NewRetryBudget(inner, nil)).
+		WithReasonPolicy(UnknownRetryReason, budget).
+		WithServicePolicy(ServiceTypeQuery, budget)
+
+	suite.Assert().Len(retryBudgetsOf(strategy), 3)
+	suite.Assert().Empty(retryBudgetsOf(NewBestEffortRetryStrategy(nil)))
+}
+
+func (suite *UnitTestSuite) TestRetryStrategyWrapperForServiceCached() {
+	wrapper := newCoreRetryStrategyWrapper(NewBestEffortRetryStrategy(nil))
+	meter := newMeterWrapper(newTestMeter())
+
+	kv := wrapper.forService(ServiceTypeKeyValue, meter)
+	suite.Assert().Same(kv, wrapper.forService(ServiceTypeKeyValue, meter))
+	suite.Assert().NotSame(kv, wrapper.forService(ServiceTypeQuery, meter))
+	suite.Assert().Equal(ServiceTypeQuery, wrapper.forService(ServiceTypeQuery, meter).service)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		wrapper.forService(ServiceTypeKeyValue, meter)
+	})
+	suite.Assert().Zero(allocs)
+}
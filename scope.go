@@ -11,6 +11,8 @@ type Scope struct {
 	retryStrategyWrapper *coreRetryStrategyWrapper
 	tracer               RequestTracer
 	meter                *meterWrapper
+	admission            *admissionController
+	interceptors         *operationInterceptors
 
 	useMutationTokens bool
 
@@ -33,6 +35,8 @@ func newScope(bucket *Bucket, scopeName string) *Scope {
 		retryStrategyWrapper: bucket.retryStrategyWrapper,
 		tracer:               bucket.tracer,
 		meter:                bucket.meter,
+		admission:            bucket.admission,
+		interceptors:         bucket.interceptors,
 
 		useMutationTokens: bucket.useMutationTokens,
 
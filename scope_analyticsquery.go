@@ -1,8 +1,7 @@
 package gocb
 
 import (
-	"fmt"
-	"time"
+	"context"
 )
 
 // AnalyticsQuery executes the analytics query statement on the server, constraining the query to the bucket and scope.
@@ -11,52 +10,35 @@ func (s *Scope) AnalyticsQuery(statement string, opts *AnalyticsOptions) (*Analy
 		opts = &AnalyticsOptions{}
 	}
 
-	start := time.Now()
-	defer s.meter.ValueRecord(meterValueServiceAnalytics, "analytics", start)
-
-	span := createSpan(s.tracer, opts.ParentSpan, "analytics", "analytics")
-	span.SetAttribute("db.statement", statement)
-	span.SetAttribute("db.name", s.BucketName())
-	span.SetAttribute("db.couchbase.scope", s.Name())
-	defer span.End()
-
-	timeout := opts.Timeout
-	if opts.Timeout == 0 {
-		timeout = s.timeoutsConfig.AnalyticsTimeout
-	}
-	deadline := time.Now().Add(timeout)
-
-	retryStrategy := s.retryStrategyWrapper
-	if opts.RetryStrategy != nil {
-		retryStrategy = newCoreRetryStrategyWrapper(opts.RetryStrategy)
-	}
-
-	queryOpts, err := opts.toMap()
-	if err != nil {
-		return nil, AnalyticsError{
-			InnerError:      wrapError(err, "failed to generate query options"),
-			Statement:       statement,
-			ClientContextID: opts.ClientContextID,
-		}
-	}
-
-	var priorityInt int32
-	if opts.Priority {
-		priorityInt = -1
+	info := &OperationInfo{
+		Service:    ServiceTypeAnalytics,
+		Operation:  "analytics",
+		BucketName: s.BucketName(),
+		ScopeName:  s.Name(),
+		Statement:  statement,
+		Options:    copyOptions(opts),
 	}
+	return interceptOperation(opts.Context, s.interceptors.all(), info,
+		func(ctx context.Context, info *OperationInfo) (*AnalyticsResult, error) {
+			opts, err := interceptedOptions[AnalyticsOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return s.analyticsQuery(statement, opts)
+		})
+}
 
-	queryOpts["statement"] = statement
-	queryOpts["query_context"] = fmt.Sprintf("default:`%s`.`%s`", s.BucketName(), s.Name())
-
+func (s *Scope) analyticsQuery(statement string, opts *AnalyticsOptions) (*AnalyticsResult, error) {
 	provider, err := s.getAnalyticsProvider()
 	if err != nil {
 		return nil, AnalyticsError{
 			InnerError:      wrapError(err, "failed to get query provider"),
 			Statement:       statement,
-			ClientContextID: maybeGetAnalyticsOption(queryOpts, "client_context_id"),
+			ClientContextID: opts.ClientContextID,
 		}
 	}
 
-	return execAnalyticsQuery(opts.Context, span, queryOpts, priorityInt, deadline, retryStrategy, provider, s.tracer,
-		opts.Internal.User)
+	return provider.AnalyticsQuery(statement, s, opts)
 }
@@ -1,11 +1,34 @@
 package gocb
 
+import "context"
+
 // Query executes the query statement on the server, constraining the query to the bucket and scope.
 func (s *Scope) Query(statement string, opts *QueryOptions) (*QueryResult, error) {
 	if opts == nil {
 		opts = &QueryOptions{}
 	}
 
+	info := &OperationInfo{
+		Service:    ServiceTypeQuery,
+		Operation:  "query",
+		BucketName: s.BucketName(),
+		ScopeName:  s.Name(),
+		Statement:  statement,
+		Options:    copyOptions(opts),
+	}
+	return interceptOperation(opts.Context, s.interceptors.all(), info,
+		func(ctx context.Context, info *OperationInfo) (*QueryResult, error) {
+			opts, err := interceptedOptions[QueryOptions](info)
+			if err != nil {
+				return nil, err
+			}
+			opts.Context = ctx
+
+			return s.query(statement, opts)
+		})
+}
+
+func (s *Scope) query(statement string, opts *QueryOptions) (*QueryResult, error) {
 	if opts.AsTransaction != nil {
 		return s.getTransactions().singleQuery(statement, s, *opts)
 	}
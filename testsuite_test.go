@@ -406,7 +406,7 @@ func (suite *UnitTestSuite) bucket(name string, timeouts TimeoutsConfig, cli *mo
 		transcoder:           NewJSONTranscoder(),
 		retryStrategyWrapper: newCoreRetryStrategyWrapper(NewBestEffortRetryStrategy(nil)),
 		tracer:               &NoopTracer{},
-		meter:                &meterWrapper{meter: &NoopMeter{}},
+		meter:                &meterWrapper{meter: &NoopMeter{}, isNoopMeter: true},
 		useServerDurations:   true,
 		useMutationTokens:    true,
 
@@ -463,7 +463,7 @@ func (suite *UnitTestSuite) collection(bucket, scope, collection string, provide
 		},
 		transcoder:           NewJSONTranscoder(),
 		tracer:               &NoopTracer{},
-		meter:                &meterWrapper{meter: &NoopMeter{}},
+		meter:                &meterWrapper{meter: &NoopMeter{}, isNoopMeter: true},
 		retryStrategyWrapper: newCoreRetryStrategyWrapper(NewBestEffortRetryStrategy(nil)),
 	}
 }
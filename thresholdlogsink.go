@@ -0,0 +1,74 @@
+package gocb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// ThresholdReportSink receives the reports generated by a ThresholdLoggingTracer.
+// VOLATILE: This API is subject to change at any time.
+type ThresholdReportSink interface {
+	Report(report ThresholdReport)
+}
+
+// ThresholdReportSinkFunc allows a function to be used as a ThresholdReportSink.
+// VOLATILE: This API is subject to change at any time.
+type ThresholdReportSinkFunc func(report ThresholdReport)
+
+// Report calls f(report).
+func (f ThresholdReportSinkFunc) Report(report ThresholdReport) {
+	f(report)
+}
+
+type writerThresholdReportSink struct {
+	lock   sync.Mutex
+	writer io.Writer
+}
+
+// NewWriterThresholdReportSink creates a ThresholdReportSink which writes each report to the writer as a line of JSON.
+// VOLATILE: This API is subject to change at any time.
+func NewWriterThresholdReportSink(w io.Writer) ThresholdReportSink {
+	return &writerThresholdReportSink{
+		writer: w,
+	}
+}
+
+func (s *writerThresholdReportSink) Report(report ThresholdReport) {
+	jsonBytes := &bytes.Buffer{}
+	encoder := json.NewEncoder(jsonBytes)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(report); err != nil {
+		logDebugf("Failed to generate threshold logging service JSON: %s", err)
+		return
+	}
+
+	s.lock.Lock()
+	_, err := s.writer.Write(jsonBytes.Bytes())
+	s.lock.Unlock()
+	if err != nil {
+		logDebugf("Failed to write threshold report: %s", err)
+	}
+}
+
+type channelThresholdReportSink struct {
+	ch chan<- ThresholdReport
+}
+
+// NewChannelThresholdReportSink creates a ThresholdReportSink which sends each report to the channel. Reports are
+// dropped, rather than blocking the tracer, if the channel is not ready to receive them.
+// VOLATILE: This API is subject to change at any time.
+func NewChannelThresholdReportSink(ch chan<- ThresholdReport) ThresholdReportSink {
+	return &channelThresholdReportSink{
+		ch: ch,
+	}
+}
+
+func (s *channelThresholdReportSink) Report(report ThresholdReport) {
+	select {
+	case s.ch <- report:
+	default:
+		logDebugf("Dropping threshold report as the channel is full")
+	}
+}
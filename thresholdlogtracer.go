@@ -1,7 +1,6 @@
 package gocb
 
 import (
-	"encoding/json"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -24,7 +23,11 @@ func initThresholdLogGroup(name string, floor time.Duration, size uint32) *thres
 }
 
 func (g *thresholdLogGroup) recordOp(span *thresholdLogSpan) {
-	if span.duration < g.floor {
+	g.recordOpWithFloor(span, g.floor)
+}
+
+func (g *thresholdLogGroup) recordOpWithFloor(span *thresholdLogSpan, floor time.Duration) {
+	if span.duration < floor {
 		return
 	}
 
@@ -70,26 +73,35 @@ func (g *thresholdLogGroup) recordOp(span *thresholdLogSpan) {
 	g.lock.Unlock()
 }
 
-type thresholdLogItem struct {
-	OperationName          string `json:"operation_name,omitempty"`
-	TotalTimeUs            uint64 `json:"total_duration_us,omitempty"`
-	EncodeDurationUs       uint64 `json:"encode_duration_us,omitempty"`
-	DispatchDurationUs     uint64 `json:"total_dispatch_duration_us,omitempty"`
-	ServerDurationUs       uint64 `json:"total_server_duration_us,omitempty"`
-	LastRemoteAddress      string `json:"last_remote_socket,omitempty"`
-	LastLocalAddress       string `json:"last_local_socket,omitempty"`
-	LastDispatchDurationUs uint64 `json:"last_dispatch_duration_us,omitempty"`
-	LastServerDurationUs   uint64 `json:"last_server_duration_us,omitempty"`
-	LastOperationID        string `json:"operation_id,omitempty"`
-	LastLocalID            string `json:"last_local_id,omitempty"`
+// ThresholdReportItem describes a single operation which exceeded its threshold.
+// VOLATILE: This API is subject to change at any time.
+type ThresholdReportItem struct {
+	OperationName          string   `json:"operation_name,omitempty"`
+	TotalTimeUs            uint64   `json:"total_duration_us,omitempty"`
+	EncodeDurationUs       uint64   `json:"encode_duration_us,omitempty"`
+	DispatchDurationUs     uint64   `json:"total_dispatch_duration_us,omitempty"`
+	ServerDurationUs       uint64   `json:"total_server_duration_us,omitempty"`
+	LastRemoteAddress      string   `json:"last_remote_socket,omitempty"`
+	LastLocalAddress       string   `json:"last_local_socket,omitempty"`
+	LastDispatchDurationUs uint64   `json:"last_dispatch_duration_us,omitempty"`
+	LastServerDurationUs   uint64   `json:"last_server_duration_us,omitempty"`
+	LastOperationID        string   `json:"operation_id,omitempty"`
+	LastLocalID            string   `json:"last_local_id,omitempty"`
+	DocumentID             string   `json:"document_id,omitempty"`
+	RetryAttempts          uint32   `json:"retry_attempts,omitempty"`
+	RetryReasons           []string `json:"retry_reasons,omitempty"`
 }
 
-type thresholdLogEntry struct {
-	Count uint64             `json:"total_count"`
-	Top   []thresholdLogItem `json:"top_requests"`
+// ThresholdReportEntry contains the slowest operations for a service which exceeded their thresholds.
+// VOLATILE: This API is subject to change at any time.
+type ThresholdReportEntry struct {
+	Count uint64                `json:"total_count"`
+	Top   []ThresholdReportItem `json:"top_requests"`
 }
 
-type thresholdLogService map[string]thresholdLogEntry
+// ThresholdReport is a report of the operations which exceeded their thresholds within an interval, keyed by service.
+// VOLATILE: This API is subject to change at any time.
+type ThresholdReport map[string]ThresholdReportEntry
 
 // ThresholdLoggingOptions is the set of options available for configuring threshold logging.
 type ThresholdLoggingOptions struct {
@@ -102,6 +114,15 @@ type ThresholdLoggingOptions struct {
 	SearchThreshold     time.Duration
 	AnalyticsThreshold  time.Duration
 	ManagementThreshold time.Duration
+
+	// OperationThresholds overrides the threshold of the service for operations with the given names, for example
+	// "get" or "upsert".
+	// VOLATILE: This API is subject to change at any time.
+	OperationThresholds map[string]time.Duration
+
+	// Sink receives each report, if not set then reports are logged.
+	// VOLATILE: This API is subject to change at any time.
+	Sink ThresholdReportSink
 }
 
 // ThresholdLoggingTracer is a specialized Tracer implementation which will automatically
@@ -118,6 +139,8 @@ type ThresholdLoggingTracer struct {
 	SearchThreshold     time.Duration
 	AnalyticsThreshold  time.Duration
 	ManagementThreshold time.Duration
+	OperationThresholds map[string]time.Duration
+	Sink                ThresholdReportSink
 
 	killCh   chan struct{}
 	refCount int32
@@ -167,6 +190,11 @@ func NewThresholdLoggingTracer(opts *ThresholdLoggingOptions) *ThresholdLoggingT
 		SearchThreshold:     opts.SearchThreshold,
 		AnalyticsThreshold:  opts.AnalyticsThreshold,
 		ManagementThreshold: opts.ManagementThreshold,
+		OperationThresholds: opts.OperationThresholds,
+		Sink:                opts.Sink,
+	}
+	if t.Sink == nil {
+		t.Sink = ThresholdReportSinkFunc(logThresholdReport)
 	}
 
 	t.groups = map[string]*thresholdLogGroup{
@@ -210,11 +238,11 @@ func (t *ThresholdLoggingTracer) DecRef() int32 {
 	return newRefCount
 }
 
-func (t *ThresholdLoggingTracer) buildJSONData() thresholdLogService {
+func (t *ThresholdLoggingTracer) buildJSONData() ThresholdReport {
 	// Preallocate space to copy the ops into...
 	oldOps := make([]*thresholdLogSpan, t.SampleSize)
 
-	jsonData := make(thresholdLogService)
+	jsonData := make(ThresholdReport)
 	for _, g := range t.groups {
 		g.lock.Lock()
 		// Escape early if we have no ops to log...
@@ -233,7 +261,7 @@ func (t *ThresholdLoggingTracer) buildJSONData() thresholdLogService {
 
 		g.lock.Unlock()
 
-		entry := thresholdLogEntry{}
+		entry := ThresholdReportEntry{}
 
 		for i := len(oldOps) - 1; i >= 0; i-- {
 			op := oldOps[i]
@@ -248,18 +276,26 @@ func (t *ThresholdLoggingTracer) buildJSONData() thresholdLogService {
 				peerAddr = peerAddr + ":" + op.lastDispatchPeerPort
 			}
 
-			entry.Top = append(entry.Top, thresholdLogItem{
+			var retryReasons []string
+			for _, reason := range op.retryReasons {
+				retryReasons = append(retryReasons, reason.Description())
+			}
+
+			entry.Top = append(entry.Top, ThresholdReportItem{
 				OperationName:          op.opName,
 				TotalTimeUs:            uint64(op.duration / time.Microsecond),
 				DispatchDurationUs:     uint64(op.totalDispatchDuration / time.Microsecond),
 				ServerDurationUs:       uint64(op.totalServerDuration / time.Microsecond),
 				EncodeDurationUs:       uint64(op.totalEncodeDuration / time.Microsecond),
-				LastLocalAddress:       localAddr,
-				LastRemoteAddress:      peerAddr,
+				LastLocalAddress:       redactSystemData(localAddr),
+				LastRemoteAddress:      redactSystemData(peerAddr),
 				LastDispatchDurationUs: uint64(op.lastDispatchDuration / time.Microsecond),
 				LastServerDurationUs:   uint64(op.lastServerDuration / time.Microsecond),
 				LastOperationID:        op.lastOperationID,
 				LastLocalID:            op.lastLocalID,
+				DocumentID:             redactUserData(op.documentID),
+				RetryAttempts:          op.retryAttempts,
+				RetryReasons:           retryReasons,
 			})
 		}
 
@@ -279,7 +315,11 @@ func (t *ThresholdLoggingTracer) logRecordedRecords() {
 		return
 	}
 
-	jsonBytes, err := json.Marshal(jsonData)
+	t.Sink.Report(jsonData)
+}
+
+func logThresholdReport(report ThresholdReport) {
+	jsonBytes, err := marshalErrorJSON(report)
 	if err != nil {
 		logDebugf("Failed to generate threshold logging service JSON: %s", err)
 	}
@@ -305,22 +345,32 @@ func (t *ThresholdLoggingTracer) loggerRoutine() {
 }
 
 func (t *ThresholdLoggingTracer) recordOp(span *thresholdLogSpan) {
+	var group *thresholdLogGroup
 	switch span.serviceName {
 	case "mgmt":
-		t.groups["management"].recordOp(span)
+		group = t.groups["management"]
 	case "kv":
-		t.groups["kv"].recordOp(span)
+		group = t.groups["kv"]
 	case "kv_scan":
-		t.groups["kv_scan"].recordOp(span)
+		group = t.groups["kv_scan"]
 	case "views":
-		t.groups["views"].recordOp(span)
+		group = t.groups["views"]
 	case "query":
-		t.groups["query"].recordOp(span)
+		group = t.groups["query"]
 	case "search":
-		t.groups["search"].recordOp(span)
+		group = t.groups["search"]
 	case "analytics":
-		t.groups["analytics"].recordOp(span)
+		group = t.groups["analytics"]
+	default:
+		return
 	}
+
+	if threshold, ok := t.OperationThresholds[span.opName]; ok {
+		group.recordOpWithFloor(span, threshold)
+		return
+	}
+
+	group.recordOp(span)
 }
 
 // RequestSpan belongs to the Tracer interface.
@@ -361,9 +411,27 @@ type thresholdLogSpan struct {
 	lastServerDuration    time.Duration
 	lastOperationID       string
 	lastLocalID           string
+	retryAttempts         uint32
+	documentID            string
+	retryReasons          []RetryReason
 	lock                  sync.Mutex
 }
 
+// setDocumentID records the ID of the document that the operation is for. The ID is not a span attribute so that
+// it isn't exposed to other tracers, it is only included in reports under the current redaction level.
+func (n *thresholdLogSpan) setDocumentID(id string) {
+	n.lock.Lock()
+	n.documentID = id
+	n.lock.Unlock()
+}
+
+// setRetryReasons records the reasons that the operation was retried for.
+func (n *thresholdLogSpan) setRetryReasons(reasons []RetryReason) {
+	n.lock.Lock()
+	n.retryReasons = reasons
+	n.lock.Unlock()
+}
+
 func (n *thresholdLogSpan) Context() RequestSpanContext {
 	return &thresholdLogSpanContext{n}
 }
@@ -404,6 +472,10 @@ func (n *thresholdLogSpan) SetAttribute(key string, value interface{}) {
 		if n.localPort, ok = value.(string); !ok {
 			logDebugf("Failed to cast span net.host.port tag")
 		}
+	case spanAttribNumRetries:
+		if n.retryAttempts, ok = value.(uint32); !ok {
+			logDebugf("Failed to cast span db.couchbase.retries tag")
+		}
 	}
 }
 
@@ -457,6 +529,9 @@ func (n *thresholdLogSpan) End() {
 		if n.lastDispatchPeerPort != "" {
 			n.parent.lastDispatchPeerPort = n.lastDispatchPeerPort
 		}
+		if n.retryAttempts > n.parent.retryAttempts {
+			n.parent.retryAttempts = n.retryAttempts
+		}
 		n.parent.lock.Unlock()
 	}
 
@@ -1,7 +1,9 @@
 package gocb
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"time"
 )
 
@@ -128,3 +130,123 @@ func (suite *UnitTestSuite) TestThresholdLogger() {
 		suite.Assert().NotZero(item.TotalTimeUs)
 	}
 }
+
+func (suite *UnitTestSuite) TestThresholdLoggerOperationThresholds() {
+	logger := NewThresholdLoggingTracer(&ThresholdLoggingOptions{
+		KVThreshold: 1,
+		OperationThresholds: map[string]time.Duration{
+			"get": time.Hour,
+		},
+	})
+
+	for _, opName := range []string{"get", "upsert"} {
+		span := logger.RequestSpan(context.Background(), opName)
+		span.SetAttribute(spanAttribServiceKey, "kv")
+		time.Sleep(50 * time.Microsecond)
+		span.End()
+	}
+
+	data := logger.buildJSONData()
+	suite.Require().Contains(data, "kv")
+	suite.Require().Len(data["kv"].Top, 1)
+	suite.Assert().Equal("upsert", data["kv"].Top[0].OperationName)
+}
+
+func (suite *UnitTestSuite) TestThresholdLoggerOperationDetails() {
+	SetLogRedactionLevel(RedactPartial)
+	defer SetLogRedactionLevel(RedactNone)
+
+	reports := make(chan ThresholdReport, 1)
+	logger := NewThresholdLoggingTracer(&ThresholdLoggingOptions{
+		KVThreshold: 1,
+		Sink:        NewChannelThresholdReportSink(reports),
+	})
+
+	span := logger.RequestSpan(context.Background(), "get")
+	span.SetAttribute(spanAttribServiceKey, "kv")
+	spanSetDocumentID(span, "mydoc")
+
+	dispatch := logger.RequestSpan(span.Context(), spanNameDispatchToServer)
+	dispatch.SetAttribute(spanAttribNumRetries, uint32(2))
+	time.Sleep(50 * time.Microsecond)
+	dispatch.End()
+
+	spanSetRetryReasons(span, []RetryReason{KVLockedRetryReason})
+	span.End()
+
+	logger.logRecordedRecords()
+
+	select {
+	case report := <-reports:
+		suite.Require().Contains(report, "kv")
+		suite.Require().Len(report["kv"].Top, 1)
+		item := report["kv"].Top[0]
+		suite.Assert().Equal("<ud>mydoc</ud>", item.DocumentID)
+		suite.Assert().Equal(uint32(2), item.RetryAttempts)
+		suite.Assert().Equal([]string{KVLockedRetryReason.Description()}, item.RetryReasons)
+	default:
+		suite.Fail("Expected a report to have been sent to the sink")
+	}
+
+	// Reports are dropped rather than blocking when the channel is full.
+	reports <- ThresholdReport{}
+	span = logger.RequestSpan(context.Background(), "get")
+	span.SetAttribute(spanAttribServiceKey, "kv")
+	time.Sleep(50 * time.Microsecond)
+	span.End()
+	logger.logRecordedRecords()
+	suite.Assert().Len(reports, 1)
+}
+
+func (suite *UnitTestSuite) TestThresholdLoggerRedactsAddresses() {
+	SetLogRedactionLevel(RedactFull)
+	defer SetLogRedactionLevel(RedactNone)
+
+	reports := make(chan ThresholdReport, 1)
+	logger := NewThresholdLoggingTracer(&ThresholdLoggingOptions{
+		KVThreshold: 1,
+		Sink:        NewChannelThresholdReportSink(reports),
+	})
+
+	span := logger.RequestSpan(context.Background(), "get")
+	span.SetAttribute(spanAttribServiceKey, "kv")
+
+	dispatch := logger.RequestSpan(span.Context(), spanNameDispatchToServer)
+	dispatch.SetAttribute(spanAttribNetHostNameKey, "10.0.0.9")
+	dispatch.SetAttribute(spanAttribNetHostPortKey, "50000")
+	dispatch.SetAttribute(spanAttribNetPeerNameKey, "10.0.0.1")
+	dispatch.SetAttribute(spanAttribNetPeerPortKey, "11210")
+	time.Sleep(50 * time.Microsecond)
+	dispatch.End()
+	span.End()
+
+	logger.logRecordedRecords()
+
+	select {
+	case report := <-reports:
+		suite.Require().Contains(report, "kv")
+		suite.Require().Len(report["kv"].Top, 1)
+		item := report["kv"].Top[0]
+		suite.Assert().Equal("<sd>10.0.0.9:50000</sd>", item.LastLocalAddress)
+		suite.Assert().Equal("<sd>10.0.0.1:11210</sd>", item.LastRemoteAddress)
+	default:
+		suite.Fail("Expected a report to have been sent to the sink")
+	}
+}
+
+func (suite *UnitTestSuite) TestThresholdReportWriterSink() {
+	buf := &bytes.Buffer{}
+	sink := NewWriterThresholdReportSink(buf)
+
+	sink.Report(ThresholdReport{
+		"kv": ThresholdReportEntry{
+			Count: 1,
+			Top:   []ThresholdReportItem{{OperationName: "get", TotalTimeUs: 1000}},
+		},
+	})
+
+	var report ThresholdReport
+	suite.Require().NoError(json.Unmarshal(buf.Bytes(), &report))
+	suite.Assert().Equal(uint64(1), report["kv"].Count)
+	suite.Assert().Equal("get", report["kv"].Top[0].OperationName)
+}
@@ -67,7 +67,7 @@ func (span *coreRequestSpanWrapper) Context() gocbcore.RequestSpanContext {
 }
 
 func (span *coreRequestSpanWrapper) SetAttribute(key string, value interface{}) {
-	span.span.SetAttribute(key, value)
+	span.span.SetAttribute(key, redactSpanAttribute(key, value))
 }
 
 func (span *coreRequestSpanWrapper) AddEvent(key string, timestamp time.Time) {
@@ -108,6 +108,24 @@ func (span noopSpan) SetAttribute(key string, value interface{}) {
 func (span noopSpan) AddEvent(key string, timestamp time.Time) {
 }
 
+// redactSpanAttribute applies the log redaction level to span attributes which can contain user or system data, as
+// spans are commonly exported to third party systems.
+func redactSpanAttribute(key string, value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	switch key {
+	case spanAttribDBStatementKey:
+		return redactUserData(str)
+	case spanAttribNetHostNameKey, spanAttribNetPeerNameKey:
+		return redactSystemData(str)
+	}
+
+	return value
+}
+
 func createSpan(tracer RequestTracer, parent RequestSpan, operationType, service string) RequestSpan {
 	var tracectx RequestSpanContext
 	if parent != nil {
@@ -122,3 +140,25 @@ func createSpan(tracer RequestTracer, parent RequestSpan, operationType, service
 
 	return span
 }
+
+// operationDetailsSpan is implemented by SDK spans which record details about an operation that aren't exposed to
+// tracers as span attributes.
+type operationDetailsSpan interface {
+	setDocumentID(id string)
+	setRetryReasons(reasons []RetryReason)
+}
+
+func spanSetDocumentID(span RequestSpan, id string) {
+	if dSpan, ok := span.(operationDetailsSpan); ok {
+		dSpan.setDocumentID(id)
+	}
+}
+
+func spanSetRetryReasons(span RequestSpan, reasons []RetryReason) {
+	if len(reasons) == 0 {
+		return
+	}
+	if dSpan, ok := span.(operationDetailsSpan); ok {
+		dSpan.setRetryReasons(reasons)
+	}
+}
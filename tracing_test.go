@@ -311,3 +311,23 @@ func (suite *IntegrationTestSuite) AssertHTTPDispatchSpan(span *testSpan, operat
 	}
 	suite.Assert().Equal(spans, len(span.Tags))
 }
+
+func (suite *UnitTestSuite) TestSpanAttributeRedaction() {
+	tracer := newTestTracer()
+	span := &coreRequestSpanWrapper{span: tracer.RequestSpan(nil, "dispatch_to_server")}
+
+	SetLogRedactionLevel(RedactPartial)
+	span.SetAttribute(spanAttribDBStatementKey, "SELECT 1")
+	span.SetAttribute(spanAttribNetPeerNameKey, "10.112.210.101")
+	span.SetAttribute(spanAttribNetPeerPortKey, "11210")
+
+	SetLogRedactionLevel(RedactFull)
+	span.SetAttribute(spanAttribNetHostNameKey, "10.112.210.1")
+	SetLogRedactionLevel(RedactNone)
+
+	tags := tracer.Spans[nil][0].Tags
+	suite.Assert().Equal("<ud>SELECT 1</ud>", tags[spanAttribDBStatementKey])
+	suite.Assert().Equal("10.112.210.101", tags[spanAttribNetPeerNameKey])
+	suite.Assert().Equal("11210", tags[spanAttribNetPeerPortKey])
+	suite.Assert().Equal("<sd>10.112.210.1</sd>", tags[spanAttribNetHostNameKey])
+}
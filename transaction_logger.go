@@ -100,7 +100,11 @@ func (tl *transactionLogger) Log(level gocbcore.LogLevel, offset int, txnID, att
 	tl.lock.Unlock()
 
 	if level <= gocbcore.LogWarn {
-		logExf(LogLevel(level), offset, txnID+"/"+attemptID+" "+fmt, args...)
+		logExfWithFields(LogLevel(level), offset, []LogField{
+			{Key: LogFieldComponent, Value: "transactions"},
+			{Key: LogFieldTransactionID, Value: txnID},
+			{Key: LogFieldTransactionAttemptID, Value: attemptID},
+		}, txnID+"/"+attemptID+" "+fmt, args...)
 	}
 
 	return nil
@@ -26,11 +26,15 @@ type Transactions struct {
 	cleanupCollections  []gocbcore.TransactionLostATRLocation
 }
 
+var errTransactionsNotSupportedPs = wrapError(ErrFeatureNotAvailable,
+	"transactions are not currently supported against the couchbase2 protocol")
+
 // initTransactions will initialize the transactions library and return a Transactions
 // object which can be used to perform transactions.
 func (c *Cluster) initTransactions(config TransactionsConfig) (*Transactions, error) {
 	// TODO: protostellar doesn't support transactions.
-	// so we'll just bail early.
+	// The transactions service is not exposed by gocbcoreps and the gocbcore transactions implementation requires
+	// xattr macro expansion on the KV connection, which couchbase2 doesn't provide, so we'll just bail early.
 	if c.cSpec.Scheme == "couchbase2" {
 		return &Transactions{
 			unsupported: true,
@@ -156,7 +160,7 @@ func (c *Cluster) initTransactions(config TransactionsConfig) (*Transactions, er
 // singular transaction.
 func (t *Transactions) Run(logicFn AttemptFunc, perConfig *TransactionOptions) (*TransactionResult, error) {
 	if t.unsupported {
-		return nil, wrapError(ErrFeatureNotAvailable, "transactions are not currently supported against  the couchbase2 protocol")
+		return nil, errTransactionsNotSupportedPs
 	}
 	return t.run(logicFn, perConfig, false)
 }
@@ -388,7 +392,7 @@ func (t *Transactions) atrLocationsProvider() ([]gocbcore.TransactionLostATRLoca
 
 func (t *Transactions) singleQuery(statement string, scope *Scope, opts QueryOptions) (*QueryResult, error) {
 	if t.unsupported {
-		return nil, wrapError(ErrFeatureNotAvailable, "transactions are not currently supported against  the couchbase2 protocol")
+		return nil, errTransactionsNotSupportedPs
 	}
 	if opts.Context != nil {
 		return nil, makeInvalidArgumentsError("cannot use context and transactions together")
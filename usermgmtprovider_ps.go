@@ -0,0 +1,47 @@
+package gocb
+
+// userManagementProviderPs is used when connected with couchbase2, which has no user management service.
+type userManagementProviderPs struct {
+}
+
+var _ userManagementProvider = &userManagementProviderPs{}
+
+func (um *userManagementProviderPs) GetAllUsers(*GetAllUsersOptions) ([]UserAndMetadata, error) {
+	return nil, makePsNotSupportedError("GetAllUsers")
+}
+
+func (um *userManagementProviderPs) GetUser(string, *GetUserOptions) (*UserAndMetadata, error) {
+	return nil, makePsNotSupportedError("GetUser")
+}
+
+func (um *userManagementProviderPs) UpsertUser(User, *UpsertUserOptions) error {
+	return makePsNotSupportedError("UpsertUser")
+}
+
+func (um *userManagementProviderPs) DropUser(string, *DropUserOptions) error {
+	return makePsNotSupportedError("DropUser")
+}
+
+func (um *userManagementProviderPs) GetRoles(*GetRolesOptions) ([]RoleAndDescription, error) {
+	return nil, makePsNotSupportedError("GetRoles")
+}
+
+func (um *userManagementProviderPs) GetGroup(string, *GetGroupOptions) (*Group, error) {
+	return nil, makePsNotSupportedError("GetGroup")
+}
+
+func (um *userManagementProviderPs) GetAllGroups(*GetAllGroupsOptions) ([]Group, error) {
+	return nil, makePsNotSupportedError("GetAllGroups")
+}
+
+func (um *userManagementProviderPs) UpsertGroup(Group, *UpsertGroupOptions) error {
+	return makePsNotSupportedError("UpsertGroup")
+}
+
+func (um *userManagementProviderPs) DropGroup(string, *DropGroupOptions) error {
+	return makePsNotSupportedError("DropGroup")
+}
+
+func (um *userManagementProviderPs) ChangePassword(string, *ChangePasswordOptions) error {
+	return makePsNotSupportedError("ChangePassword")
+}